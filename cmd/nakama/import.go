@@ -0,0 +1,207 @@
+package main
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"compress/gzip"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/go-kit/log"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	cockroachpkg "github.com/nakamauwu/nakama/cockroach"
+	"github.com/nakamauwu/nakama/minio"
+	"github.com/nakamauwu/nakama/service"
+)
+
+// runImport imports the posts from a Mastodon export or a Twitter archive
+// into the account of the given user.
+func runImport(ctx context.Context, logger log.Logger, args []string) error {
+	var (
+		dbURL       = env("DATABASE_URL", "postgresql://root@127.0.0.1:26257/nakama?sslmode=disable")
+		s3Endpoint  = env("S3_ENDPOINT", "localhost:9000")
+		s3AccessKey = env("S3_ACCESS_KEY", "minioadmin")
+		s3SecretKey = env("S3_SECRET_KEY", "minioadmin")
+		s3Secure, _ = strconv.ParseBool(env("S3_SECURE", "false"))
+		username    string
+	)
+
+	fs := flag.NewFlagSet("nakama import", flag.ExitOnError)
+	fs.Usage = func() {
+		fmt.Println("Usage: nakama import -user <username> <archive>")
+		fmt.Println("\nThe archive can be a Mastodon export (.tar.gz, extracted directory or outbox.json)")
+		fmt.Println("or a Twitter archive (.zip).")
+		fmt.Println()
+		fs.PrintDefaults()
+	}
+	fs.StringVar(&username, "user", username, "Username of the account to import into")
+	fs.StringVar(&dbURL, "db", dbURL, "Database URL")
+	if err := fs.Parse(args); err != nil {
+		return fmt.Errorf("could not parse flags: %w", err)
+	}
+
+	if username == "" || fs.NArg() != 1 {
+		fs.Usage()
+		return errors.New("missing username or archive")
+	}
+
+	archive, closeArchive, err := openArchive(fs.Arg(0))
+	if err != nil {
+		return err
+	}
+
+	defer closeArchive()
+
+	db, err := pgxpool.New(ctx, dbURL)
+	if err != nil {
+		return fmt.Errorf("could not open db connection: %w", err)
+	}
+
+	defer db.Close()
+
+	if err = db.Ping(ctx); err != nil {
+		return fmt.Errorf("could not ping to db: %w", err)
+	}
+
+	cockroach := cockroachpkg.New(db)
+
+	minioStore := minio.NewStore(minio.StoreOptions{
+		Endpoint:  s3Endpoint,
+		AccessKey: s3AccessKey,
+		SecretKey: s3SecretKey,
+		Secure:    s3Secure,
+	})
+
+	svc := &service.Service{
+		Logger:     logger,
+		Cockroach:  cockroach,
+		MinioStore: minioStore,
+	}
+
+	userID, err := cockroach.UserIDFromUsername(ctx, username)
+	if err != nil {
+		return fmt.Errorf("could not find user %q: %w", username, err)
+	}
+
+	ctx = context.WithValue(ctx, service.KeyAuthUserID, userID)
+
+	report, err := svc.ImportArchive(ctx, archive)
+	if err != nil {
+		return fmt.Errorf("could not import archive: %w", err)
+	}
+
+	for _, skipped := range report.Skipped {
+		fmt.Printf("skipped %s: %s\n", skipped.SourceID, skipped.Reason)
+	}
+
+	fmt.Printf("imported %d posts from %s, %d skipped\n", report.Imported, report.Source, len(report.Skipped))
+
+	return nil
+}
+
+// openArchive opens a Twitter archive zip, a Mastodon export tarball,
+// an already extracted directory or the outbox.json file inside of it.
+func openArchive(name string) (fs.FS, func() error, error) {
+	info, err := os.Stat(name)
+	if err != nil {
+		return nil, nil, fmt.Errorf("could not open archive: %w", err)
+	}
+
+	noop := func() error { return nil }
+
+	switch {
+	case info.IsDir():
+		return os.DirFS(name), noop, nil
+	case filepath.Base(name) == "outbox.json":
+		return os.DirFS(filepath.Dir(name)), noop, nil
+	case strings.HasSuffix(name, ".zip"):
+		r, err := zip.OpenReader(name)
+		if err != nil {
+			return nil, nil, fmt.Errorf("could not open zip archive: %w", err)
+		}
+
+		return r, r.Close, nil
+	case strings.HasSuffix(name, ".tar.gz"), strings.HasSuffix(name, ".tgz"):
+		dir, err := os.MkdirTemp("", "nakama-import-*")
+		if err != nil {
+			return nil, nil, fmt.Errorf("could not create temp dir: %w", err)
+		}
+
+		cleanup := func() error { return os.RemoveAll(dir) }
+
+		if err := extractTarGz(name, dir); err != nil {
+			_ = cleanup()
+			return nil, nil, err
+		}
+
+		return os.DirFS(dir), cleanup, nil
+	default:
+		return nil, nil, errors.New("unsupported archive format")
+	}
+}
+
+// extractTarGz extracts the regular files of the tarball into dir.
+func extractTarGz(name, dir string) error {
+	f, err := os.Open(name)
+	if err != nil {
+		return fmt.Errorf("could not open tar archive: %w", err)
+	}
+
+	defer f.Close()
+
+	gz, err := gzip.NewReader(f)
+	if err != nil {
+		return fmt.Errorf("could not read gzip archive: %w", err)
+	}
+
+	defer gz.Close()
+
+	root, err := os.OpenRoot(dir)
+	if err != nil {
+		return fmt.Errorf("could not open extraction dir: %w", err)
+	}
+
+	defer root.Close()
+
+	tr := tar.NewReader(gz)
+	for {
+		hdr, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+
+		if err != nil {
+			return fmt.Errorf("could not read tar archive: %w", err)
+		}
+
+		if hdr.Typeflag != tar.TypeReg || !filepath.IsLocal(hdr.Name) {
+			continue
+		}
+
+		if err := root.MkdirAll(filepath.Dir(hdr.Name), 0o755); err != nil {
+			return fmt.Errorf("could not create dir for %s: %w", hdr.Name, err)
+		}
+
+		out, err := root.Create(hdr.Name)
+		if err != nil {
+			return fmt.Errorf("could not create %s: %w", hdr.Name, err)
+		}
+
+		_, err = io.Copy(out, tr)
+		if errClose := out.Close(); err == nil {
+			err = errClose
+		}
+		if err != nil {
+			return fmt.Errorf("could not extract %s: %w", hdr.Name, err)
+		}
+	}
+}
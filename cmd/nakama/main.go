@@ -32,6 +32,7 @@ import (
 	natspubsub "github.com/nakamauwu/nakama/pubsub/nats"
 	"github.com/nakamauwu/nakama/service"
 	httptransport "github.com/nakamauwu/nakama/transport/http"
+	"github.com/nakamauwu/nakama/unfurl"
 )
 
 func main() {
@@ -50,6 +51,10 @@ func main() {
 }
 
 func run(ctx context.Context, logger log.Logger, args []string) error {
+	if len(args) != 0 && args[0] == "import" {
+		return runImport(ctx, logger, args[1:])
+	}
+
 	var (
 		port, _             = strconv.Atoi(env("PORT", "3000"))
 		originStr           = env("ORIGIN", fmt.Sprintf("http://localhost:%d", port))
@@ -77,6 +82,10 @@ func run(ctx context.Context, logger log.Logger, args []string) error {
 		allowedOrigins      = env("ALLOWED_ORIGINS", originStr)
 		vapidPrivateKey     = os.Getenv("VAPID_PRIVATE_KEY")
 		vapidPublicKey      = os.Getenv("VAPID_PUBLIC_KEY")
+		proxyKey            = os.Getenv("PROXY_KEY")
+		clientIPHeader      = os.Getenv("CLIENT_IP_HEADER")
+		adminUserIDs        = os.Getenv("ADMIN_USER_IDS")
+		deletionGraceStr    = env("ACCOUNT_DELETION_GRACE_PERIOD", service.DefaultAccountDeletionGracePeriod.String())
 	)
 
 	if objectsBaseURL == "" {
@@ -87,10 +96,16 @@ func run(ctx context.Context, logger log.Logger, args []string) error {
 		}
 	}
 
+	deletionGrace, err := time.ParseDuration(deletionGraceStr)
+	if err != nil {
+		return fmt.Errorf("invalid account deletion grace period: %w", err)
+	}
+
 	fs := flag.NewFlagSet("nakama", flag.ExitOnError)
 	fs.Usage = func() {
 		fs.PrintDefaults()
 		fmt.Println("\nDon't forget to set TOKEN_KEY, and RESEND_API_KEY or SMTP_USERNAME and SMTP_PASSWORD for real usage.")
+		fmt.Println("\nRun \"nakama import -h\" to import posts from a Mastodon or Twitter archive.")
 	}
 	fs.IntVar(&port, "port", port, "Port in which this server will run")
 	fs.StringVar(&originStr, "origin", originStr, "URL origin for this service")
@@ -105,10 +120,17 @@ func run(ctx context.Context, logger log.Logger, args []string) error {
 	fs.StringVar(&googleClientID, "google-client-id", googleClientID, "Google client ID")
 	fs.BoolVar(&disabledDevLogin, "disable-dev-login", disabledDevLogin, "Disable development login endpoint")
 	fs.StringVar(&allowedOrigins, "allowed-origins", allowedOrigins, "Comma separated list of allowed origins")
+	fs.StringVar(&clientIPHeader, "client-ip-header", clientIPHeader, "Header with the client IP address set by a trusted reverse proxy, like X-Forwarded-For")
+	fs.StringVar(&adminUserIDs, "admin-user-ids", adminUserIDs, "Comma separated list of admin user IDs")
+	fs.DurationVar(&deletionGrace, "account-deletion-grace-period", deletionGrace, "Time before a deactivated account gets permanently deleted")
 	if err := fs.Parse(args); err != nil {
 		return fmt.Errorf("could not parse flags: %w", err)
 	}
 
+	if deletionGrace <= 0 {
+		return errors.New("account deletion grace period must be positive")
+	}
+
 	origin, err := url.Parse(originStr)
 	if err != nil || !origin.IsAbs() {
 		return errors.New("invalid url origin")
@@ -182,7 +204,11 @@ func run(ctx context.Context, logger log.Logger, args []string) error {
 		Secure:    s3Secure,
 	})
 
-	if err := minioStore.CreateReadOnlyBuckets(ctx, service.AvatarsBucket, service.CoversBucket, service.MediaBucket); err != nil {
+	if err := minioStore.CreateReadOnlyBuckets(ctx, service.AvatarsBucket, service.CoversBucket, service.MediaBucket, service.EmojisBucket); err != nil {
+		return err
+	}
+
+	if err := minioStore.CreatePrivateBucket(ctx, service.ExportsBucket); err != nil {
 		return err
 	}
 
@@ -241,10 +267,24 @@ func run(ctx context.Context, logger log.Logger, args []string) error {
 		AllowedOrigins:   strings.Split(allowedOrigins, ","),
 		VAPIDPrivateKey:  vapidPrivateKey,
 		VAPIDPublicKey:   vapidPublicKey,
+		Unfurler:         unfurl.New(nil),
+		ProxyKey:         []byte(proxyKey),
+		AdminUserIDs:     strings.FieldsFunc(adminUserIDs, func(r rune) bool { return r == ',' }),
+
+		AccountDeletionGracePeriod: deletionGrace,
 	}
 
+	go svc.RunBackgroundJobs(ctx)
+
 	sessStore := pgxstore.New(db)
-	h := httptransport.New(svc, sessStore, origin, log.With(logger, "component", "http"), promHandler, embedStaticFiles)
+
+	go func() {
+		if err := httptransport.TrackSessions(ctx, svc, sessStore); err != nil {
+			_ = logger.Log("error", fmt.Errorf("could not track user sessions: %w", err))
+		}
+	}()
+
+	h := httptransport.New(svc, sessStore, origin, log.With(logger, "component", "http"), promHandler, embedStaticFiles, clientIPHeader)
 	server := &http.Server{
 		Addr:              fmt.Sprintf(":%d", port),
 		Handler:           h,
@@ -0,0 +1,351 @@
+package cockroach
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgxutil"
+	"github.com/nakamauwu/nakama/types"
+	"github.com/nicolasparada/go-errs"
+)
+
+// sqlUserActive filters out users with a pending account deletion.
+const sqlUserActive = "users.delete_after IS NULL"
+
+// RequestAccountDeletion deactivates the user until deleteAfter
+// and revokes all of their sessions.
+func (c *Cockroach) RequestAccountDeletion(ctx context.Context, userID string, deleteAfter time.Time) error {
+	return c.db.RunTx(ctx, func(ctx context.Context) error {
+		const query = `
+			UPDATE users SET delete_after = @delete_after
+			WHERE id = @user_id AND delete_after IS NULL
+		`
+		args := pgx.StrictNamedArgs{
+			"user_id":      userID,
+			"delete_after": deleteAfter,
+		}
+		tag, err := c.db.Exec(ctx, query, args)
+		if err != nil {
+			return fmt.Errorf("sql update user delete after: %w", err)
+		}
+
+		if tag.RowsAffected() == 0 {
+			return errs.NotFoundError("user not found")
+		}
+
+		return c.deleteUserSessions(ctx, userID)
+	})
+}
+
+// CancelAccountDeletion reactivates the user, if it was pending deletion.
+func (c *Cockroach) CancelAccountDeletion(ctx context.Context, userID string) error {
+	const query = `
+		UPDATE users SET delete_after = NULL
+		WHERE id = @user_id AND delete_after IS NOT NULL
+	`
+	args := pgx.StrictNamedArgs{"user_id": userID}
+	_, err := c.db.Exec(ctx, query, args)
+	if err != nil {
+		return fmt.Errorf("sql cancel account deletion: %w", err)
+	}
+
+	return nil
+}
+
+// DueAccountDeletions returns the IDs of the users whose grace period is over.
+func (c *Cockroach) DueAccountDeletions(ctx context.Context, limit uint) ([]string, error) {
+	const query = `
+		SELECT id
+		FROM users
+		WHERE delete_after IS NOT NULL AND delete_after <= now()
+		ORDER BY delete_after
+		LIMIT @limit
+	`
+	args := pgx.StrictNamedArgs{"limit": limit}
+
+	userIDs, err := pgxutil.Select(ctx, c.db, query, []any{args}, pgx.RowTo[string])
+	if err != nil {
+		return nil, fmt.Errorf("sql select due account deletions: %w", err)
+	}
+
+	return userIDs, nil
+}
+
+// DeleteUser permanently once the grace period is over.
+// Everything the user owns is removed by the ON DELETE CASCADE constraints,
+// while the objects in storage are returned for the caller to remove.
+func (c *Cockroach) DeleteUser(ctx context.Context, userID string) (types.DeletedUser, error) {
+	var out types.DeletedUser
+	return out, c.db.RunTx(ctx, func(ctx context.Context) error {
+		args := pgx.StrictNamedArgs{"user_id": userID}
+
+		const userQuery = `
+			SELECT avatar, cover
+			FROM users
+			WHERE id = @user_id AND delete_after <= now()
+			FOR UPDATE
+		`
+		err := c.db.QueryRow(ctx, userQuery, args).Scan(&out.Avatar, &out.Cover)
+		if errors.Is(err, pgx.ErrNoRows) {
+			return errs.NotFoundError("user not found")
+		}
+
+		if err != nil {
+			return fmt.Errorf("sql select deleted user objects: %w", err)
+		}
+
+		out.Media, err = c.userMedia(ctx, userID)
+		if err != nil {
+			return err
+		}
+
+		// the cascade removes the rows, so the archives must be collected before.
+		const dataExportsQuery = `
+			SELECT object_key
+			FROM data_exports
+			WHERE user_id = @user_id AND object_key IS NOT NULL
+		`
+		out.DataExports, err = pgxutil.Select(ctx, c.db, dataExportsQuery, []any{args}, pgx.RowTo[string])
+		if err != nil {
+			return fmt.Errorf("sql select deleted user data exports: %w", err)
+		}
+
+		// counts are not kept up to date by the cascade.
+		const followersCountQuery = `
+			UPDATE users SET followers_count = followers_count - 1
+			WHERE id IN (SELECT followee_id FROM follows WHERE follower_id = @user_id)
+		`
+		if _, err := c.db.Exec(ctx, followersCountQuery, args); err != nil {
+			return fmt.Errorf("sql decrease followers count of deleted user followees: %w", err)
+		}
+
+		const followeesCountQuery = `
+			UPDATE users SET followees_count = followees_count - 1
+			WHERE id IN (SELECT follower_id FROM follows WHERE followee_id = @user_id)
+		`
+		if _, err := c.db.Exec(ctx, followeesCountQuery, args); err != nil {
+			return fmt.Errorf("sql decrease followees count of deleted user followers: %w", err)
+		}
+
+		if err := c.decreaseDeletedUserCounts(ctx, userID); err != nil {
+			return err
+		}
+
+		if err := c.deleteUserSessions(ctx, userID); err != nil {
+			return err
+		}
+
+		if _, err := c.db.Exec(ctx, "DELETE FROM users WHERE id = @user_id", args); err != nil {
+			return fmt.Errorf("sql delete user: %w", err)
+		}
+
+		return nil
+	})
+}
+
+// decreaseDeletedUserCounts repairs the counters and reaction aggregates
+// of what other users own, that the cascade would leave stale.
+// Things owned by the deleted user don't matter as they go away too.
+func (c *Cockroach) decreaseDeletedUserCounts(ctx context.Context, userID string) error {
+	args := pgx.StrictNamedArgs{"user_id": userID}
+
+	// replies to the user comments go away with them.
+	const commentsCountQuery = `
+		WITH RECURSIVE thread AS (
+			SELECT id, post_id FROM comments WHERE user_id = @user_id
+			UNION ALL
+			SELECT comments.id, comments.post_id FROM comments INNER JOIN thread ON comments.parent_id = thread.id
+		), counts AS (
+			SELECT post_id, count(DISTINCT id) AS n FROM thread GROUP BY post_id
+		)
+		UPDATE posts SET comments_count = greatest(posts.comments_count - counts.n, 0)
+		FROM counts
+		WHERE posts.id = counts.post_id AND posts.user_id != @user_id
+	`
+	if _, err := c.db.Exec(ctx, commentsCountQuery, args); err != nil {
+		return fmt.Errorf("sql decrease comments count of posts commented by deleted user: %w", err)
+	}
+
+	const repliesCountQuery = `
+		UPDATE comments SET replies_count = greatest(comments.replies_count - counts.n, 0)
+		FROM (
+			SELECT parent_id, count(*) AS n
+			FROM comments
+			WHERE user_id = @user_id AND parent_id IS NOT NULL
+			GROUP BY parent_id
+		) AS counts
+		WHERE comments.id = counts.parent_id AND comments.user_id != @user_id
+	`
+	if _, err := c.db.Exec(ctx, repliesCountQuery, args); err != nil {
+		return fmt.Errorf("sql decrease replies count of comments replied by deleted user: %w", err)
+	}
+
+	const repostsCountQuery = `
+		UPDATE posts SET reposts_count = greatest(posts.reposts_count - counts.n, 0)
+		FROM (
+			SELECT repost_of_id, count(*) AS n
+			FROM posts
+			WHERE user_id = @user_id AND repost_of_id IS NOT NULL
+			GROUP BY repost_of_id
+		) AS counts
+		WHERE posts.id = counts.repost_of_id AND posts.user_id != @user_id
+	`
+	if _, err := c.db.Exec(ctx, repostsCountQuery, args); err != nil {
+		return fmt.Errorf("sql decrease reposts count of posts reposted by deleted user: %w", err)
+	}
+
+	const votesCountQuery = `
+		UPDATE poll_options SET votes_count = greatest(votes_count - 1, 0)
+		WHERE id IN (SELECT option_id FROM poll_votes WHERE user_id = @user_id)
+	`
+	if _, err := c.db.Exec(ctx, votesCountQuery, args); err != nil {
+		return fmt.Errorf("sql decrease votes count of poll options voted by deleted user: %w", err)
+	}
+
+	const votersCountQuery = `
+		UPDATE polls SET voters_count = greatest(voters_count - 1, 0)
+		WHERE post_id IN (SELECT post_id FROM poll_votes WHERE user_id = @user_id)
+	`
+	if _, err := c.db.Exec(ctx, votersCountQuery, args); err != nil {
+		return fmt.Errorf("sql decrease voters count of polls voted by deleted user: %w", err)
+	}
+
+	// reactions are deleted upfront so the aggregates can be refreshed without them.
+	const postReactionsQuery = `
+		DELETE FROM post_reactions
+		WHERE user_id = @user_id
+		RETURNING post_id
+	`
+	postIDs, err := pgxutil.Select(ctx, c.db, postReactionsQuery, []any{args}, pgx.RowTo[string])
+	if err != nil {
+		return fmt.Errorf("sql delete post reactions of deleted user: %w", err)
+	}
+
+	slices.Sort(postIDs)
+	for _, postID := range slices.Compact(postIDs) {
+		if err := c.refreshPostReactions(ctx, postID); err != nil {
+			return err
+		}
+	}
+
+	const commentReactionsQuery = `
+		DELETE FROM comment_reactions
+		WHERE user_id = @user_id
+		RETURNING comment_id
+	`
+	commentIDs, err := pgxutil.Select(ctx, c.db, commentReactionsQuery, []any{args}, pgx.RowTo[string])
+	if err != nil {
+		return fmt.Errorf("sql delete comment reactions of deleted user: %w", err)
+	}
+
+	slices.Sort(commentIDs)
+	for _, commentID := range slices.Compact(commentIDs) {
+		if err := c.refreshCommentReactions(ctx, commentID); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// AddUserSession keeps track of the session token of the user.
+func (c *Cockroach) AddUserSession(ctx context.Context, userID, token string) error {
+	const query = `
+		UPSERT INTO user_sessions (token, user_id)
+		VALUES (@token, @user_id)
+	`
+	args := pgx.StrictNamedArgs{
+		"token":   token,
+		"user_id": userID,
+	}
+	_, err := c.db.Exec(ctx, query, args)
+	if err != nil {
+		return fmt.Errorf("sql upsert user session: %w", err)
+	}
+
+	return nil
+}
+
+// TrackUserSession is like [Cockroach.AddUserSession] but for sessions
+// started before they were tracked. Sessions of deactivated or deleted users
+// get revoked instead.
+func (c *Cockroach) TrackUserSession(ctx context.Context, userID, token string) error {
+	return c.db.RunTx(ctx, func(ctx context.Context) error {
+		const query = "SELECT EXISTS (SELECT 1 FROM users WHERE id = @user_id AND " + sqlUserActive + ")"
+		args := pgx.StrictNamedArgs{"user_id": userID}
+		active, err := pgxutil.SelectRow(ctx, c.db, query, []any{args}, pgx.RowTo[bool])
+		if err != nil {
+			return fmt.Errorf("sql select user active: %w", err)
+		}
+
+		if active {
+			return c.AddUserSession(ctx, userID, token)
+		}
+
+		_, err = c.db.Exec(ctx, "DELETE FROM sessions WHERE token = @token", pgx.StrictNamedArgs{"token": token})
+		if err != nil {
+			return fmt.Errorf("sql delete inactive user session: %w", err)
+		}
+
+		return nil
+	})
+}
+
+func (c *Cockroach) deleteUserSessions(ctx context.Context, userID string) error {
+	const query = `
+		DELETE FROM sessions
+		WHERE token IN (SELECT token FROM user_sessions WHERE user_id = @user_id)
+	`
+	args := pgx.StrictNamedArgs{"user_id": userID}
+	if _, err := c.db.Exec(ctx, query, args); err != nil {
+		return fmt.Errorf("sql delete sessions: %w", err)
+	}
+
+	if _, err := c.db.Exec(ctx, "DELETE FROM user_sessions WHERE user_id = @user_id", args); err != nil {
+		return fmt.Errorf("sql delete user sessions: %w", err)
+	}
+
+	return nil
+}
+
+// DeleteStaleUserSessions cleans up the tracked tokens
+// of sessions that already expired or were logged out.
+func (c *Cockroach) DeleteStaleUserSessions(ctx context.Context) error {
+	const query = `
+		DELETE FROM user_sessions
+		WHERE NOT EXISTS (SELECT 1 FROM sessions WHERE sessions.token = user_sessions.token)
+		-- sessions are saved at the end of the login request, give them some room.
+		AND created_at < now() - INTERVAL '1 hour'
+	`
+	_, err := c.db.Exec(ctx, query)
+	if err != nil {
+		return fmt.Errorf("sql delete stale user sessions: %w", err)
+	}
+
+	return nil
+}
+
+// userMedia returns the media uploaded by the user in posts and drafts.
+func (c *Cockroach) userMedia(ctx context.Context, userID string) ([]types.Media, error) {
+	const query = `
+		SELECT media FROM posts WHERE user_id = @user_id AND media IS NOT NULL
+		UNION ALL
+		SELECT media FROM drafts WHERE user_id = @user_id AND media IS NOT NULL
+	`
+	args := pgx.StrictNamedArgs{"user_id": userID}
+	media, err := pgxutil.Select(ctx, c.db, query, []any{args}, pgx.RowTo[[]types.Media])
+	if err != nil {
+		return nil, fmt.Errorf("sql select user media: %w", err)
+	}
+
+	var out []types.Media
+	for _, m := range media {
+		out = append(out, m...)
+	}
+
+	return out, nil
+}
@@ -0,0 +1,161 @@
+package cockroach
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgxutil"
+	"github.com/nakamauwu/nakama/types"
+	"github.com/nicolasparada/go-db"
+	"github.com/nicolasparada/go-errs"
+)
+
+// sqlNoBlockBetween filters out pairs of users where either one blocked the other.
+// Both arguments are SQL expressions, like a column or a query arg.
+func sqlNoBlockBetween(userID, otherUserID string) string {
+	return fmt.Sprintf(`NOT EXISTS (
+		SELECT 1 FROM blocks
+		WHERE (blocks.blocker_id = %[1]s AND blocks.blocked_id = %[2]s)
+		OR (blocks.blocker_id = %[2]s AND blocks.blocked_id = %[1]s)
+	)`, userID, otherUserID)
+}
+
+// Block the given user. Follows and follow requests between the two users,
+// in any direction, get removed along the way.
+func (c *Cockroach) Block(ctx context.Context, blockerID, blockedID string) error {
+	return c.db.RunTx(ctx, func(ctx context.Context) error {
+		const query = `
+			INSERT INTO blocks (blocker_id, blocked_id)
+			VALUES (@blocker_id, @blocked_id)
+			ON CONFLICT (blocker_id, blocked_id) DO NOTHING
+		`
+		args := pgx.StrictNamedArgs{
+			"blocker_id": blockerID,
+			"blocked_id": blockedID,
+		}
+		_, err := c.db.Exec(ctx, query, args)
+		if db.IsForeignKeyViolationError(err, "blocked_id") {
+			return errs.NotFoundError("user not found")
+		}
+
+		if err != nil {
+			return fmt.Errorf("sql insert block: %w", err)
+		}
+
+		if err := c.deleteFollowRequestsBetween(ctx, blockerID, blockedID); err != nil {
+			return err
+		}
+
+		for _, pair := range [][2]string{{blockerID, blockedID}, {blockedID, blockerID}} {
+			followExists, err := c.followExists(ctx, pair[0], pair[1])
+			if err != nil {
+				return err
+			}
+
+			if !followExists {
+				continue
+			}
+
+			if _, err := c.unfollow(ctx, pair[0], pair[1]); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+func (c *Cockroach) Unblock(ctx context.Context, blockerID, blockedID string) error {
+	const query = `
+		DELETE FROM blocks
+		WHERE blocker_id = @blocker_id AND blocked_id = @blocked_id
+	`
+	args := pgx.StrictNamedArgs{
+		"blocker_id": blockerID,
+		"blocked_id": blockedID,
+	}
+	_, err := c.db.Exec(ctx, query, args)
+	if err != nil {
+		return fmt.Errorf("sql delete block: %w", err)
+	}
+
+	return nil
+}
+
+// BlockExists reports whether either user blocked the other.
+func (c *Cockroach) BlockExists(ctx context.Context, userID, otherUserID string) (bool, error) {
+	query := fmt.Sprintf("SELECT NOT %s", sqlNoBlockBetween("@user_id", "@other_user_id"))
+	args := pgx.StrictNamedArgs{
+		"user_id":       userID,
+		"other_user_id": otherUserID,
+	}
+	exists, err := pgxutil.SelectRow(ctx, c.db, query, []any{args}, pgx.RowTo[bool])
+	if err != nil {
+		return false, fmt.Errorf("sql select block exists: %w", err)
+	}
+
+	return exists, nil
+}
+
+// Blocks lists the users blocked by the given user.
+func (c *Cockroach) Blocks(ctx context.Context, in types.ListBlocks) (types.Page[types.UserProfile], error) {
+	var out types.Page[types.UserProfile]
+
+	args := pgx.StrictNamedArgs{"viewer_id": in.UserID()}
+	selects := []string{sqlUserProfileCols}
+	joins := []string{
+		`INNER JOIN users ON users.id = blocks.blocked_id`,
+	}
+	filters := []string{
+		`blocks.blocker_id = @viewer_id`,
+	}
+
+	selects, joins = appendViewerRelationshipFields(selects, joins)
+
+	pageArgs, err := ParsePageArgs[any](in.PageArgs)
+	if err != nil {
+		return out, err
+	}
+
+	if pageArgs.After != nil {
+		filters = append(filters, "users.username < @after_username")
+		args["after_username"] = pageArgs.After.ID // Cursor ID is the username in this case
+	} else if pageArgs.Before != nil {
+		filters = append(filters, "users.username > @before_username")
+		args["before_username"] = pageArgs.Before.ID // Cursor ID is the username in this case
+	}
+
+	var order, limit string
+	if pageArgs.IsBackwards() {
+		order = "ORDER BY users.username ASC, users.id ASC"
+		limit = fmt.Sprintf("LIMIT %d", or(pageArgs.Last, defaultPageSize)+1) // +1 to check if there's a next page
+	} else {
+		order = "ORDER BY users.username DESC, users.id DESC"
+		limit = fmt.Sprintf("LIMIT %d", or(pageArgs.First, defaultPageSize)+1) // +1 to check if there's a next page
+	}
+
+	query := fmt.Sprintf(`
+		SELECT %s
+		FROM blocks
+		%s
+		WHERE %s
+		%s
+		%s`,
+		strings.Join(selects, ",\n\t\t"),
+		strings.Join(joins, "\n\t\t"),
+		strings.Join(filters, " AND "),
+		order,
+		limit,
+	)
+
+	users, err := pgxutil.Select(ctx, c.db, query, []any{args}, pgx.RowToStructByNameLax[types.UserProfile])
+	if err != nil {
+		return out, fmt.Errorf("sql select blocks: %w", err)
+	}
+
+	out.Items = users
+
+	return out, applyPageInfo(&out, pageArgs, userProfileCursor)
+}
@@ -0,0 +1,155 @@
+package cockroach
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgxutil"
+	"github.com/nakamauwu/nakama/types"
+	"github.com/nicolasparada/go-db"
+	"github.com/nicolasparada/go-errs"
+)
+
+func (c *Cockroach) ToggleBookmark(ctx context.Context, in types.ToggleBookmark) (types.ToggledBookmark, error) {
+	var out types.ToggledBookmark
+	return out, c.db.RunTx(ctx, func(ctx context.Context) error {
+		deleted, err := c.deleteBookmark(ctx, in)
+		if err != nil {
+			return err
+		}
+
+		if deleted {
+			out.Bookmarked = false
+			return nil
+		}
+
+		if err := c.createBookmark(ctx, in); err != nil {
+			return err
+		}
+
+		out.Bookmarked = true
+
+		return nil
+	})
+}
+
+func (c *Cockroach) deleteBookmark(ctx context.Context, in types.ToggleBookmark) (bool, error) {
+	const query = `
+		DELETE FROM bookmarks
+		WHERE user_id = @user_id AND post_id = @post_id
+	`
+	args := pgx.StrictNamedArgs{
+		"user_id": in.UserID(),
+		"post_id": in.PostID,
+	}
+	tag, err := c.db.Exec(ctx, query, args)
+	if err != nil {
+		return false, fmt.Errorf("sql delete bookmark: %w", err)
+	}
+
+	return tag.RowsAffected() > 0, nil
+}
+
+func (c *Cockroach) createBookmark(ctx context.Context, in types.ToggleBookmark) error {
+	const query = `
+		INSERT INTO bookmarks (user_id, post_id)
+		VALUES (@user_id, @post_id)
+	`
+	args := pgx.StrictNamedArgs{
+		"user_id": in.UserID(),
+		"post_id": in.PostID,
+	}
+	_, err := c.db.Exec(ctx, query, args)
+	if db.IsForeignKeyViolationError(err, "post_id") {
+		return errs.NotFoundError("post not found")
+	}
+
+	if err != nil {
+		return fmt.Errorf("sql insert bookmark: %w", err)
+	}
+
+	return nil
+}
+
+// Bookmarks from the given user, most recently bookmarked first.
+func (c *Cockroach) Bookmarks(ctx context.Context, in types.ListBookmarks) (types.Page[types.Bookmark], error) {
+	var out types.Page[types.Bookmark]
+
+	args := pgx.StrictNamedArgs{
+		"viewer_id": in.UserID(),
+	}
+	selects := []string{
+		`bookmarks.created_at AS bookmarked_at`,
+		sqlPostCols,
+		sqlUserJSONB,
+		sqlSelectRepostOf,
+		sqlSelectLinkPreview,
+		sqlSelectPoll(args, new(in.UserID())),
+		`(posts.user_id = @viewer_id) AS mine`,
+		`(post_subscriptions.user_id IS NOT NULL) AS subscribed`,
+		`true AS bookmarked`,
+		sqlSelectPostsReactions}
+	joins := []string{
+		"INNER JOIN posts ON bookmarks.post_id = posts.id",
+		"INNER JOIN users ON posts.user_id = users.id",
+		sqlJoinRepostOf(args, new(in.UserID())),
+		`LEFT JOIN post_subscriptions ON post_subscriptions.post_id = posts.id AND post_subscriptions.user_id = @viewer_id`,
+		sqlJoinPostReactions(args, in.UserID())}
+	filters := []string{
+		"bookmarks.user_id = @viewer_id",
+		sqlPostVisible(args, new(in.UserID())),
+		sqlNoBlockBetween("@viewer_id", "posts.user_id"),
+	}
+
+	pageArgs, err := ParsePageArgs[time.Time](in.PageArgs)
+	if err != nil {
+		return out, err
+	}
+
+	if pageArgs.After != nil {
+		filters = append(filters, "(bookmarks.created_at, bookmarks.post_id) < (@after_created_at, @after_id)")
+		args["after_created_at"] = pageArgs.After.Value
+		args["after_id"] = pageArgs.After.ID
+	} else if pageArgs.Before != nil {
+		filters = append(filters, "(bookmarks.created_at, bookmarks.post_id) > (@before_created_at, @before_id)")
+		args["before_created_at"] = pageArgs.Before.Value
+		args["before_id"] = pageArgs.Before.ID
+	}
+
+	var order, limit string
+	if pageArgs.IsBackwards() {
+		order = "ORDER BY bookmarks.created_at ASC, bookmarks.post_id ASC"
+		limit = fmt.Sprintf("LIMIT %d", or(pageArgs.Last, defaultPageSize)+1) // +1 to check if there's a next page
+	} else {
+		order = "ORDER BY bookmarks.created_at DESC, bookmarks.post_id DESC"
+		limit = fmt.Sprintf("LIMIT %d", or(pageArgs.First, defaultPageSize)+1) // +1 to check if there's a next page
+	}
+
+	query := fmt.Sprintf(`
+		SELECT %s
+		FROM bookmarks
+		%s
+		WHERE %s
+		%s
+		%s`,
+		strings.Join(selects, ",\n\t\t"),
+		strings.Join(joins, "\n\t\t"),
+		strings.Join(filters, " AND "),
+		order,
+		limit,
+	)
+
+	bookmarks, err := pgxutil.Select(ctx, c.db, query, []any{args}, pgx.RowToStructByNameLax[types.Bookmark])
+	if err != nil {
+		return out, fmt.Errorf("sql select bookmarks: %w", err)
+	}
+
+	out.Items = bookmarks
+
+	return out, applyPageInfo(&out, pageArgs, func(b types.Bookmark) Cursor[time.Time] {
+		return Cursor[time.Time]{ID: b.Post.ID, Value: b.BookmarkedAt}
+	})
+}
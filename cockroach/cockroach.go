@@ -27,6 +27,11 @@ func (c *Cockroach) Migrate(ctx context.Context) error {
 	if err != nil {
 		return fmt.Errorf("sql exec migration: %w", err)
 	}
+
+	if err := c.migrateTags(ctx); err != nil {
+		return fmt.Errorf("migrate tags: %w", err)
+	}
+
 	return nil
 }
 
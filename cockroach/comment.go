@@ -18,7 +18,12 @@ const sqlCommentCols = `
 	  comments.id
 	, comments.user_id
 	, comments.post_id
+	, comments.parent_id
+	, comments.depth
 	, comments.content
+	, comments.entities
+	, comments.replies_count
+	, comments.edited_at IS NOT NULL AS edited
 	, comments.created_at
 `
 
@@ -39,7 +44,8 @@ const sqlSelectCommentPreview = `
 //
 //	[
 //		{ "kind": "emoji", "reaction": "❤️", "count": 3, "reacted": true },
-//		{ "kind": "emoji", "reaction": "😂", "count": 2, "reacted": false }
+//		{ "kind": "emoji", "reaction": "😂", "count": 2, "reacted": false },
+//		{ "kind": "custom", "reaction": "blobcat", "count": 1, "imageURL": "blobcat.png", "reacted": false }
 //	]
 const sqlSelectCommentsReactions = `
 	CASE WHEN comments.reactions IS NULL THEN NULL
@@ -72,11 +78,22 @@ const sqlJoinCommentReactions = `
 		GROUP BY comment_reactions.comment_id
 	) AS user_reactions ON user_reactions.comment_id = comments.id`
 
-func (c *Cockroach) CreateComment(ctx context.Context, in types.CreateComment) (types.Created, error) {
-	var out types.Created
+func (c *Cockroach) CreateComment(ctx context.Context, in types.CreateComment) (types.CreatedComment, error) {
+	var out types.CreatedComment
 
 	return out, c.db.RunTx(ctx, func(ctx context.Context) error {
-		created, err := c.createComment(ctx, in)
+		var parentID *string
+		var depth int
+		if in.ParentID != nil {
+			parent, err := c.replyParent(ctx, in.PostID, *in.ParentID)
+			if err != nil {
+				return err
+			}
+
+			parentID, depth = &parent.ID, parent.Depth+1
+		}
+
+		created, err := c.createComment(ctx, in, parentID, depth)
 		if err != nil {
 			return err
 		}
@@ -97,25 +114,72 @@ func (c *Cockroach) CreateComment(ctx context.Context, in types.CreateComment) (
 			return err
 		}
 
+		if parentID != nil {
+			if err := c.increaseCommentRepliesCount(ctx, *parentID); err != nil {
+				return err
+			}
+		}
+
 		out = created
 
 		return nil
 	})
 }
 
-func (c *Cockroach) createComment(ctx context.Context, in types.CreateComment) (types.Created, error) {
-	var out types.Created
+type commentParent struct {
+	ID     string `db:"id"`
+	PostID string `db:"post_id"`
+	Depth  int    `db:"depth"`
+}
 
+// replyParent returns the comment a reply ends up attached to.
+// Replies to a comment at [types.CommentMaxDepth] are attached
+// to its parent instead, so threads don't nest any deeper.
+func (c *Cockroach) replyParent(ctx context.Context, postID, parentID string) (commentParent, error) {
 	const query = `
-		INSERT INTO comments (user_id, post_id, content) VALUES (@user_id, @post_id, @content)
-		RETURNING id, created_at
+		SELECT
+			  CASE WHEN depth >= @max_depth THEN parent_id ELSE id END AS id
+			, post_id
+			, LEAST(depth, @max_depth - 1) AS depth
+		FROM comments
+		WHERE id = @parent_id
 	`
 	args := pgx.StrictNamedArgs{
-		"user_id": in.UserID(),
-		"post_id": in.PostID,
-		"content": in.Content,
+		"parent_id": parentID,
+		"max_depth": types.CommentMaxDepth,
 	}
-	out, err := pgxutil.SelectRow(ctx, c.db, query, []any{args}, pgx.RowToStructByNameLax[types.Created])
+	parent, err := pgxutil.SelectRow(ctx, c.db, query, []any{args}, pgx.RowToStructByNameLax[commentParent])
+	if db.IsNotFoundError(err) {
+		return parent, errs.NotFoundError("parent comment not found")
+	}
+
+	if err != nil {
+		return parent, fmt.Errorf("sql select reply parent: %w", err)
+	}
+
+	if parent.PostID != postID {
+		return parent, errs.InvalidArgumentError("parent comment belongs to another post")
+	}
+
+	return parent, nil
+}
+
+func (c *Cockroach) createComment(ctx context.Context, in types.CreateComment, parentID *string, depth int) (types.CreatedComment, error) {
+	var out types.CreatedComment
+
+	const query = `
+		INSERT INTO comments (user_id, post_id, parent_id, depth, content, entities) VALUES (@user_id, @post_id, @parent_id, @depth, @content, @entities)
+		RETURNING id, parent_id, depth, created_at
+	`
+	args := pgx.StrictNamedArgs{
+		"user_id":   in.UserID(),
+		"post_id":   in.PostID,
+		"parent_id": parentID,
+		"depth":     depth,
+		"content":   in.Content,
+		"entities":  in.Entities(),
+	}
+	out, err := pgxutil.SelectRow(ctx, c.db, query, []any{args}, pgx.RowToStructByNameLax[types.CreatedComment])
 	if db.IsForeignKeyViolationError(err) {
 		return out, errs.NotFoundError("post not found")
 	}
@@ -127,23 +191,40 @@ func (c *Cockroach) createComment(ctx context.Context, in types.CreateComment) (
 	return out, nil
 }
 
+// Comments at the top level of a post.
+// Use [Cockroach.CommentReplies] to get the replies of each one.
 func (c *Cockroach) Comments(ctx context.Context, in types.ListComments) (types.Page[types.Comment], error) {
+	args := pgx.StrictNamedArgs{"post_id": in.PostID}
+	filters := []string{"comments.post_id = @post_id", "comments.parent_id IS NULL"}
+	return c.comments(ctx, args, filters, in.ViewerID(), in.PageArgs)
+}
+
+func (c *Cockroach) CommentReplies(ctx context.Context, in types.ListCommentReplies) (types.Page[types.Comment], error) {
+	args := pgx.StrictNamedArgs{"parent_id": in.CommentID}
+	filters := []string{"comments.parent_id = @parent_id"}
+	return c.comments(ctx, args, filters, in.ViewerID(), in.PageArgs)
+}
+
+func (c *Cockroach) comments(ctx context.Context, args pgx.StrictNamedArgs, filters []string, viewerID *string, page types.PageArgs) (types.Page[types.Comment], error) {
 	var out types.Page[types.Comment]
 
-	args := pgx.StrictNamedArgs{"post_id": in.PostID}
 	selects := []string{sqlCommentCols, sqlUserJSONB}
-	joins := []string{"INNER JOIN users ON comments.user_id = users.id"}
-	filters := []string{"comments.post_id = @post_id"}
+	joins := []string{
+		"INNER JOIN users ON comments.user_id = users.id",
+		"INNER JOIN posts ON comments.post_id = posts.id",
+	}
+	filters = append(filters, sqlPostVisible(args, viewerID), sqlUserActive)
 
-	if in.ViewerID() != nil {
-		args["viewer_id"] = *in.ViewerID()
+	if viewerID != nil {
+		args["viewer_id"] = *viewerID
+		filters = append(filters, sqlNoBlockBetween("@viewer_id", "comments.user_id"))
 		selects = append(selects, `(comments.user_id = @viewer_id) AS mine`, sqlSelectCommentsReactions)
 		joins = append(joins, sqlJoinCommentReactions)
 	} else {
 		selects = append(selects, `false AS mine`, `comments.reactions`)
 	}
 
-	pageArgs, err := ParsePageArgs[time.Time](in.PageArgs)
+	pageArgs, err := ParsePageArgs[time.Time](page)
 	if err != nil {
 		return out, err
 	}
@@ -231,16 +312,42 @@ func (c *Cockroach) CommentCreatedAt(ctx context.Context, commentID string) (tim
 	return createdAt, nil
 }
 
+func (c *Cockroach) commentContent(ctx context.Context, commentID string) (string, error) {
+	const query = "SELECT content FROM comments WHERE id = @comment_id"
+	args := pgx.StrictNamedArgs{"comment_id": commentID}
+	content, err := pgxutil.SelectRow(ctx, c.db, query, []any{args}, pgx.RowTo[string])
+	if db.IsNotFoundError(err) {
+		return "", errs.NotFoundError("comment not found")
+	}
+
+	if err != nil {
+		return "", fmt.Errorf("sql select comment content: %w", err)
+	}
+
+	return content, nil
+}
+
 func (c *Cockroach) UpdateComment(ctx context.Context, in types.UpdateComment) (types.UpdatedComment, error) {
 	var out types.UpdatedComment
 
 	return out, c.db.RunTx(ctx, func(ctx context.Context) error {
-		var err error
-		out, err = c.updateComment(ctx, in)
+		previousContent, err := c.commentContent(ctx, in.ID)
+		if err != nil {
+			return err
+		}
+
+		edited, err := c.createCommentRevision(ctx, in)
 		if err != nil {
 			return err
 		}
 
+		out, err = c.updateComment(ctx, in, edited)
+		if err != nil {
+			return err
+		}
+
+		out.PreviousContent = previousContent
+
 		if in.Content != nil {
 			if err := c.deletePostsTagsWithComment(ctx, in.ID); err != nil {
 				return err
@@ -248,7 +355,7 @@ func (c *Cockroach) UpdateComment(ctx context.Context, in types.UpdateComment) (
 		}
 
 		if len(in.Tags()) > 0 {
-			postID, err := c.commentPostID(ctx, in.ID)
+			postID, err := c.CommentPostID(ctx, in.ID)
 			if err != nil {
 				return err
 			}
@@ -267,18 +374,23 @@ func (c *Cockroach) UpdateComment(ctx context.Context, in types.UpdateComment) (
 	})
 }
 
-func (c *Cockroach) updateComment(ctx context.Context, in types.UpdateComment) (types.UpdatedComment, error) {
+func (c *Cockroach) updateComment(ctx context.Context, in types.UpdateComment, edited bool) (types.UpdatedComment, error) {
 	var out types.UpdatedComment
 
 	const query = `
 		UPDATE comments
-		SET content = COALESCE(@content, content)
+		SET
+			  content = COALESCE(@content, content)
+			, entities = CASE WHEN @content::STRING IS NULL THEN entities ELSE @entities END
+			, edited_at = CASE WHEN @edited THEN now() ELSE edited_at END
 		WHERE id = @comment_id
-		RETURNING content
+		RETURNING content, entities, edited_at IS NOT NULL AS edited, post_id
 	`
 	args := pgx.StrictNamedArgs{
 		"comment_id": in.ID,
 		"content":    in.Content,
+		"entities":   in.Entities(),
+		"edited":     edited,
 	}
 	out, err := pgxutil.SelectRow(ctx, c.db, query, []any{args}, pgx.RowToStructByNameLax[types.UpdatedComment])
 	if db.IsNotFoundError(err) {
@@ -294,7 +406,18 @@ func (c *Cockroach) updateComment(ctx context.Context, in types.UpdateComment) (
 
 func (c *Cockroach) DeleteComment(ctx context.Context, commentID string) error {
 	err := c.db.RunTx(ctx, func(ctx context.Context) error {
-		postID, err := c.commentPostID(ctx, commentID)
+		postID, err := c.CommentPostID(ctx, commentID)
+		if err != nil {
+			return err
+		}
+
+		parentID, err := c.commentParentID(ctx, commentID)
+		if err != nil {
+			return err
+		}
+
+		// replies get deleted in cascade.
+		threadSize, err := c.commentThreadSize(ctx, commentID)
 		if err != nil {
 			return err
 		}
@@ -303,7 +426,13 @@ func (c *Cockroach) DeleteComment(ctx context.Context, commentID string) error {
 			return err
 		}
 
-		return c.decreasePostCommentsCount(ctx, postID)
+		if parentID != nil {
+			if err := c.decreaseCommentRepliesCount(ctx, *parentID); err != nil {
+				return err
+			}
+		}
+
+		return c.decreasePostCommentsCount(ctx, postID, threadSize)
 	})
 	if errors.Is(err, errs.NotFound) {
 		return nil // idempotent
@@ -312,7 +441,61 @@ func (c *Cockroach) DeleteComment(ctx context.Context, commentID string) error {
 	return err
 }
 
-func (c *Cockroach) commentPostID(ctx context.Context, commentID string) (string, error) {
+func (c *Cockroach) commentParentID(ctx context.Context, commentID string) (*string, error) {
+	const query = "SELECT parent_id FROM comments WHERE id = @comment_id"
+	args := pgx.StrictNamedArgs{"comment_id": commentID}
+	parentID, err := pgxutil.SelectRow(ctx, c.db, query, []any{args}, pgx.RowTo[*string])
+	if db.IsNotFoundError(err) {
+		return nil, errs.NotFoundError("comment not found")
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("sql select comment parent_id: %w", err)
+	}
+
+	return parentID, nil
+}
+
+// commentThreadSize counts the comment along with all its nested replies.
+func (c *Cockroach) commentThreadSize(ctx context.Context, commentID string) (int, error) {
+	const query = `
+		WITH RECURSIVE thread AS (
+			SELECT id FROM comments WHERE id = @comment_id
+			UNION ALL
+			SELECT comments.id FROM comments INNER JOIN thread ON comments.parent_id = thread.id
+		)
+		SELECT count(*) FROM thread
+	`
+	args := pgx.StrictNamedArgs{"comment_id": commentID}
+	size, err := pgxutil.SelectRow(ctx, c.db, query, []any{args}, pgx.RowTo[int])
+	if err != nil {
+		return 0, fmt.Errorf("sql select comment thread size: %w", err)
+	}
+
+	return size, nil
+}
+
+func (c *Cockroach) increaseCommentRepliesCount(ctx context.Context, commentID string) error {
+	const query = "UPDATE comments SET replies_count = replies_count + 1 WHERE id = @comment_id"
+	_, err := c.db.Exec(ctx, query, pgx.StrictNamedArgs{"comment_id": commentID})
+	if err != nil {
+		return fmt.Errorf("sql increase comment replies count: %w", err)
+	}
+
+	return nil
+}
+
+func (c *Cockroach) decreaseCommentRepliesCount(ctx context.Context, commentID string) error {
+	const query = "UPDATE comments SET replies_count = replies_count - 1 WHERE id = @comment_id AND replies_count > 0"
+	_, err := c.db.Exec(ctx, query, pgx.StrictNamedArgs{"comment_id": commentID})
+	if err != nil {
+		return fmt.Errorf("sql decrease comment replies count: %w", err)
+	}
+
+	return nil
+}
+
+func (c *Cockroach) CommentPostID(ctx context.Context, commentID string) (string, error) {
 	const query = `
 		SELECT post_id
 		FROM comments
@@ -359,6 +542,12 @@ func (c *Cockroach) ToggleCommentReaction(ctx context.Context, in types.ToggleCo
 				return err
 			}
 		} else {
+			if in.Kind == types.ReactionKindCustom {
+				if err := c.customEmojiExists(ctx, in.Reaction); err != nil {
+					return err
+				}
+			}
+
 			if err := c.createCommentReaction(ctx, in); err != nil {
 				return err
 			}
@@ -450,8 +639,14 @@ func (c *Cockroach) refreshCommentReactions(ctx context.Context, commentID strin
 		)
 		UPDATE comments
 		SET reactions = (
-			SELECT jsonb_agg(jsonb_build_object('kind', kind, 'reaction', reaction, 'count', count))
+			SELECT jsonb_agg(jsonb_strip_nulls(jsonb_build_object(
+				'kind', counts.kind,
+				'reaction', counts.reaction,
+				'count', counts.count,
+				'imageURL', custom_emojis.image
+			)))
 			FROM counts
+			LEFT JOIN custom_emojis ON counts.kind = 'custom' AND custom_emojis.shortcode = counts.reaction
 		)
 		WHERE id = @comment_id
 	`
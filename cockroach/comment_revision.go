@@ -0,0 +1,87 @@
+package cockroach
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgxutil"
+	"github.com/nakamauwu/nakama/types"
+)
+
+// createCommentRevision saves the current state of the comment before updating it.
+// It reports whether the update actually changes anything,
+// no revision is created otherwise.
+func (c *Cockroach) createCommentRevision(ctx context.Context, in types.UpdateComment) (bool, error) {
+	const query = `
+		INSERT INTO comment_revisions (comment_id, content)
+		SELECT id, content
+		FROM comments
+		WHERE id = @comment_id AND content != COALESCE(@content, content)
+	`
+	args := pgx.StrictNamedArgs{
+		"comment_id": in.ID,
+		"content":    in.Content,
+	}
+	tag, err := c.db.Exec(ctx, query, args)
+	if err != nil {
+		return false, fmt.Errorf("sql insert comment revision: %w", err)
+	}
+
+	return tag.RowsAffected() != 0, nil
+}
+
+func (c *Cockroach) CommentRevisions(ctx context.Context, in types.ListCommentRevisions) (types.Page[types.CommentRevision], error) {
+	var out types.Page[types.CommentRevision]
+
+	args := pgx.StrictNamedArgs{"comment_id": in.CommentID}
+	filters := []string{"comment_id = @comment_id"}
+
+	pageArgs, err := ParsePageArgs[time.Time](in.PageArgs)
+	if err != nil {
+		return out, err
+	}
+
+	if pageArgs.After != nil {
+		filters = append(filters, "(created_at, id) < (@after_created_at, @after_id)")
+		args["after_created_at"] = pageArgs.After.Value
+		args["after_id"] = pageArgs.After.ID
+	} else if pageArgs.Before != nil {
+		filters = append(filters, "(created_at, id) > (@before_created_at, @before_id)")
+		args["before_created_at"] = pageArgs.Before.Value
+		args["before_id"] = pageArgs.Before.ID
+	}
+
+	var order, limit string
+	if pageArgs.IsBackwards() {
+		order = "ORDER BY created_at ASC, id ASC"
+		limit = fmt.Sprintf("LIMIT %d", or(pageArgs.Last, defaultPageSize)+1) // +1 to check if there's a next page
+	} else {
+		order = "ORDER BY created_at DESC, id DESC"
+		limit = fmt.Sprintf("LIMIT %d", or(pageArgs.First, defaultPageSize)+1) // +1 to check if there's a next page
+	}
+
+	query := fmt.Sprintf(`
+		SELECT id, comment_id, content, created_at
+		FROM comment_revisions
+		WHERE %s
+		%s
+		%s`,
+		strings.Join(filters, " AND "),
+		order,
+		limit,
+	)
+
+	revisions, err := pgxutil.Select(ctx, c.db, query, []any{args}, pgx.RowToStructByNameLax[types.CommentRevision])
+	if err != nil {
+		return out, fmt.Errorf("sql select comment revisions: %w", err)
+	}
+
+	out.Items = revisions
+
+	return out, applyPageInfo(&out, pageArgs, func(r types.CommentRevision) Cursor[time.Time] {
+		return Cursor[time.Time]{ID: r.ID, Value: r.CreatedAt}
+	})
+}
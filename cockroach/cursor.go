@@ -10,7 +10,7 @@ import (
 	"github.com/vmihailenco/msgpack/v5"
 )
 
-const defaultPageSize = 15
+const defaultPageSize = types.DefaultPageSize
 
 type Cursor[T any] struct {
 	ID string `msgpack:"i"`
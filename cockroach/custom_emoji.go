@@ -0,0 +1,125 @@
+package cockroach
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgxutil"
+	"github.com/nakamauwu/nakama/types"
+	"github.com/nicolasparada/go-db"
+	"github.com/nicolasparada/go-errs"
+)
+
+func (c *Cockroach) CreateCustomEmoji(ctx context.Context, in types.CreateCustomEmoji) (types.CustomEmoji, error) {
+	const query = `
+		INSERT INTO custom_emojis (shortcode, image)
+		VALUES (@shortcode, @image)
+		RETURNING shortcode, image, created_at
+	`
+	args := pgx.StrictNamedArgs{
+		"shortcode": in.Shortcode,
+		"image":     in.Image(),
+	}
+	out, err := pgxutil.SelectRow(ctx, c.db, query, []any{args}, pgx.RowToStructByNameLax[types.CustomEmoji])
+	if db.IsUniqueViolationError(err) {
+		return out, errs.ConflictError("shortcode taken")
+	}
+
+	if err != nil {
+		return out, fmt.Errorf("sql insert custom emoji: %w", err)
+	}
+
+	return out, nil
+}
+
+// CustomEmojis sorted by shortcode.
+func (c *Cockroach) CustomEmojis(ctx context.Context) ([]types.CustomEmoji, error) {
+	const query = `
+		SELECT shortcode, image, created_at
+		FROM custom_emojis
+		ORDER BY shortcode
+	`
+	emojis, err := pgxutil.Select(ctx, c.db, query, nil, pgx.RowToStructByNameLax[types.CustomEmoji])
+	if err != nil {
+		return nil, fmt.Errorf("sql select custom emojis: %w", err)
+	}
+
+	return emojis, nil
+}
+
+func (c *Cockroach) customEmojiExists(ctx context.Context, shortcode string) error {
+	const query = `SELECT EXISTS (SELECT 1 FROM custom_emojis WHERE shortcode = @shortcode)`
+	args := pgx.StrictNamedArgs{"shortcode": shortcode}
+	exists, err := pgxutil.SelectRow(ctx, c.db, query, []any{args}, pgx.RowTo[bool])
+	if err != nil {
+		return fmt.Errorf("sql select custom emoji exists: %w", err)
+	}
+
+	if !exists {
+		return errs.NotFoundError("custom emoji not found")
+	}
+
+	return nil
+}
+
+// DeleteCustomEmoji along with every reaction made with it.
+// It returns the object key of its image so it can be removed from storage.
+func (c *Cockroach) DeleteCustomEmoji(ctx context.Context, shortcode string) (string, error) {
+	var image string
+	return image, c.db.RunTx(ctx, func(ctx context.Context) error {
+		args := pgx.StrictNamedArgs{"shortcode": shortcode}
+
+		const query = `
+			DELETE FROM custom_emojis
+			WHERE shortcode = @shortcode
+			RETURNING image
+		`
+		var err error
+		image, err = pgxutil.SelectRow(ctx, c.db, query, []any{args}, pgx.RowTo[string])
+		if db.IsNotFoundError(err) {
+			return errs.NotFoundError("custom emoji not found")
+		}
+
+		if err != nil {
+			return fmt.Errorf("sql delete custom emoji: %w", err)
+		}
+
+		const postReactionsQuery = `
+			DELETE FROM post_reactions
+			WHERE kind = 'custom' AND reaction = @shortcode
+			RETURNING post_id
+		`
+		postIDs, err := pgxutil.Select(ctx, c.db, postReactionsQuery, []any{args}, pgx.RowTo[string])
+		if err != nil {
+			return fmt.Errorf("sql delete custom emoji post reactions: %w", err)
+		}
+
+		slices.Sort(postIDs)
+		for _, postID := range slices.Compact(postIDs) {
+			if err := c.refreshPostReactions(ctx, postID); err != nil {
+				return err
+			}
+		}
+
+		const commentReactionsQuery = `
+			DELETE FROM comment_reactions
+			WHERE kind = 'custom' AND reaction = @shortcode
+			RETURNING comment_id
+		`
+		commentIDs, err := pgxutil.Select(ctx, c.db, commentReactionsQuery, []any{args}, pgx.RowTo[string])
+		if err != nil {
+			return fmt.Errorf("sql delete custom emoji comment reactions: %w", err)
+		}
+
+		slices.Sort(commentIDs)
+		for _, commentID := range slices.Compact(commentIDs) {
+			if err := c.refreshCommentReactions(ctx, commentID); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
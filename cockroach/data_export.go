@@ -0,0 +1,299 @@
+package cockroach
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgxutil"
+	"github.com/nakamauwu/nakama/types"
+	"github.com/nicolasparada/go-db"
+	"github.com/nicolasparada/go-errs"
+)
+
+const sqlDataExportCols = `
+	  data_exports.id
+	, data_exports.user_id
+	, data_exports.status
+	, data_exports.expires_at
+	, data_exports.created_at
+`
+
+// dataExportStaleAfter is how long an export can go without being touched
+// while processing before another instance picks it up again, like after a crash.
+// See [Cockroach.TouchDataExport].
+const dataExportStaleAfter = time.Hour
+
+func (c *Cockroach) CreateDataExport(ctx context.Context, userID string) (types.DataExport, error) {
+	query := fmt.Sprintf(`
+		INSERT INTO data_exports (user_id)
+		VALUES (@user_id)
+		RETURNING %s
+	`, sqlDataExportCols)
+	args := pgx.StrictNamedArgs{"user_id": userID}
+
+	out, err := pgxutil.SelectRow(ctx, c.db, query, []any{args}, pgx.RowToStructByNameLax[types.DataExport])
+	if db.IsUniqueViolationError(err) {
+		return out, errs.ConflictError("data export already in progress")
+	}
+
+	if err != nil {
+		return out, fmt.Errorf("sql insert data export: %w", err)
+	}
+
+	return out, nil
+}
+
+// ClaimDataExport marks the oldest pending export as processing and returns it.
+// It returns a not found error when there is nothing to process.
+func (c *Cockroach) ClaimDataExport(ctx context.Context) (types.DataExport, error) {
+	query := fmt.Sprintf(`
+		UPDATE data_exports
+		SET status = 'processing', updated_at = now()
+		WHERE id = (
+			SELECT id FROM data_exports
+			WHERE status = 'pending'
+			OR (status = 'processing' AND updated_at < now() - @stale_after::INTERVAL)
+			ORDER BY created_at
+			LIMIT 1
+		)
+		RETURNING %s
+	`, sqlDataExportCols)
+	args := pgx.StrictNamedArgs{"stale_after": dataExportStaleAfter}
+
+	out, err := pgxutil.SelectRow(ctx, c.db, query, []any{args}, pgx.RowToStructByNameLax[types.DataExport])
+	if db.IsNotFoundError(err) {
+		return out, errs.NotFoundError("no pending data export")
+	}
+
+	if err != nil {
+		return out, fmt.Errorf("sql claim data export: %w", err)
+	}
+
+	return out, nil
+}
+
+func (c *Cockroach) CompleteDataExport(ctx context.Context, dataExportID, objectKey string, expiresAt time.Time) error {
+	const query = `
+		UPDATE data_exports
+		SET status = 'ready', object_key = @object_key, expires_at = @expires_at, updated_at = now()
+		WHERE id = @data_export_id
+	`
+	args := pgx.StrictNamedArgs{
+		"data_export_id": dataExportID,
+		"object_key":     objectKey,
+		"expires_at":     expiresAt,
+	}
+	tag, err := c.db.Exec(ctx, query, args)
+	if err != nil {
+		return fmt.Errorf("sql update data export as ready: %w", err)
+	}
+
+	// the user could have been deleted in the meantime.
+	if tag.RowsAffected() == 0 {
+		return errs.NotFoundError("data export not found")
+	}
+
+	return nil
+}
+
+// TouchDataExport keeps a processing export claimed,
+// so it doesn't look stale while its archive is still being built.
+func (c *Cockroach) TouchDataExport(ctx context.Context, dataExportID string) error {
+	const query = `
+		UPDATE data_exports
+		SET updated_at = now()
+		WHERE id = @data_export_id AND status = 'processing'
+	`
+	args := pgx.StrictNamedArgs{"data_export_id": dataExportID}
+	_, err := c.db.Exec(ctx, query, args)
+	if err != nil {
+		return fmt.Errorf("sql touch data export: %w", err)
+	}
+
+	return nil
+}
+
+// FailDataExport marks a processing export as failed.
+// Exports that are already ready are left as is.
+func (c *Cockroach) FailDataExport(ctx context.Context, dataExportID string) error {
+	const query = `
+		UPDATE data_exports
+		SET status = 'failed', updated_at = now()
+		WHERE id = @data_export_id AND status = 'processing'
+	`
+	args := pgx.StrictNamedArgs{"data_export_id": dataExportID}
+	_, err := c.db.Exec(ctx, query, args)
+	if err != nil {
+		return fmt.Errorf("sql update data export as failed: %w", err)
+	}
+
+	return nil
+}
+
+// DeleteExpiredDataExports removes exports whose link already expired,
+// along with failed ones, returning the keys of the archives to remove from storage.
+func (c *Cockroach) DeleteExpiredDataExports(ctx context.Context) ([]string, error) {
+	const query = `
+		DELETE FROM data_exports
+		WHERE expires_at <= now()
+		OR (status = 'failed' AND updated_at < now() - INTERVAL '1 day')
+		RETURNING object_key
+	`
+	keys, err := pgxutil.Select(ctx, c.db, query, nil, pgx.RowTo[*string])
+	if err != nil {
+		return nil, fmt.Errorf("sql delete expired data exports: %w", err)
+	}
+
+	var out []string
+	for _, key := range keys {
+		if key != nil {
+			out = append(out, *key)
+		}
+	}
+
+	return out, nil
+}
+
+// UserData collects everything from the given user to export.
+func (c *Cockroach) UserData(ctx context.Context, userID string) (types.UserData, error) {
+	var out types.UserData
+
+	const query = `
+		SELECT
+			  users.email
+			, users.avatar
+			, users.cover
+			, jsonb_build_object(
+				  'id', users.id
+				, 'email', users.email
+				, 'username', users.username
+				, 'avatar', users.avatar
+				, 'cover', users.cover
+				, 'bio', users.bio
+				, 'waifu', users.waifu
+				, 'husbando', users.husbando
+				, 'private', users.private
+				, 'followersCount', users.followers_count
+				, 'followeesCount', users.followees_count
+				, 'createdAt', users.created_at
+			)
+			, (
+				SELECT COALESCE(jsonb_agg(jsonb_build_object(
+					  'id', posts.id
+					, 'content', posts.content
+					, 'spoilerOf', posts.spoiler_of
+					, 'nsfw', posts.nsfw
+					, 'media', posts.media
+					, 'visibility', posts.visibility
+					, 'repostOfID', posts.repost_of_id
+					, 'createdAt', posts.created_at
+					, 'editedAt', posts.edited_at
+				) ORDER BY posts.created_at), '[]'::JSONB)
+				FROM posts WHERE posts.user_id = users.id
+			)
+			, (
+				SELECT COALESCE(jsonb_agg(jsonb_build_object(
+					  'id', drafts.id
+					, 'content', drafts.content
+					, 'spoilerOf', drafts.spoiler_of
+					, 'nsfw', drafts.nsfw
+					, 'media', drafts.media
+					, 'visibility', drafts.visibility
+					, 'publishAt', drafts.publish_at
+					, 'createdAt', drafts.created_at
+				) ORDER BY drafts.created_at), '[]'::JSONB)
+				FROM drafts WHERE drafts.user_id = users.id
+			)
+			, (
+				SELECT COALESCE(jsonb_agg(jsonb_build_object(
+					  'id', comments.id
+					, 'postID', comments.post_id
+					, 'parentID', comments.parent_id
+					, 'content', comments.content
+					, 'createdAt', comments.created_at
+					, 'editedAt', comments.edited_at
+				) ORDER BY comments.created_at), '[]'::JSONB)
+				FROM comments WHERE comments.user_id = users.id
+			)
+			, (
+				SELECT COALESCE(jsonb_agg(jsonb_build_object(
+					  'postID', post_reactions.post_id
+					, 'kind', post_reactions.kind
+					, 'reaction', post_reactions.reaction
+				)), '[]'::JSONB)
+				FROM post_reactions WHERE post_reactions.user_id = users.id
+			)
+			, (
+				SELECT COALESCE(jsonb_agg(jsonb_build_object(
+					  'commentID', comment_reactions.comment_id
+					, 'kind', comment_reactions.kind
+					, 'reaction', comment_reactions.reaction
+				)), '[]'::JSONB)
+				FROM comment_reactions WHERE comment_reactions.user_id = users.id
+			)
+			, (
+				SELECT COALESCE(jsonb_agg(jsonb_build_object(
+					  'id', followees.id
+					, 'username', followees.username
+				) ORDER BY followees.username), '[]'::JSONB)
+				FROM follows
+				INNER JOIN users AS followees ON followees.id = follows.followee_id
+				WHERE follows.follower_id = users.id
+			)
+			, (
+				SELECT COALESCE(jsonb_agg(jsonb_build_object(
+					  'id', followers.id
+					, 'username', followers.username
+				) ORDER BY followers.username), '[]'::JSONB)
+				FROM follows
+				INNER JOIN users AS followers ON followers.id = follows.follower_id
+				WHERE follows.followee_id = users.id
+			)
+			, (
+				SELECT COALESCE(jsonb_agg(jsonb_build_object(
+					  'id', notifications.id
+					, 'kind', notifications.kind
+					, 'actorUsernames', notifications.actor_usernames
+					, 'postID', notifications.post_id
+					, 'commentID', notifications.comment_id
+					, 'readAt', notifications.read_at
+					, 'issuedAt', notifications.issued_at
+				) ORDER BY notifications.issued_at), '[]'::JSONB)
+				FROM notifications WHERE notifications.user_id = users.id
+			)
+		FROM users
+		WHERE users.id = @user_id
+	`
+	args := pgx.StrictNamedArgs{"user_id": userID}
+	err := c.db.QueryRow(ctx, query, args).Scan(
+		&out.Email,
+		&out.Avatar,
+		&out.Cover,
+		&out.Profile,
+		&out.Posts,
+		&out.Drafts,
+		&out.Comments,
+		&out.PostReactions,
+		&out.CommentReactions,
+		&out.Following,
+		&out.Followers,
+		&out.Notifications,
+	)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return out, errs.NotFoundError("user not found")
+	}
+
+	if err != nil {
+		return out, fmt.Errorf("sql select user data: %w", err)
+	}
+
+	out.Media, err = c.userMedia(ctx, userID)
+	if err != nil {
+		return out, err
+	}
+
+	return out, nil
+}
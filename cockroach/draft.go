@@ -0,0 +1,274 @@
+package cockroach
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgerrcode"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgxutil"
+	"github.com/nakamauwu/nakama/types"
+	"github.com/nicolasparada/go-db"
+	"github.com/nicolasparada/go-errs"
+)
+
+const sqlDraftCols = `
+	  drafts.id
+	, drafts.user_id
+	, drafts.content
+	, drafts.media
+	, drafts.spoiler_of
+	, drafts.nsfw
+	, drafts.visibility
+	, drafts.publish_at
+	, drafts.created_at
+	, drafts.updated_at
+`
+
+func (c *Cockroach) CreateDraft(ctx context.Context, in types.CreateDraft) (types.Created, error) {
+	const query = `
+		INSERT INTO drafts (user_id, content, spoiler_of, nsfw, visibility, media, publish_at)
+		VALUES (@user_id, @content, @spoiler_of, @nsfw, @visibility, @media, @publish_at)
+		RETURNING id, created_at
+	`
+	args := pgx.StrictNamedArgs{
+		"user_id":    in.UserID(),
+		"content":    in.Content,
+		"spoiler_of": in.SpoilerOf,
+		"nsfw":       in.NSFW,
+		"visibility": in.Visibility,
+		"media":      in.Media(),
+		"publish_at": in.PublishAt,
+	}
+
+	out, err := pgxutil.SelectRow(ctx, c.db, query, []any{args}, pgx.RowToStructByNameLax[types.Created])
+	if err != nil {
+		return out, fmt.Errorf("sql insert draft: %w", err)
+	}
+
+	return out, nil
+}
+
+func (c *Cockroach) Drafts(ctx context.Context, in types.ListDrafts) (types.Page[types.Draft], error) {
+	var out types.Page[types.Draft]
+
+	args := pgx.StrictNamedArgs{"user_id": in.UserID()}
+	filters := []string{"drafts.user_id = @user_id"}
+
+	pageArgs, err := ParsePageArgs[time.Time](in.PageArgs)
+	if err != nil {
+		return out, err
+	}
+
+	if pageArgs.After != nil {
+		filters = append(filters, "(drafts.created_at, drafts.id) < (@after_created_at, @after_id)")
+		args["after_created_at"] = pageArgs.After.Value
+		args["after_id"] = pageArgs.After.ID
+	} else if pageArgs.Before != nil {
+		filters = append(filters, "(drafts.created_at, drafts.id) > (@before_created_at, @before_id)")
+		args["before_created_at"] = pageArgs.Before.Value
+		args["before_id"] = pageArgs.Before.ID
+	}
+
+	var order, limit string
+	if pageArgs.IsBackwards() {
+		order = "ORDER BY drafts.created_at ASC, drafts.id ASC"
+		limit = fmt.Sprintf("LIMIT %d", or(pageArgs.Last, defaultPageSize)+1) // +1 to check if there's a next page
+	} else {
+		order = "ORDER BY drafts.created_at DESC, drafts.id DESC"
+		limit = fmt.Sprintf("LIMIT %d", or(pageArgs.First, defaultPageSize)+1) // +1 to check if there's a next page
+	}
+
+	query := fmt.Sprintf(`
+		SELECT %s
+		FROM drafts
+		WHERE %s
+		%s
+		%s`,
+		sqlDraftCols,
+		strings.Join(filters, " AND "),
+		order,
+		limit,
+	)
+
+	drafts, err := pgxutil.Select(ctx, c.db, query, []any{args}, pgx.RowToStructByNameLax[types.Draft])
+	if err != nil {
+		return out, fmt.Errorf("sql select drafts: %w", err)
+	}
+
+	out.Items = drafts
+
+	return out, applyPageInfo(&out, pageArgs, func(d types.Draft) Cursor[time.Time] {
+		return Cursor[time.Time]{ID: d.ID, Value: d.CreatedAt}
+	})
+}
+
+func (c *Cockroach) Draft(ctx context.Context, draftID string) (types.Draft, error) {
+	query := fmt.Sprintf("SELECT %s FROM drafts WHERE drafts.id = @draft_id", sqlDraftCols)
+	args := pgx.StrictNamedArgs{"draft_id": draftID}
+	draft, err := pgxutil.SelectRow(ctx, c.db, query, []any{args}, pgx.RowToStructByNameLax[types.Draft])
+	if db.IsNotFoundError(err) {
+		return draft, errs.NotFoundError("draft not found")
+	}
+
+	if err != nil {
+		return draft, fmt.Errorf("sql select draft: %w", err)
+	}
+
+	return draft, nil
+}
+
+func (c *Cockroach) DraftUserID(ctx context.Context, draftID string) (string, error) {
+	const query = "SELECT user_id FROM drafts WHERE id = @draft_id"
+	args := pgx.StrictNamedArgs{"draft_id": draftID}
+	userID, err := pgxutil.SelectRow(ctx, c.db, query, []any{args}, pgx.RowTo[string])
+	if db.IsNotFoundError(err) {
+		return "", errs.NotFoundError("draft not found")
+	}
+
+	if err != nil {
+		return "", fmt.Errorf("sql select draft user ID: %w", err)
+	}
+
+	return userID, nil
+}
+
+func (c *Cockroach) UpdateDraft(ctx context.Context, in types.UpdateDraft) (types.Draft, error) {
+	query := fmt.Sprintf(`
+		UPDATE drafts
+		SET
+			  content = COALESCE(@content, content)
+			, spoiler_of = CASE WHEN @spoiler_of::VARCHAR = '' THEN NULL ELSE COALESCE(@spoiler_of, spoiler_of) END
+			, nsfw = COALESCE(@nsfw, nsfw)
+			, visibility = COALESCE(@visibility, visibility)
+			, publish_at = CASE WHEN @unschedule THEN NULL ELSE COALESCE(@publish_at, publish_at) END
+			, updated_at = now()
+		WHERE drafts.id = @draft_id
+		RETURNING %s
+	`, sqlDraftCols)
+	args := pgx.StrictNamedArgs{
+		"draft_id":   in.ID,
+		"content":    in.Content,
+		"spoiler_of": in.SpoilerOf,
+		"nsfw":       in.NSFW,
+		"visibility": in.Visibility,
+		"publish_at": in.PublishAt,
+		"unschedule": in.Unschedule,
+	}
+	draft, err := pgxutil.SelectRow(ctx, c.db, query, []any{args}, pgx.RowToStructByNameLax[types.Draft])
+	if db.IsNotFoundError(err) {
+		return draft, errs.NotFoundError("draft not found")
+	}
+
+	if db.IsError(err, pgerrcode.CheckViolation, "scheduled_drafts_content") {
+		return draft, errs.InvalidArgumentError("scheduled draft cannot be empty")
+	}
+
+	if err != nil {
+		return draft, fmt.Errorf("sql update draft: %w", err)
+	}
+
+	return draft, nil
+}
+
+// DeleteDraft returns the media of the deleted draft
+// so it can be removed from the object store.
+func (c *Cockroach) DeleteDraft(ctx context.Context, draftID string) ([]types.Media, error) {
+	const query = `
+		DELETE FROM drafts
+		WHERE id = @draft_id
+		RETURNING media
+	`
+	args := pgx.StrictNamedArgs{"draft_id": draftID}
+	media, err := pgxutil.SelectRow(ctx, c.db, query, []any{args}, pgx.RowTo[[]types.Media])
+	if db.IsNotFoundError(err) {
+		return nil, nil // idempotent
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("sql delete draft: %w", err)
+	}
+
+	return media, nil
+}
+
+func (c *Cockroach) DueDraftIDs(ctx context.Context, limit int) ([]string, error) {
+	const query = `
+		SELECT id
+		FROM drafts
+		WHERE publish_at IS NOT NULL AND publish_at <= now()
+		ORDER BY publish_at ASC
+		LIMIT @limit
+	`
+	args := pgx.StrictNamedArgs{"limit": limit}
+	ids, err := pgxutil.Select(ctx, c.db, query, []any{args}, pgx.RowTo[string])
+	if err != nil {
+		return nil, fmt.Errorf("sql select due draft IDs: %w", err)
+	}
+
+	return ids, nil
+}
+
+// FailScheduledDraft unschedules a draft that could not be published
+// and notifies its author so they can fix it.
+// It returns a not found error if the draft is gone or no longer scheduled.
+func (c *Cockroach) FailScheduledDraft(ctx context.Context, draftID string) (types.CreatedNotification, error) {
+	const query = `
+		WITH unscheduled AS (
+			UPDATE drafts
+			SET publish_at = NULL, updated_at = now()
+			WHERE id = @draft_id AND publish_at IS NOT NULL
+			RETURNING id, user_id
+		)
+		INSERT INTO notifications (user_id, kind, draft_id)
+		SELECT user_id, @kind, id FROM unscheduled
+		RETURNING id, issued_at
+	`
+	args := pgx.StrictNamedArgs{
+		"draft_id": draftID,
+		"kind":     types.NotificationKindDraftFailed,
+	}
+	created, err := pgxutil.SelectRow(ctx, c.db, query, []any{args}, pgx.RowToStructByNameLax[types.CreatedNotification])
+	if db.IsNotFoundError(err) {
+		return created, errs.NotFoundError("draft not found")
+	}
+
+	if err != nil {
+		return created, fmt.Errorf("sql fail scheduled draft: %w", err)
+	}
+
+	return created, nil
+}
+
+// PublishDraft locks the draft and calls publishFunc within the same transaction,
+// deleting the draft afterwards. Use [Cockroach.CreatePost] inside publishFunc,
+// so either both the post gets created and the draft gets deleted, or neither.
+// Concurrent calls for the same draft wait for the lock and then get a not found error,
+// so a draft is published only once even with several instances running.
+func (c *Cockroach) PublishDraft(ctx context.Context, draftID string, publishFunc func(ctx context.Context, draft types.Draft) error) error {
+	return c.db.RunTx(ctx, func(ctx context.Context) error {
+		query := fmt.Sprintf("SELECT %s FROM drafts WHERE drafts.id = @draft_id FOR UPDATE", sqlDraftCols)
+		args := pgx.StrictNamedArgs{"draft_id": draftID}
+		draft, err := pgxutil.SelectRow(ctx, c.db, query, []any{args}, pgx.RowToStructByNameLax[types.Draft])
+		if db.IsNotFoundError(err) {
+			return errs.NotFoundError("draft not found")
+		}
+
+		if err != nil {
+			return fmt.Errorf("sql select draft for update: %w", err)
+		}
+
+		if err := publishFunc(ctx, draft); err != nil {
+			return err
+		}
+
+		_, err = c.db.Exec(ctx, "DELETE FROM drafts WHERE id = @draft_id", pgx.StrictNamedArgs{"draft_id": draftID})
+		if err != nil {
+			return fmt.Errorf("sql delete published draft: %w", err)
+		}
+
+		return nil
+	})
+}
@@ -11,6 +11,9 @@ import (
 	"github.com/nicolasparada/go-errs"
 )
 
+// ToggleFollow follows or unfollows the given user.
+// Following a private account creates a follow request instead,
+// and toggling again while the request is pending cancels it.
 func (c *Cockroach) ToggleFollow(ctx context.Context, followerID, followeeID string) (types.ToggledFollow, error) {
 	var out types.ToggledFollow
 	return out, c.db.RunTx(ctx, func(ctx context.Context) error {
@@ -20,15 +23,7 @@ func (c *Cockroach) ToggleFollow(ctx context.Context, followerID, followeeID str
 		}
 
 		if followExists {
-			if err := c.deleteFollow(ctx, followerID, followeeID); err != nil {
-				return err
-			}
-
-			if _, err := c.decreaseFolloweesCount(ctx, followerID); err != nil {
-				return err
-			}
-
-			followersCount, err := c.decreaseFollowersCount(ctx, followeeID)
+			followersCount, err := c.unfollow(ctx, followerID, followeeID)
 			if err != nil {
 				return err
 			}
@@ -39,15 +34,32 @@ func (c *Cockroach) ToggleFollow(ctx context.Context, followerID, followeeID str
 			return nil
 		}
 
-		if err := c.createFollow(ctx, followerID, followeeID); err != nil {
+		canceled, err := c.deleteFollowRequest(ctx, followerID, followeeID)
+		if err != nil {
 			return err
 		}
 
-		if _, err := c.increaseFolloweesCount(ctx, followerID); err != nil {
+		if canceled {
+			out.FollowersCount, err = c.followersCount(ctx, followeeID)
 			return err
 		}
 
-		followersCount, err := c.increaseFollowersCount(ctx, followeeID)
+		private, err := c.UserPrivate(ctx, followeeID)
+		if err != nil {
+			return err
+		}
+
+		if private {
+			if err := c.createFollowRequest(ctx, followerID, followeeID); err != nil {
+				return err
+			}
+
+			out.FollowRequested = true
+			out.FollowersCount, err = c.followersCount(ctx, followeeID)
+			return err
+		}
+
+		followersCount, err := c.follow(ctx, followerID, followeeID)
 		if err != nil {
 			return err
 		}
@@ -59,6 +71,34 @@ func (c *Cockroach) ToggleFollow(ctx context.Context, followerID, followeeID str
 	})
 }
 
+// follow creates the follow and updates the counts of both users.
+// It returns the new followers count of the followee.
+func (c *Cockroach) follow(ctx context.Context, followerID, followeeID string) (uint, error) {
+	if err := c.createFollow(ctx, followerID, followeeID); err != nil {
+		return 0, err
+	}
+
+	if _, err := c.increaseFolloweesCount(ctx, followerID); err != nil {
+		return 0, err
+	}
+
+	return c.increaseFollowersCount(ctx, followeeID)
+}
+
+// unfollow deletes the follow and updates the counts of both users.
+// It returns the new followers count of the followee.
+func (c *Cockroach) unfollow(ctx context.Context, followerID, followeeID string) (uint, error) {
+	if err := c.deleteFollow(ctx, followerID, followeeID); err != nil {
+		return 0, err
+	}
+
+	if _, err := c.decreaseFolloweesCount(ctx, followerID); err != nil {
+		return 0, err
+	}
+
+	return c.decreaseFollowersCount(ctx, followeeID)
+}
+
 func (c *Cockroach) createFollow(ctx context.Context, followerID, followeeID string) error {
 	query := `
 		INSERT INTO follows (follower_id, followee_id) VALUES (@follower_id, @followee_id)
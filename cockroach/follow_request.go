@@ -0,0 +1,182 @@
+package cockroach
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgxutil"
+	"github.com/nakamauwu/nakama/types"
+	"github.com/nicolasparada/go-db"
+	"github.com/nicolasparada/go-errs"
+)
+
+func (c *Cockroach) createFollowRequest(ctx context.Context, followerID, followeeID string) error {
+	const query = `
+		INSERT INTO follow_requests (follower_id, followee_id)
+		VALUES (@follower_id, @followee_id)
+	`
+	args := pgx.StrictNamedArgs{
+		"follower_id": followerID,
+		"followee_id": followeeID,
+	}
+	_, err := c.db.Exec(ctx, query, args)
+	if db.IsUniqueViolationError(err) {
+		return errs.ConflictError("follow request already exists")
+	}
+
+	if err != nil {
+		return fmt.Errorf("sql insert follow request: %w", err)
+	}
+
+	return nil
+}
+
+// deleteFollowRequest reports whether there was a request to delete.
+func (c *Cockroach) deleteFollowRequest(ctx context.Context, followerID, followeeID string) (bool, error) {
+	const query = `
+		DELETE FROM follow_requests
+		WHERE follower_id = @follower_id AND followee_id = @followee_id
+	`
+	args := pgx.StrictNamedArgs{
+		"follower_id": followerID,
+		"followee_id": followeeID,
+	}
+	tag, err := c.db.Exec(ctx, query, args)
+	if err != nil {
+		return false, fmt.Errorf("sql delete follow request: %w", err)
+	}
+
+	return tag.RowsAffected() > 0, nil
+}
+
+// FollowRequests lists the users waiting for the given user to approve them.
+func (c *Cockroach) FollowRequests(ctx context.Context, in types.ListFollowRequests) (types.Page[types.UserProfile], error) {
+	var out types.Page[types.UserProfile]
+
+	args := pgx.StrictNamedArgs{"viewer_id": in.UserID()}
+	selects := []string{sqlUserProfileCols}
+	joins := []string{
+		`INNER JOIN users ON users.id = follow_requests.follower_id`,
+	}
+	filters := []string{`follow_requests.followee_id = @viewer_id`}
+
+	selects, joins = appendViewerRelationshipFields(selects, joins)
+
+	pageArgs, err := ParsePageArgs[any](in.PageArgs)
+	if err != nil {
+		return out, err
+	}
+
+	if pageArgs.After != nil {
+		filters = append(filters, "users.username < @after_username")
+		args["after_username"] = pageArgs.After.ID // Cursor ID is the username in this case
+	} else if pageArgs.Before != nil {
+		filters = append(filters, "users.username > @before_username")
+		args["before_username"] = pageArgs.Before.ID // Cursor ID is the username in this case
+	}
+
+	var order, limit string
+	if pageArgs.IsBackwards() {
+		order = "ORDER BY users.username ASC, users.id ASC"
+		limit = fmt.Sprintf("LIMIT %d", or(pageArgs.Last, defaultPageSize)+1) // +1 to check if there's a next page
+	} else {
+		order = "ORDER BY users.username DESC, users.id DESC"
+		limit = fmt.Sprintf("LIMIT %d", or(pageArgs.First, defaultPageSize)+1) // +1 to check if there's a next page
+	}
+
+	query := fmt.Sprintf(`
+		SELECT %s
+		FROM follow_requests
+		%s
+		WHERE %s
+		%s
+		%s`,
+		strings.Join(selects, ",\n\t\t"),
+		strings.Join(joins, "\n\t\t"),
+		strings.Join(filters, " AND "),
+		order,
+		limit,
+	)
+
+	users, err := pgxutil.Select(ctx, c.db, query, []any{args}, pgx.RowToStructByNameLax[types.UserProfile])
+	if err != nil {
+		return out, fmt.Errorf("sql select follow requests: %w", err)
+	}
+
+	out.Items = users
+
+	return out, applyPageInfo(&out, pageArgs, userProfileCursor)
+}
+
+// ApproveFollowRequest turns the pending request into a follow.
+func (c *Cockroach) ApproveFollowRequest(ctx context.Context, followerID, followeeID string) error {
+	return c.db.RunTx(ctx, func(ctx context.Context) error {
+		deleted, err := c.deleteFollowRequest(ctx, followerID, followeeID)
+		if err != nil {
+			return err
+		}
+
+		if !deleted {
+			return errs.NotFoundError("follow request not found")
+		}
+
+		_, err = c.follow(ctx, followerID, followeeID)
+		return err
+	})
+}
+
+func (c *Cockroach) RejectFollowRequest(ctx context.Context, followerID, followeeID string) error {
+	deleted, err := c.deleteFollowRequest(ctx, followerID, followeeID)
+	if err != nil {
+		return err
+	}
+
+	if !deleted {
+		return errs.NotFoundError("follow request not found")
+	}
+
+	return nil
+}
+
+// approveAllFollowRequests is used when an account stops being private.
+func (c *Cockroach) approveAllFollowRequests(ctx context.Context, followeeID string) error {
+	const query = `
+		DELETE FROM follow_requests
+		WHERE followee_id = @followee_id
+		RETURNING follower_id
+	`
+	args := pgx.StrictNamedArgs{"followee_id": followeeID}
+	followerIDs, err := pgxutil.Select(ctx, c.db, query, []any{args}, pgx.RowTo[string])
+	if err != nil {
+		return fmt.Errorf("sql delete all follow requests: %w", err)
+	}
+
+	for _, followerID := range followerIDs {
+		if _, err := c.follow(ctx, followerID, followeeID); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// deleteFollowRequestsBetween removes pending requests in any direction.
+func (c *Cockroach) deleteFollowRequestsBetween(ctx context.Context, userID, otherUserID string) error {
+	const query = `
+		DELETE FROM follow_requests
+		WHERE (follower_id = @user_id AND followee_id = @other_user_id)
+		OR (follower_id = @other_user_id AND followee_id = @user_id)
+	`
+	args := pgx.StrictNamedArgs{
+		"user_id":       userID,
+		"other_user_id": otherUserID,
+	}
+	_, err := c.db.Exec(ctx, query, args)
+	if err != nil {
+		return fmt.Errorf("sql delete follow requests between users: %w", err)
+	}
+
+	return nil
+}
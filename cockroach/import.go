@@ -0,0 +1,68 @@
+package cockroach
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgxutil"
+	"github.com/nakamauwu/nakama/types"
+	"github.com/nicolasparada/go-db"
+	"github.com/nicolasparada/go-errs"
+)
+
+// ImportPost creates the post and records its origin.
+// It returns a conflict error when the post was already imported.
+func (c *Cockroach) ImportPost(ctx context.Context, in types.ImportPost) (types.CreatedTimelineItem, error) {
+	var out types.CreatedTimelineItem
+
+	return out, c.db.RunTx(ctx, func(ctx context.Context) error {
+		var err error
+		out, err = c.CreatePost(ctx, in.CreatePost)
+		if err != nil {
+			return err
+		}
+
+		const query = `
+			INSERT INTO imported_posts (user_id, source, source_id, post_id)
+			VALUES (@user_id, @source, @source_id, @post_id)
+		`
+		args := pgx.StrictNamedArgs{
+			"user_id":   in.UserID(),
+			"source":    in.Source,
+			"source_id": in.SourceID,
+			"post_id":   out.PostID,
+		}
+		_, err = c.db.Exec(ctx, query, args)
+		if db.IsUniqueViolationError(err) {
+			return errs.ConflictError("post already imported")
+		}
+
+		if err != nil {
+			return fmt.Errorf("sql insert imported post: %w", err)
+		}
+
+		return nil
+	})
+}
+
+func (c *Cockroach) ImportedPostExists(ctx context.Context, userID string, source types.ImportSource, sourceID string) (bool, error) {
+	const query = `
+		SELECT EXISTS (
+			SELECT 1 FROM imported_posts
+			WHERE user_id = @user_id AND source = @source AND source_id = @source_id
+		)
+	`
+	args := pgx.StrictNamedArgs{
+		"user_id":   userID,
+		"source":    source,
+		"source_id": sourceID,
+	}
+
+	exists, err := pgxutil.SelectRow(ctx, c.db, query, []any{args}, pgx.RowTo[bool])
+	if err != nil {
+		return false, fmt.Errorf("sql select imported post existence: %w", err)
+	}
+
+	return exists, nil
+}
@@ -0,0 +1,53 @@
+package cockroach
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgxutil"
+	"github.com/nakamauwu/nakama/types"
+)
+
+// LinkPreviewCached reports whether the URL was unfurled within maxAge.
+func (c *Cockroach) LinkPreviewCached(ctx context.Context, url string, maxAge time.Duration) (bool, error) {
+	const query = `
+		SELECT EXISTS (
+			SELECT 1 FROM link_previews
+			WHERE url = @url AND fetched_at > now() - @max_age::INTERVAL
+		)
+	`
+	args := pgx.StrictNamedArgs{
+		"url":     url,
+		"max_age": maxAge,
+	}
+	cached, err := pgxutil.SelectRow(ctx, c.db, query, []any{args}, pgx.RowTo[bool])
+	if err != nil {
+		return false, fmt.Errorf("sql select link preview cached: %w", err)
+	}
+
+	return cached, nil
+}
+
+// UpsertLinkPreview caches the metadata unfurled from the URL.
+// A preview with no title, description nor image records that there is nothing to show.
+func (c *Cockroach) UpsertLinkPreview(ctx context.Context, in types.LinkPreview) error {
+	const query = `
+		UPSERT INTO link_previews (url, title, description, image_url, site_name, fetched_at)
+		VALUES (@url, @title, @description, @image_url, @site_name, now())
+	`
+	args := pgx.StrictNamedArgs{
+		"url":         in.URL,
+		"title":       in.Title,
+		"description": in.Description,
+		"image_url":   in.ImageURL,
+		"site_name":   in.SiteName,
+	}
+	_, err := c.db.Exec(ctx, query, args)
+	if err != nil {
+		return fmt.Errorf("sql upsert link preview: %w", err)
+	}
+
+	return nil
+}
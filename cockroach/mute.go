@@ -0,0 +1,154 @@
+package cockroach
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgxutil"
+	"github.com/nakamauwu/nakama/types"
+	"github.com/nicolasparada/go-db"
+	"github.com/nicolasparada/go-errs"
+)
+
+// sqlNotMuted filters out content from users the given user muted.
+// Expired mutes are ignored.
+// Both arguments are SQL expressions, like a column or a query arg.
+func sqlNotMuted(muterID, mutedID string) string {
+	return fmt.Sprintf(`NOT EXISTS (
+		SELECT 1 FROM mutes
+		WHERE mutes.muter_id = %s AND mutes.muted_id = %s
+		AND (mutes.expires_at IS NULL OR mutes.expires_at > now())
+	)`, muterID, mutedID)
+}
+
+// Mute the given user. Muting an already muted user updates the expiration.
+func (c *Cockroach) Mute(ctx context.Context, muterID, mutedID string, expiresAt *time.Time) error {
+	const query = `
+		INSERT INTO mutes (muter_id, muted_id, expires_at)
+		VALUES (@muter_id, @muted_id, @expires_at)
+		ON CONFLICT (muter_id, muted_id) DO UPDATE SET expires_at = excluded.expires_at, created_at = now()
+	`
+	args := pgx.StrictNamedArgs{
+		"muter_id":   muterID,
+		"muted_id":   mutedID,
+		"expires_at": expiresAt,
+	}
+	_, err := c.db.Exec(ctx, query, args)
+	if db.IsForeignKeyViolationError(err, "muted_id") {
+		return errs.NotFoundError("user not found")
+	}
+
+	if err != nil {
+		return fmt.Errorf("sql upsert mute: %w", err)
+	}
+
+	return nil
+}
+
+func (c *Cockroach) Unmute(ctx context.Context, muterID, mutedID string) error {
+	const query = `
+		DELETE FROM mutes
+		WHERE muter_id = @muter_id AND muted_id = @muted_id
+	`
+	args := pgx.StrictNamedArgs{
+		"muter_id": muterID,
+		"muted_id": mutedID,
+	}
+	_, err := c.db.Exec(ctx, query, args)
+	if err != nil {
+		return fmt.Errorf("sql delete mute: %w", err)
+	}
+
+	return nil
+}
+
+// MuteExists reports whether the given user has an active mute on the other one.
+func (c *Cockroach) MuteExists(ctx context.Context, muterID, mutedID string) (bool, error) {
+	query := fmt.Sprintf("SELECT NOT %s", sqlNotMuted("@muter_id", "@muted_id"))
+	args := pgx.StrictNamedArgs{
+		"muter_id": muterID,
+		"muted_id": mutedID,
+	}
+	muted, err := pgxutil.SelectRow(ctx, c.db, query, []any{args}, pgx.RowTo[bool])
+	if err != nil {
+		return false, fmt.Errorf("sql select muted: %w", err)
+	}
+
+	return muted, nil
+}
+
+// Mutes lists the users muted by the given user. Expired mutes are not included.
+func (c *Cockroach) Mutes(ctx context.Context, in types.ListMutes) (types.Page[types.UserProfile], error) {
+	var out types.Page[types.UserProfile]
+
+	args := pgx.StrictNamedArgs{"viewer_id": in.UserID()}
+	selects := []string{sqlUserProfileCols}
+	joins := []string{
+		`INNER JOIN users ON users.id = mutes.muted_id`,
+	}
+	filters := []string{
+		`mutes.muter_id = @viewer_id`,
+		`(mutes.expires_at IS NULL OR mutes.expires_at > now())`,
+	}
+
+	selects, joins = appendViewerRelationshipFields(selects, joins)
+
+	pageArgs, err := ParsePageArgs[any](in.PageArgs)
+	if err != nil {
+		return out, err
+	}
+
+	if pageArgs.After != nil {
+		filters = append(filters, "users.username < @after_username")
+		args["after_username"] = pageArgs.After.ID // Cursor ID is the username in this case
+	} else if pageArgs.Before != nil {
+		filters = append(filters, "users.username > @before_username")
+		args["before_username"] = pageArgs.Before.ID // Cursor ID is the username in this case
+	}
+
+	var order, limit string
+	if pageArgs.IsBackwards() {
+		order = "ORDER BY users.username ASC, users.id ASC"
+		limit = fmt.Sprintf("LIMIT %d", or(pageArgs.Last, defaultPageSize)+1) // +1 to check if there's a next page
+	} else {
+		order = "ORDER BY users.username DESC, users.id DESC"
+		limit = fmt.Sprintf("LIMIT %d", or(pageArgs.First, defaultPageSize)+1) // +1 to check if there's a next page
+	}
+
+	query := fmt.Sprintf(`
+		SELECT %s
+		FROM mutes
+		%s
+		WHERE %s
+		%s
+		%s`,
+		strings.Join(selects, ",\n\t\t"),
+		strings.Join(joins, "\n\t\t"),
+		strings.Join(filters, " AND "),
+		order,
+		limit,
+	)
+
+	users, err := pgxutil.Select(ctx, c.db, query, []any{args}, pgx.RowToStructByNameLax[types.UserProfile])
+	if err != nil {
+		return out, fmt.Errorf("sql select mutes: %w", err)
+	}
+
+	out.Items = users
+
+	return out, applyPageInfo(&out, pageArgs, userProfileCursor)
+}
+
+// DeleteExpiredMutes cleans up mutes that are no longer in effect.
+func (c *Cockroach) DeleteExpiredMutes(ctx context.Context) error {
+	const query = "DELETE FROM mutes WHERE expires_at IS NOT NULL AND expires_at <= now()"
+	_, err := c.db.Exec(ctx, query)
+	if err != nil {
+		return fmt.Errorf("sql delete expired mutes: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,118 @@
+package cockroach
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgxutil"
+	"github.com/nakamauwu/nakama/types"
+	"github.com/nicolasparada/go-db"
+	"github.com/nicolasparada/go-errs"
+)
+
+const sqlMutedWordCols = `
+	  muted_words.id
+	, muted_words.user_id
+	, muted_words.keyword
+	, muted_words.scope
+	, muted_words.action
+	, muted_words.created_at
+`
+
+func (c *Cockroach) CreateMutedWord(ctx context.Context, in types.CreateMutedWord) (types.Created, error) {
+	var out types.Created
+	return out, c.db.RunTx(ctx, func(ctx context.Context) error {
+		const countQuery = `SELECT count(*) FROM muted_words WHERE user_id = @user_id`
+		countArgs := pgx.StrictNamedArgs{"user_id": in.UserID()}
+		count, err := pgxutil.SelectRow(ctx, c.db, countQuery, []any{countArgs}, pgx.RowTo[int])
+		if err != nil {
+			return fmt.Errorf("sql select muted words count: %w", err)
+		}
+
+		if count >= types.MaxMutedWords {
+			return errs.ConflictError(fmt.Sprintf("cannot mute more than %d words", types.MaxMutedWords))
+		}
+
+		const query = `
+			INSERT INTO muted_words (user_id, keyword, scope, action)
+			VALUES (@user_id, @keyword, @scope, @action)
+			RETURNING id, created_at
+		`
+		args := pgx.StrictNamedArgs{
+			"user_id": in.UserID(),
+			"keyword": in.Keyword,
+			"scope":   in.Scope,
+			"action":  in.Action,
+		}
+		out, err = pgxutil.SelectRow(ctx, c.db, query, []any{args}, pgx.RowToStructByNameLax[types.Created])
+		if db.IsUniqueViolationError(err) {
+			return errs.ConflictError("keyword already muted")
+		}
+
+		if err != nil {
+			return fmt.Errorf("sql insert muted word: %w", err)
+		}
+
+		return nil
+	})
+}
+
+// MutedWords from the given user, most recent first.
+func (c *Cockroach) MutedWords(ctx context.Context, userID string) ([]types.MutedWord, error) {
+	query := fmt.Sprintf(`
+		SELECT %s
+		FROM muted_words
+		WHERE user_id = @user_id
+		ORDER BY created_at DESC, id DESC
+	`, sqlMutedWordCols)
+	args := pgx.StrictNamedArgs{"user_id": userID}
+
+	words, err := pgxutil.Select(ctx, c.db, query, []any{args}, pgx.RowToStructByNameLax[types.MutedWord])
+	if err != nil {
+		return nil, fmt.Errorf("sql select muted words: %w", err)
+	}
+
+	return words, nil
+}
+
+// MutedWordsByUsernames returns the muted words of all the given users at once,
+// with the username of their owner set.
+func (c *Cockroach) MutedWordsByUsernames(ctx context.Context, usernames []string) ([]types.MutedWord, error) {
+	if len(usernames) == 0 {
+		return nil, nil
+	}
+
+	query := fmt.Sprintf(`
+		SELECT %s, users.username
+		FROM muted_words
+		INNER JOIN users ON users.id = muted_words.user_id
+		WHERE users.username = ANY(@usernames)
+	`, sqlMutedWordCols)
+	args := pgx.StrictNamedArgs{"usernames": usernames}
+
+	words, err := pgxutil.Select(ctx, c.db, query, []any{args}, pgx.RowToStructByNameLax[types.MutedWord])
+	if err != nil {
+		return nil, fmt.Errorf("sql select muted words by usernames: %w", err)
+	}
+
+	return words, nil
+}
+
+func (c *Cockroach) DeleteMutedWord(ctx context.Context, userID, mutedWordID string) error {
+	const query = `DELETE FROM muted_words WHERE id = @muted_word_id AND user_id = @user_id`
+	args := pgx.StrictNamedArgs{
+		"muted_word_id": mutedWordID,
+		"user_id":       userID,
+	}
+	tag, err := c.db.Exec(ctx, query, args)
+	if err != nil {
+		return fmt.Errorf("sql delete muted word: %w", err)
+	}
+
+	if tag.RowsAffected() == 0 {
+		return errs.NotFoundError("muted word not found")
+	}
+
+	return nil
+}
@@ -21,6 +21,7 @@ const notificationsCols = `
 	, notifications.kind
 	, notifications.post_id
 	, notifications.comment_id
+	, notifications.draft_id
 	, notifications.read_at
 	, notifications.issued_at
 	, (notifications.read_at IS NOT NULL) AS read
@@ -215,10 +216,28 @@ func (c *Cockroach) MarkNotificationsAsRead(ctx context.Context, userID string)
 }
 
 func (c *Cockroach) CreateFollowNotification(ctx context.Context, userID, actorUserID string) (*string, error) {
+	return c.createUserNotification(ctx, types.NotificationKindFollow, userID, actorUserID)
+}
+
+// CreateFollowRequestNotification notifies a private account
+// about a new follow request.
+func (c *Cockroach) CreateFollowRequestNotification(ctx context.Context, userID, actorUserID string) (*string, error) {
+	return c.createUserNotification(ctx, types.NotificationKindFollowRequest, userID, actorUserID)
+}
+
+// CreateFollowAcceptedNotification notifies the follower
+// that the private account approved their follow request.
+func (c *Cockroach) CreateFollowAcceptedNotification(ctx context.Context, userID, actorUserID string) (*string, error) {
+	return c.createUserNotification(ctx, types.NotificationKindFollowAccepted, userID, actorUserID)
+}
+
+// createUserNotification groups the notifications without post
+// in a single unread notification per kind.
+func (c *Cockroach) createUserNotification(ctx context.Context, kind types.NotificationKind, userID, actorUserID string) (*string, error) {
 	var notificationID *string
 
 	return notificationID, c.db.RunTx(ctx, func(ctx context.Context) error {
-		actorExists, err := c.notificationActorExists(ctx, userID, actorUserID, types.NotificationKindFollow)
+		actorExists, err := c.notificationActorExists(ctx, userID, actorUserID, kind)
 		if err != nil {
 			return err
 		}
@@ -228,7 +247,16 @@ func (c *Cockroach) CreateFollowNotification(ctx context.Context, userID, actorU
 			return nil
 		}
 
-		notificationID, err = c.notificationIDFromUnreadFollow(ctx, userID)
+		muted, err := c.MuteExists(ctx, userID, actorUserID)
+		if err != nil {
+			return err
+		}
+
+		if muted {
+			return nil
+		}
+
+		notificationID, err = c.unreadNotificationID(ctx, userID, kind)
 		if err != nil {
 			return err
 		}
@@ -237,7 +265,7 @@ func (c *Cockroach) CreateFollowNotification(ctx context.Context, userID, actorU
 		if notificationID == nil {
 			created, err := c.createNotification(ctx, types.CreateNotification{
 				UserID: userID,
-				Kind:   types.NotificationKindFollow,
+				Kind:   kind,
 			})
 			if err != nil {
 				return err
@@ -296,28 +324,86 @@ func (c *Cockroach) NotificationsByIDs(ctx context.Context, notificationIDs []st
 }
 
 func (c *Cockroach) fanoutCommentNotification(ctx context.Context, in types.FanoutCommentNotification) ([]types.CreatedNotification, error) {
-	const query = `
+	args := pgx.StrictNamedArgs{
+		"actor_user_id": in.ActorUserID,
+		"kind":          types.NotificationKindComment,
+		"post_id":       in.PostID,
+	}
+
+	var excludeParentAuthor string
+	if in.ParentID != nil {
+		// the parent comment author gets a reply notification instead.
+		args["parent_id"] = *in.ParentID
+		excludeParentAuthor = "AND post_subscriptions.user_id != (SELECT user_id FROM comments WHERE id = @parent_id)"
+	}
+
+	query := fmt.Sprintf(`
 		INSERT INTO notifications (user_id, kind, post_id)
 		SELECT post_subscriptions.user_id, @kind, post_subscriptions.post_id
 		FROM post_subscriptions
+		INNER JOIN posts ON posts.id = post_subscriptions.post_id
 		WHERE post_subscriptions.user_id != @actor_user_id
 		  AND post_subscriptions.post_id = @post_id
+		  AND %s
+		  AND %s
+		  AND %s
+		  %s
 		ON CONFLICT (user_id, kind, post_id) WHERE kind = 'comment' AND read_at IS NULL DO UPDATE SET issued_at = now()
 		RETURNING id, issued_at
-	`
+	`,
+		sqlPostVisibleTo("post_subscriptions.user_id"),
+		sqlNoBlockBetween("post_subscriptions.user_id", "@actor_user_id"),
+		sqlNotMuted("post_subscriptions.user_id", "@actor_user_id"),
+		excludeParentAuthor,
+	)
+
+	notifications, err := pgxutil.Select(ctx, c.db, query, []any{args}, pgx.RowToStructByNameLax[types.CreatedNotification])
+	if err != nil {
+		return nil, fmt.Errorf("sql fanout comment notifications: %w", err)
+	}
+
+	return notifications, nil
+}
+
+func (c *Cockroach) CreateCommentReplyNotification(ctx context.Context, in types.CreateCommentReplyNotification) ([]types.CreatedNotification, error) {
+	var createdList []types.CreatedNotification
+	return createdList, c.db.RunTx(ctx, func(ctx context.Context) error {
+		var err error
+		createdList, err = c.createCommentReplyNotification(ctx, in)
+		if err != nil {
+			return err
+		}
 
+		return c.upsertManyNotificationsActor(ctx, createdList, in.ActorUserID)
+	})
+}
+
+// createCommentReplyNotification notifies the author of the parent comment.
+// The notification points to the reply so it can be previewed.
+func (c *Cockroach) createCommentReplyNotification(ctx context.Context, in types.CreateCommentReplyNotification) ([]types.CreatedNotification, error) {
+	query := fmt.Sprintf(`
+		INSERT INTO notifications (user_id, kind, post_id, comment_id)
+		SELECT comments.user_id, @kind, @post_id, @comment_id
+		FROM comments
+		WHERE comments.id = @parent_id AND comments.user_id != @actor_user_id
+		AND %s
+		AND %s
+		RETURNING id, issued_at
+	`, sqlNoBlockBetween("comments.user_id", "@actor_user_id"), sqlNotMuted("comments.user_id", "@actor_user_id"))
 	args := pgx.StrictNamedArgs{
 		"actor_user_id": in.ActorUserID,
-		"kind":          types.NotificationKindComment,
+		"kind":          types.NotificationKindCommentReply,
 		"post_id":       in.PostID,
+		"comment_id":    in.CommentID,
+		"parent_id":     in.ParentID,
 	}
 
-	notifications, err := pgxutil.Select(ctx, c.db, query, []any{args}, pgx.RowToStructByNameLax[types.CreatedNotification])
+	createdList, err := pgxutil.Select(ctx, c.db, query, []any{args}, pgx.RowToStructByNameLax[types.CreatedNotification])
 	if err != nil {
-		return nil, fmt.Errorf("sql fanout comment notifications: %w", err)
+		return nil, fmt.Errorf("sql create comment reply notification: %w", err)
 	}
 
-	return notifications, nil
+	return createdList, nil
 }
 
 func (c *Cockroach) CreateMentionNotifications(ctx context.Context, in types.CreateMentionNotifications) ([]types.CreatedNotification, error) {
@@ -338,13 +424,31 @@ func (c *Cockroach) createMentionNotifications(ctx context.Context, in types.Cre
 		return nil, nil
 	}
 
-	const query = `
+	query := fmt.Sprintf(`
 		INSERT INTO notifications (user_id, kind, post_id, comment_id)
 		SELECT users.id, @kind, @post_id, @comment_id
 		FROM users
+		INNER JOIN posts ON posts.id = @post_id
 		WHERE users.username = ANY(@mentions) AND users.id != @actor_user_id
+		-- only users that can see the post get notified.
+		AND %s
+		AND %s
+		AND %s
+		-- mentions re-added on edit don't duplicate a notification the user has not read yet.
+		AND NOT EXISTS (
+			SELECT 1 FROM notifications
+			WHERE notifications.user_id = users.id
+			AND notifications.kind = @kind
+			AND notifications.post_id = @post_id
+			AND notifications.comment_id IS NOT DISTINCT FROM @comment_id::UUID
+			AND notifications.read_at IS NULL
+		)
 		RETURNING id, issued_at
-	`
+	`,
+		sqlPostVisibleTo("users.id"),
+		sqlNoBlockBetween("users.id", "@actor_user_id"),
+		sqlNotMuted("users.id", "@actor_user_id"),
+	)
 
 	args := pgx.StrictNamedArgs{
 		"actor_user_id": in.ActorUserID,
@@ -362,6 +466,93 @@ func (c *Cockroach) createMentionNotifications(ctx context.Context, in types.Cre
 	return createdList, nil
 }
 
+// DeleteMentionNotifications retracts the unread notifications
+// of users that are no longer mentioned after an edit.
+func (c *Cockroach) DeleteMentionNotifications(ctx context.Context, in types.DeleteMentionNotifications) error {
+	if len(in.Mentions) == 0 {
+		return nil
+	}
+
+	args := pgx.StrictNamedArgs{
+		"kind":     in.Kind,
+		"post_id":  in.PostID,
+		"mentions": in.Mentions,
+	}
+	filters := []string{
+		"notifications.kind = @kind",
+		"notifications.post_id = @post_id",
+		"notifications.read_at IS NULL",
+		"notifications.user_id IN (SELECT id FROM users WHERE username = ANY(@mentions))",
+	}
+
+	if in.CommentID != nil {
+		args["comment_id"] = *in.CommentID
+		filters = append(filters, "notifications.comment_id = @comment_id")
+	} else {
+		filters = append(filters, "notifications.comment_id IS NULL")
+	}
+
+	query := "DELETE FROM notifications WHERE " + strings.Join(filters, " AND ")
+	_, err := c.db.Exec(ctx, query, args)
+	if err != nil {
+		return fmt.Errorf("sql delete %q mention notifications: %w", in.Kind, err)
+	}
+
+	return nil
+}
+
+func (c *Cockroach) CreateRepostNotification(ctx context.Context, in types.CreateRepostNotification) ([]types.CreatedNotification, error) {
+	var createdList []types.CreatedNotification
+	return createdList, c.db.RunTx(ctx, func(ctx context.Context) error {
+		var err error
+		createdList, err = c.createRepostNotification(ctx, in)
+		if err != nil {
+			return err
+		}
+
+		return c.upsertManyNotificationsActor(ctx, createdList, in.ActorUserID)
+	})
+}
+
+// createRepostNotification notifies the author of the original post.
+// Plain reposts are aggregated into the unread notification of the original post,
+// while quotes point to the quote post itself so its content can be previewed.
+func (c *Cockroach) createRepostNotification(ctx context.Context, in types.CreateRepostNotification) ([]types.CreatedNotification, error) {
+	actorFilter := sqlNoBlockBetween("posts.user_id", "@actor_user_id") + " AND " + sqlNotMuted("posts.user_id", "@actor_user_id")
+	query := fmt.Sprintf(`
+		INSERT INTO notifications (user_id, kind, post_id)
+		SELECT posts.user_id, @kind, posts.id
+		FROM posts
+		WHERE posts.id = @repost_of_id AND posts.user_id != @actor_user_id AND %s
+		ON CONFLICT (user_id, kind, post_id) WHERE kind = 'repost' AND read_at IS NULL DO UPDATE SET issued_at = now()
+		RETURNING id, issued_at
+	`, actorFilter)
+	args := pgx.StrictNamedArgs{
+		"actor_user_id": in.ActorUserID,
+		"kind":          types.NotificationKindRepost,
+		"repost_of_id":  in.RepostOfID,
+	}
+
+	if in.Quote {
+		query = fmt.Sprintf(`
+			INSERT INTO notifications (user_id, kind, post_id)
+			SELECT posts.user_id, @kind, @post_id
+			FROM posts
+			WHERE posts.id = @repost_of_id AND posts.user_id != @actor_user_id AND %s
+			RETURNING id, issued_at
+		`, actorFilter)
+		args["kind"] = types.NotificationKindQuote
+		args["post_id"] = in.PostID
+	}
+
+	createdList, err := pgxutil.Select(ctx, c.db, query, []any{args}, pgx.RowToStructByNameLax[types.CreatedNotification])
+	if err != nil {
+		return nil, fmt.Errorf("sql create %q notification: %w", args["kind"], err)
+	}
+
+	return createdList, nil
+}
+
 func (c *Cockroach) createNotification(ctx context.Context, in types.CreateNotification) (types.CreatedNotification, error) {
 	const query = `
 		INSERT INTO notifications (user_id, kind, post_id, comment_id)
@@ -384,7 +575,7 @@ func (c *Cockroach) createNotification(ctx context.Context, in types.CreateNotif
 	return out, nil
 }
 
-func (c *Cockroach) notificationIDFromUnreadFollow(ctx context.Context, userID string) (*string, error) {
+func (c *Cockroach) unreadNotificationID(ctx context.Context, userID string, kind types.NotificationKind) (*string, error) {
 	const query = `
 		SELECT id
 		FROM notifications
@@ -395,7 +586,7 @@ func (c *Cockroach) notificationIDFromUnreadFollow(ctx context.Context, userID s
 
 	args := pgx.StrictNamedArgs{
 		"user_id": userID,
-		"kind":    types.NotificationKindFollow,
+		"kind":    kind,
 	}
 
 	notificationID, err := pgxutil.SelectRow(ctx, c.db, query, []any{args}, pgx.RowTo[string])
@@ -404,7 +595,7 @@ func (c *Cockroach) notificationIDFromUnreadFollow(ctx context.Context, userID s
 	}
 
 	if err != nil {
-		return nil, fmt.Errorf("sql select unread follow notification ID: %w", err)
+		return nil, fmt.Errorf("sql select unread %q notification ID: %w", kind, err)
 	}
 
 	return &notificationID, nil
@@ -427,7 +618,7 @@ func (c *Cockroach) notificationActorExists(ctx context.Context, userID, actorUs
 
 	var exists bool
 	if err := row.Scan(&exists); err != nil {
-		return false, fmt.Errorf("sql check follow notification by actor exists: %w", err)
+		return false, fmt.Errorf("sql check %q notification by actor exists: %w", kind, err)
 	}
 
 	return exists, nil
@@ -0,0 +1,105 @@
+package cockroach
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgxutil"
+	"github.com/nakamauwu/nakama/types"
+	"github.com/nicolasparada/go-errs"
+)
+
+// PinPost to the top of the user profile.
+// Pinning an already pinned post is a no-op.
+func (c *Cockroach) PinPost(ctx context.Context, userID, postID string) error {
+	return c.db.RunTx(ctx, func(ctx context.Context) error {
+		const countQuery = `
+			SELECT
+				  count(*)
+				, count(*) FILTER (WHERE post_id = @post_id) > 0
+			FROM pinned_posts
+			WHERE user_id = @user_id
+		`
+		args := pgx.StrictNamedArgs{
+			"user_id": userID,
+			"post_id": postID,
+		}
+		var count int
+		var pinned bool
+		if err := c.db.QueryRow(ctx, countQuery, args).Scan(&count, &pinned); err != nil {
+			return fmt.Errorf("sql select pinned posts count: %w", err)
+		}
+
+		if pinned {
+			return nil
+		}
+
+		if count >= types.MaxPinnedPosts {
+			return errs.ConflictError(fmt.Sprintf("cannot pin more than %d posts", types.MaxPinnedPosts))
+		}
+
+		const insertQuery = `
+			INSERT INTO pinned_posts (user_id, post_id)
+			VALUES (@user_id, @post_id)
+		`
+		if _, err := c.db.Exec(ctx, insertQuery, args); err != nil {
+			return fmt.Errorf("sql insert pinned post: %w", err)
+		}
+
+		return nil
+	})
+}
+
+func (c *Cockroach) UnpinPost(ctx context.Context, userID, postID string) error {
+	const query = `
+		DELETE FROM pinned_posts
+		WHERE user_id = @user_id AND post_id = @post_id
+	`
+	args := pgx.StrictNamedArgs{
+		"user_id": userID,
+		"post_id": postID,
+	}
+	if _, err := c.db.Exec(ctx, query, args); err != nil {
+		return fmt.Errorf("sql delete pinned post: %w", err)
+	}
+
+	return nil
+}
+
+// PinnedPosts from the given user, most recently pinned first.
+func (c *Cockroach) PinnedPosts(ctx context.Context, in types.ListPinnedPosts) ([]types.Post, error) {
+	args := pgx.StrictNamedArgs{"user_id": in.UserID}
+	selects := []string{sqlPostCols, sqlUserJSONB, sqlSelectRepostOf, sqlSelectLinkPreview, sqlSelectPoll(args, in.ViewerID())}
+	joins := []string{
+		"INNER JOIN posts ON pinned_posts.post_id = posts.id",
+		"INNER JOIN users ON posts.user_id = users.id",
+		sqlJoinRepostOf(args, in.ViewerID()),
+	}
+	filters := []string{"pinned_posts.user_id = @user_id", "posts.user_id = @user_id", sqlPostVisible(args, in.ViewerID())}
+
+	selects, joins = appendPostViewerFields(args, selects, joins, in.ViewerID())
+
+	if in.ViewerID() != nil {
+		filters = append(filters, sqlNoBlockBetween("@viewer_id", "posts.user_id"))
+	}
+
+	query := fmt.Sprintf(`
+		SELECT %s
+		FROM pinned_posts
+		%s
+		WHERE %s
+		ORDER BY pinned_posts.pinned_at DESC`,
+		strings.Join(selects, ",\n\t\t"),
+		strings.Join(joins, "\n\t\t"),
+		strings.Join(filters, " AND "),
+	)
+
+	posts, err := pgxutil.Select(ctx, c.db, query, []any{args}, pgx.RowToStructByNameLax[types.Post])
+	if err != nil {
+		return nil, fmt.Errorf("sql select pinned posts: %w", err)
+	}
+
+	return posts, nil
+}
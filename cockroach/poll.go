@@ -0,0 +1,234 @@
+package cockroach
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgxutil"
+	"github.com/nakamauwu/nakama/types"
+	"github.com/nicolasparada/go-db"
+	"github.com/nicolasparada/go-errs"
+)
+
+// sqlSelectPoll builds the poll of each post as JSON so it decodes into [types.Poll].
+// When a viewer is given, it adds `@viewer_id` to the query args
+// and marks the options the viewer voted for.
+func sqlSelectPoll(args pgx.StrictNamedArgs, viewerID *string) string {
+	pollVoted, optionVoted := "false", "false"
+	if viewerID != nil {
+		args["viewer_id"] = *viewerID
+		pollVoted = `EXISTS (SELECT 1 FROM poll_votes WHERE poll_votes.post_id = polls.post_id AND poll_votes.user_id = @viewer_id)`
+		optionVoted = `EXISTS (SELECT 1 FROM poll_votes WHERE poll_votes.option_id = poll_options.id AND poll_votes.user_id = @viewer_id)`
+	}
+
+	return fmt.Sprintf(`
+		(
+			SELECT jsonb_build_object(
+				'multipleChoice', polls.multiple_choice,
+				'votersCount', polls.voters_count,
+				'closesAt', polls.closes_at,
+				'closed', polls.closes_at <= now(),
+				'voted', %s,
+				'options', (
+					SELECT jsonb_agg(jsonb_build_object(
+						'id', poll_options.id,
+						'text', poll_options.text,
+						'votesCount', poll_options.votes_count,
+						'voted', %s
+					) ORDER BY poll_options.position)
+					FROM poll_options
+					WHERE poll_options.post_id = polls.post_id
+				)
+			)
+			FROM polls
+			WHERE polls.post_id = posts.id
+		) AS poll`, pollVoted, optionVoted)
+}
+
+func (c *Cockroach) createPoll(ctx context.Context, postID string, in types.CreatePoll) error {
+	const createPollQuery = `
+		INSERT INTO polls (post_id, multiple_choice, closes_at)
+		VALUES (@post_id, @multiple_choice, @closes_at)
+	`
+	args := pgx.StrictNamedArgs{
+		"post_id":         postID,
+		"multiple_choice": in.MultipleChoice,
+		"closes_at":       in.ClosesAt,
+	}
+	if _, err := c.db.Exec(ctx, createPollQuery, args); err != nil {
+		return fmt.Errorf("sql insert poll: %w", err)
+	}
+
+	const createOptionsQuery = `
+		INSERT INTO poll_options (post_id, position, text)
+		SELECT @post_id, options.position, options.text
+		FROM unnest(@options::VARCHAR[]) WITH ORDINALITY AS options (text, position)
+	`
+	args = pgx.StrictNamedArgs{
+		"post_id": postID,
+		"options": in.Options,
+	}
+	if _, err := c.db.Exec(ctx, createOptionsQuery, args); err != nil {
+		return fmt.Errorf("sql insert poll options: %w", err)
+	}
+
+	return nil
+}
+
+func (c *Cockroach) poll(ctx context.Context, postID string, viewerID *string) (types.Poll, error) {
+	args := pgx.StrictNamedArgs{"post_id": postID}
+	query := fmt.Sprintf(`SELECT %s FROM posts WHERE posts.id = @post_id`, sqlSelectPoll(args, viewerID))
+	poll, err := pgxutil.SelectRow(ctx, c.db, query, []any{args}, pgx.RowTo[*types.Poll])
+	if db.IsNotFoundError(err) || (err == nil && poll == nil) {
+		return types.Poll{}, errs.NotFoundError("poll not found")
+	}
+
+	if err != nil {
+		return types.Poll{}, fmt.Errorf("sql select poll: %w", err)
+	}
+
+	return *poll, nil
+}
+
+func (c *Cockroach) VotePoll(ctx context.Context, in types.VotePoll) (types.Poll, error) {
+	var out types.Poll
+
+	return out, c.db.RunTx(ctx, func(ctx context.Context) error {
+		multipleChoice, closed, err := c.pollStatus(ctx, in.PostID)
+		if err != nil {
+			return err
+		}
+
+		if closed {
+			return errs.GoneError("poll closed")
+		}
+
+		if !multipleChoice && len(in.OptionIDs) != 1 {
+			return errs.InvalidArgumentError("poll allows a single choice")
+		}
+
+		voted, err := c.pollVoteExists(ctx, in.PostID, in.UserID())
+		if err != nil {
+			return err
+		}
+
+		if voted {
+			return errs.ConflictError("poll already voted")
+		}
+
+		if err := c.createPollVotes(ctx, in); err != nil {
+			return err
+		}
+
+		out, err = c.poll(ctx, in.PostID, new(in.UserID()))
+		return err
+	})
+}
+
+func (c *Cockroach) pollStatus(ctx context.Context, postID string) (multipleChoice, closed bool, err error) {
+	const query = `
+		SELECT multiple_choice, closes_at <= now()
+		FROM polls
+		WHERE post_id = @post_id
+	`
+	args := pgx.StrictNamedArgs{"post_id": postID}
+	err = c.db.QueryRow(ctx, query, args).Scan(&multipleChoice, &closed)
+	if db.IsNotFoundError(err) {
+		return false, false, errs.NotFoundError("poll not found")
+	}
+
+	if err != nil {
+		return false, false, fmt.Errorf("sql select poll status: %w", err)
+	}
+
+	return multipleChoice, closed, nil
+}
+
+func (c *Cockroach) pollVoteExists(ctx context.Context, postID, userID string) (bool, error) {
+	const query = `
+		SELECT EXISTS (
+			SELECT 1
+			FROM poll_votes
+			WHERE post_id = @post_id AND user_id = @user_id
+		)
+	`
+	args := pgx.StrictNamedArgs{
+		"post_id": postID,
+		"user_id": userID,
+	}
+	exists, err := pgxutil.SelectRow(ctx, c.db, query, []any{args}, pgx.RowTo[bool])
+	if err != nil {
+		return false, fmt.Errorf("sql select poll vote exists: %w", err)
+	}
+
+	return exists, nil
+}
+
+func (c *Cockroach) createPollVotes(ctx context.Context, in types.VotePoll) error {
+	const insertQuery = `
+		INSERT INTO poll_votes (user_id, post_id, option_id)
+		SELECT @user_id, poll_options.post_id, poll_options.id
+		FROM poll_options
+		WHERE poll_options.post_id = @post_id AND poll_options.id = ANY(@option_ids)
+	`
+	args := pgx.StrictNamedArgs{
+		"user_id":    in.UserID(),
+		"post_id":    in.PostID,
+		"option_ids": in.OptionIDs,
+	}
+	tag, err := c.db.Exec(ctx, insertQuery, args)
+	if err != nil {
+		return fmt.Errorf("sql insert poll votes: %w", err)
+	}
+
+	// options from other polls are simply not inserted.
+	if tag.RowsAffected() != int64(len(in.OptionIDs)) {
+		return errs.InvalidArgumentError("invalid poll option ID")
+	}
+
+	const updateOptionsQuery = `
+		UPDATE poll_options
+		SET votes_count = votes_count + 1
+		WHERE post_id = @post_id AND id = ANY(@option_ids)
+	`
+	args = pgx.StrictNamedArgs{
+		"post_id":    in.PostID,
+		"option_ids": in.OptionIDs,
+	}
+	if _, err := c.db.Exec(ctx, updateOptionsQuery, args); err != nil {
+		return fmt.Errorf("sql increase poll options votes count: %w", err)
+	}
+
+	const updatePollQuery = "UPDATE polls SET voters_count = voters_count + 1 WHERE post_id = @post_id"
+	if _, err := c.db.Exec(ctx, updatePollQuery, pgx.StrictNamedArgs{"post_id": in.PostID}); err != nil {
+		return fmt.Errorf("sql increase poll voters count: %w", err)
+	}
+
+	return nil
+}
+
+// ClosePolls notifies the authors of polls that already closed.
+// Each poll is claimed only once, so it is safe to call it from several instances.
+func (c *Cockroach) ClosePolls(ctx context.Context) ([]types.CreatedNotification, error) {
+	const query = `
+		WITH closed AS (
+			UPDATE polls
+			SET closed_notified_at = now()
+			WHERE closed_notified_at IS NULL AND closes_at <= now()
+			RETURNING post_id
+		)
+		INSERT INTO notifications (user_id, kind, post_id)
+		SELECT posts.user_id, @kind, posts.id
+		FROM closed
+		INNER JOIN posts ON posts.id = closed.post_id
+		RETURNING id, issued_at
+	`
+	args := pgx.StrictNamedArgs{"kind": types.NotificationKindPollClosed}
+	createdList, err := pgxutil.Select(ctx, c.db, query, []any{args}, pgx.RowToStructByNameLax[types.CreatedNotification])
+	if err != nil {
+		return nil, fmt.Errorf("sql close polls: %w", err)
+	}
+
+	return createdList, nil
+}
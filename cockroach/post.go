@@ -17,19 +17,137 @@ const sqlPostCols = `
 	  posts.id
 	, posts.user_id
 	, posts.content
+	, posts.entities
 	, posts.media
 	, posts.spoiler_of
 	, posts.nsfw
 	, posts.comments_count
+	, posts.repost_of_id
+	, posts.reposts_count
+	, posts.visibility
+	, posts.edited_at IS NOT NULL AS edited
 	, posts.created_at
 	, posts.updated_at
 `
 
+// sqlSelectRepostOf embeds the reposted post as JSON so it decodes into [types.Post].
+// Make sure to include [sqlJoinRepostOf] when using this select.
+const sqlSelectRepostOf = `
+	CASE WHEN repost_of.id IS NULL THEN NULL
+	ELSE jsonb_build_object(
+		'id', repost_of.id,
+		'userID', repost_of.user_id,
+		'content', repost_of.content,
+		'entities', repost_of.entities,
+		'media', repost_of.media,
+		'spoilerOf', repost_of.spoiler_of,
+		'nsfw', repost_of.nsfw,
+		'reactions', repost_of.reactions,
+		'commentsCount', repost_of.comments_count,
+		'repostsCount', repost_of.reposts_count,
+		'visibility', repost_of.visibility,
+		'edited', repost_of.edited_at IS NOT NULL,
+		'createdAt', repost_of.created_at,
+		'updatedAt', repost_of.updated_at::TIMESTAMPTZ,
+		'linkPreview', (
+			SELECT ` + sqlLinkPreviewJSONB + `
+			FROM link_previews
+			WHERE link_previews.url = repost_of.link_url AND ` + sqlLinkPreviewFound + `
+		),
+		'user', jsonb_build_object(
+			'id', repost_of_users.id,
+			'username', repost_of_users.username,
+			'avatarURL', repost_of_users.avatar
+		)
+	) END AS repost_of`
+
+// sqlLinkPreviewJSONB builds a [types.LinkPreview] out of a link_previews row.
+const sqlLinkPreviewJSONB = `jsonb_build_object(
+	'url', link_previews.url,
+	'title', link_previews.title,
+	'description', link_previews.description,
+	'imageURL', link_previews.image_url,
+	'siteName', link_previews.site_name
+)`
+
+// sqlLinkPreviewFound leaves out the cached URLs that had nothing to show.
+const sqlLinkPreviewFound = `(link_previews.title != '' OR link_previews.description != '' OR link_previews.image_url IS NOT NULL)`
+
+// sqlSelectLinkPreview embeds the link preview of the post, if any.
+const sqlSelectLinkPreview = `(
+	SELECT ` + sqlLinkPreviewJSONB + `
+	FROM link_previews
+	WHERE link_previews.url = posts.link_url AND ` + sqlLinkPreviewFound + `
+) AS link_preview`
+
+// sqlJoinRepostOf joins the reposted post only when the viewer is allowed to see it,
+// so [sqlSelectRepostOf] gives NULL for originals that are hidden, blocked or deactivated.
+// The inner posts and users shadow the outer ones so the usual filters apply to the original.
+func sqlJoinRepostOf(args pgx.StrictNamedArgs, viewerID *string) string {
+	filters := []string{"posts.id = repost_of.id", sqlUserActive, sqlPostVisible(args, viewerID)}
+	if viewerID != nil {
+		filters = append(filters, sqlNoBlockBetween("@viewer_id", "posts.user_id"))
+	}
+
+	return `
+	LEFT JOIN posts AS repost_of ON repost_of.id = posts.repost_of_id AND EXISTS (
+		SELECT 1 FROM posts
+		INNER JOIN users ON users.id = posts.user_id
+		WHERE ` + strings.Join(filters, " AND ") + `
+	)
+	LEFT JOIN users AS repost_of_users ON repost_of_users.id = repost_of.user_id`
+}
+
+// sqlPostPublic matches public posts from accounts that are not private.
+const sqlPostPublic = `(
+	posts.visibility = 'public'
+	AND NOT EXISTS (
+		SELECT 1 FROM users AS authors
+		WHERE authors.id = posts.user_id
+		AND (authors.private OR authors.delete_after IS NOT NULL)
+	)
+)`
+
+// sqlPostAuthorActive hides the posts from accounts pending deletion.
+const sqlPostAuthorActive = `NOT EXISTS (
+	SELECT 1 FROM users AS authors
+	WHERE authors.id = posts.user_id AND authors.delete_after IS NOT NULL
+)`
+
+// sqlPostVisible filters the posts the viewer is allowed to see:
+// public posts, their own posts, followers-only posts from users they follow,
+// and any post they were mentioned in.
+// Public posts from private accounts are treated as followers-only,
+// and posts from accounts pending deletion are hidden.
+// When a viewer is given, it adds `@viewer_id` to the query args.
+func sqlPostVisible(args pgx.StrictNamedArgs, viewerID *string) string {
+	if viewerID == nil {
+		return sqlPostPublic
+	}
+
+	args["viewer_id"] = *viewerID
+	return sqlPostVisibleTo("@viewer_id")
+}
+
+// sqlPostVisibleTo is like [sqlPostVisible] but takes the viewer ID as an SQL expression,
+// so it can be used against a column.
+func sqlPostVisibleTo(viewerID string) string {
+	return fmt.Sprintf(`(%[3]s AND (
+		%[2]s
+		OR posts.user_id = %[1]s
+		OR %[1]s = ANY(posts.mentioned_user_ids)
+		OR (posts.visibility != 'mentioned' AND EXISTS (
+			SELECT 1 FROM follows WHERE follows.follower_id = %[1]s AND follows.followee_id = posts.user_id
+		))
+	))`, viewerID, sqlPostPublic, sqlPostAuthorActive)
+}
+
 // sqlSelectPostsReactions adds a `reacted` field to each reaction, producing something like this:
 //
 //	[
 //		{ "kind": "emoji", "reaction": "❤️", "count": 3, "reacted": true },
-//		{ "kind": "emoji", "reaction": "😂", "count": 2, "reacted": false }
+//		{ "kind": "emoji", "reaction": "😂", "count": 2, "reacted": false },
+//		{ "kind": "custom", "reaction": "blobcat", "count": 1, "imageURL": "blobcat.png", "reacted": false }
 //	]
 const sqlSelectPostsReactions = `
 	CASE WHEN posts.reactions IS NULL THEN NULL
@@ -70,11 +188,39 @@ func (c *Cockroach) CreatePost(ctx context.Context, in types.CreatePost) (types.
 	var out types.CreatedTimelineItem
 
 	return out, c.db.RunTx(ctx, func(ctx context.Context) error {
+		if in.RepostOfID != nil {
+			repostOfID, err := c.repostTarget(ctx, *in.RepostOfID, in.UserID())
+			if err != nil {
+				return err
+			}
+
+			in.RepostOfID = &repostOfID
+		}
+
 		createdPost, err := c.createPost(ctx, in)
 		if err != nil {
 			return err
 		}
 
+		if in.RepostOfID != nil {
+			if err := c.increasePostRepostsCount(ctx, *in.RepostOfID); err != nil {
+				return err
+			}
+		}
+
+		if in.Poll != nil {
+			if err := c.createPoll(ctx, createdPost.ID, *in.Poll); err != nil {
+				return err
+			}
+
+			poll, err := c.poll(ctx, createdPost.ID, new(in.UserID()))
+			if err != nil {
+				return err
+			}
+
+			out.Poll = &poll
+		}
+
 		if err := c.upsertPostSubscription(ctx, in.UserID(), createdPost.ID); err != nil {
 			return err
 		}
@@ -93,6 +239,7 @@ func (c *Cockroach) CreatePost(ctx context.Context, in types.CreatePost) (types.
 
 		out.TimelineItemID = timelineItemID
 		out.PostID = createdPost.ID
+		out.RepostOfID = in.RepostOfID
 		out.CreatedAt = createdPost.CreatedAt
 
 		return nil
@@ -102,20 +249,38 @@ func (c *Cockroach) CreatePost(ctx context.Context, in types.CreatePost) (types.
 func (c *Cockroach) createPost(ctx context.Context, in types.CreatePost) (types.Created, error) {
 	var out types.Created
 
-	const query = `
-		INSERT INTO posts (user_id, content, spoiler_of, nsfw, media)
-		VALUES (@user_id, @content, @spoiler_of, @nsfw, @media)
+	query := fmt.Sprintf(`
+		INSERT INTO posts (user_id, content, entities, link_url, spoiler_of, nsfw, media, repost_of_id, visibility, mentioned_user_ids, created_at, updated_at)
+		VALUES (
+			@user_id, @content, @entities, @link_url, @spoiler_of, @nsfw, @media, @repost_of_id, @visibility,
+			COALESCE((
+				SELECT array_agg(users.id) FROM users
+				WHERE users.username = ANY(@mentions) AND users.id != @user_id AND %s
+			), '{}'),
+			COALESCE(@created_at, now()),
+			COALESCE(@created_at, now())
+		)
 		RETURNING id, created_at
-	`
+	`, sqlNoBlockBetween("users.id", "@user_id"))
 	args := pgx.StrictNamedArgs{
-		"user_id":    in.UserID(),
-		"content":    in.Content,
-		"spoiler_of": in.SpoilerOf,
-		"nsfw":       in.NSFW,
-		"media":      in.Media(),
+		"user_id":      in.UserID(),
+		"content":      in.Content,
+		"entities":     in.Entities(),
+		"link_url":     types.LinkURL(in.Entities()),
+		"spoiler_of":   in.SpoilerOf,
+		"nsfw":         in.NSFW,
+		"media":        in.Media(),
+		"repost_of_id": in.RepostOfID,
+		"visibility":   in.Visibility,
+		"mentions":     in.Mentions(),
+		"created_at":   in.CreatedAt(),
 	}
 
 	out, err := pgxutil.SelectRow(ctx, c.db, query, []any{args}, pgx.RowToStructByNameLax[types.Created])
+	if db.IsUniqueViolationError(err, "unique_plain_reposts") {
+		return out, errs.ConflictError("post already reposted")
+	}
+
 	if err != nil {
 		return out, fmt.Errorf("sql insert post: %w", err)
 	}
@@ -123,13 +288,86 @@ func (c *Cockroach) createPost(ctx context.Context, in types.CreatePost) (types.
 	return out, nil
 }
 
+// repostTarget returns the ID of the post that ends up being reposted.
+// Plain reposts have nothing to quote, so reposting one reposts its original post instead.
+func (c *Cockroach) repostTarget(ctx context.Context, postID, userID string) (string, error) {
+	query := fmt.Sprintf(`
+		SELECT
+			  CASE WHEN posts.repost_of_id IS NOT NULL AND posts.content = '' THEN posts.repost_of_id ELSE posts.id END
+			, COALESCE(repost_of.visibility, posts.visibility)
+			, authors.private
+			, NOT %s
+		FROM posts
+		LEFT JOIN posts AS repost_of ON repost_of.id = posts.repost_of_id AND posts.content = ''
+		INNER JOIN users AS authors ON authors.id = COALESCE(repost_of.user_id, posts.user_id)
+		WHERE posts.id = @post_id
+	`, sqlNoBlockBetween("@user_id", "COALESCE(repost_of.user_id, posts.user_id)"))
+	args := pgx.StrictNamedArgs{
+		"post_id": postID,
+		"user_id": userID,
+	}
+	var repostOfID string
+	var visibility types.PostVisibility
+	var private, blocked bool
+	err := c.db.QueryRow(ctx, query, args).Scan(&repostOfID, &visibility, &private, &blocked)
+	if db.IsNotFoundError(err) || blocked {
+		return "", errs.NotFoundError("repost of post not found")
+	}
+
+	if err != nil {
+		return "", fmt.Errorf("sql select repost target: %w", err)
+	}
+
+	if visibility != types.PostVisibilityPublic || private {
+		return "", errs.PermissionDeniedError("only public posts can be reposted")
+	}
+
+	return repostOfID, nil
+}
+
+// appendPostViewerFields adds the fields that depend on the viewer
+// like mine, subscribed, bookmarked and the viewer reactions.
+// When a viewer is given, it adds `@viewer_id` to the query args.
+func appendPostViewerFields(args pgx.StrictNamedArgs, selects, joins []string, viewerID *string) ([]string, []string) {
+	if viewerID == nil {
+		selects = append(selects,
+			`false AS mine`,
+			`false AS subscribed`,
+			`false AS bookmarked`,
+			`posts.reactions`)
+		return selects, joins
+	}
+
+	args["viewer_id"] = *viewerID
+	selects = append(selects,
+		`(posts.user_id = @viewer_id) AS mine`,
+		`(post_subscriptions.user_id IS NOT NULL) AS subscribed`,
+		`(bookmarks.user_id IS NOT NULL) AS bookmarked`,
+		sqlSelectPostsReactions)
+	joins = append(joins,
+		`LEFT JOIN post_subscriptions ON post_subscriptions.post_id = posts.id AND post_subscriptions.user_id = @viewer_id`,
+		`LEFT JOIN bookmarks ON bookmarks.post_id = posts.id AND bookmarks.user_id = @viewer_id`,
+		sqlJoinPostReactions(args, *viewerID))
+
+	return selects, joins
+}
+
 func (c *Cockroach) Posts(ctx context.Context, in types.ListPosts) (types.Page[types.Post], error) {
 	var out types.Page[types.Post]
 
 	args := pgx.StrictNamedArgs{}
-	selects := []string{sqlPostCols, sqlUserJSONB}
-	joins := []string{"INNER JOIN users ON posts.user_id = users.id"}
-	filters := []string{}
+	selects := []string{sqlPostCols, sqlUserJSONB, sqlSelectRepostOf, sqlSelectLinkPreview, sqlSelectPoll(args, in.ViewerID())}
+	joins := []string{"INNER JOIN users ON posts.user_id = users.id", sqlJoinRepostOf(args, in.ViewerID())}
+	filters := []string{sqlPostVisible(args, in.ViewerID())}
+
+	if in.ViewerID() != nil {
+		filters = append(filters, sqlNoBlockBetween("@viewer_id", "posts.user_id"))
+
+		// muted users are still visible on their own profile.
+		if in.Username == nil {
+			filters = append(filters, sqlNotMuted("@viewer_id", "posts.user_id"))
+		}
+	}
 
 	if in.Username != nil {
 		args["username"] = *in.Username
@@ -141,21 +379,7 @@ func (c *Cockroach) Posts(ctx context.Context, in types.ListPosts) (types.Page[t
 		joins = append(joins, "INNER JOIN post_tags ON post_tags.post_id = posts.id AND post_tags.tag = @tag")
 	}
 
-	if in.ViewerID() != nil {
-		args["viewer_id"] = *in.ViewerID()
-		selects = append(selects,
-			`(posts.user_id = @viewer_id) AS mine`,
-			`(post_subscriptions.user_id IS NOT NULL) AS subscribed`,
-			sqlSelectPostsReactions)
-		joins = append(joins,
-			`LEFT JOIN post_subscriptions ON post_subscriptions.post_id = posts.id AND post_subscriptions.user_id = @viewer_id`,
-			sqlJoinPostReactions(args, *in.ViewerID()))
-	} else {
-		selects = append(selects,
-			`false AS mine`,
-			`false AS subscribed`,
-			`posts.reactions`)
-	}
+	selects, joins = appendPostViewerFields(args, selects, joins, in.ViewerID())
 
 	pageArgs, err := ParsePageArgs[time.Time](in.PageArgs)
 	if err != nil {
@@ -216,26 +440,16 @@ func (c *Cockroach) Post(ctx context.Context, in types.RetrievePost) (types.Post
 	var out types.Post
 
 	args := pgx.StrictNamedArgs{"post_id": in.PostID}
-	selects := []string{sqlPostCols, sqlUserJSONB}
-	joins := []string{"INNER JOIN users ON posts.user_id = users.id"}
-	filters := []string{"posts.id = @post_id"}
+	selects := []string{sqlPostCols, sqlUserJSONB, sqlSelectRepostOf, sqlSelectLinkPreview, sqlSelectPoll(args, in.ViewerID())}
+	joins := []string{"INNER JOIN users ON posts.user_id = users.id", sqlJoinRepostOf(args, in.ViewerID())}
+	filters := []string{"posts.id = @post_id", sqlPostVisible(args, in.ViewerID())}
 
 	if in.ViewerID() != nil {
-		args["viewer_id"] = *in.ViewerID()
-		selects = append(selects,
-			`(posts.user_id = @viewer_id) AS mine`,
-			`(post_subscriptions.user_id IS NOT NULL) AS subscribed`,
-			sqlSelectPostsReactions)
-		joins = append(joins,
-			`LEFT JOIN post_subscriptions ON post_subscriptions.post_id = posts.id AND post_subscriptions.user_id = @viewer_id`,
-			sqlJoinPostReactions(args, *in.ViewerID()))
-	} else {
-		selects = append(selects,
-			`false AS mine`,
-			`false AS subscribed`,
-			`posts.reactions`)
+		filters = append(filters, sqlNoBlockBetween("@viewer_id", "posts.user_id"))
 	}
 
+	selects, joins = appendPostViewerFields(args, selects, joins, in.ViewerID())
+
 	query := fmt.Sprintf(`
 		SELECT %s
 		FROM posts
@@ -258,6 +472,32 @@ func (c *Cockroach) Post(ctx context.Context, in types.RetrievePost) (types.Post
 	return post, nil
 }
 
+// PostVisible returns a not found error when the post does not exist
+// or the viewer is not allowed to see it, either because of its visibility
+// or because of a block between the viewer and the author.
+func (c *Cockroach) PostVisible(ctx context.Context, postID string, viewerID *string) error {
+	args := pgx.StrictNamedArgs{"post_id": postID}
+	filters := []string{"posts.id = @post_id", sqlPostVisible(args, viewerID)}
+	if viewerID != nil {
+		filters = append(filters, sqlNoBlockBetween("@viewer_id", "posts.user_id"))
+	}
+
+	query := fmt.Sprintf(`
+		SELECT EXISTS (
+			SELECT 1 FROM posts WHERE %s
+		)`, strings.Join(filters, " AND "))
+	visible, err := pgxutil.SelectRow(ctx, c.db, query, []any{args}, pgx.RowTo[bool])
+	if err != nil {
+		return fmt.Errorf("sql select post visible: %w", err)
+	}
+
+	if !visible {
+		return errs.NotFoundError("post not found")
+	}
+
+	return nil
+}
+
 func (c *Cockroach) PostUserID(ctx context.Context, postID string) (string, error) {
 	const query = "SELECT user_id FROM posts WHERE id = @post_id"
 	args := pgx.StrictNamedArgs{"post_id": postID}
@@ -288,16 +528,70 @@ func (c *Cockroach) PostCreatedAt(ctx context.Context, postID string) (time.Time
 	return createdAt, nil
 }
 
+func (c *Cockroach) postContent(ctx context.Context, postID string) (string, error) {
+	const query = "SELECT content FROM posts WHERE id = @post_id"
+	args := pgx.StrictNamedArgs{"post_id": postID}
+	content, err := pgxutil.SelectRow(ctx, c.db, query, []any{args}, pgx.RowTo[string])
+	if db.IsNotFoundError(err) {
+		return "", errs.NotFoundError("post not found")
+	}
+
+	if err != nil {
+		return "", fmt.Errorf("sql select post content: %w", err)
+	}
+
+	return content, nil
+}
+
+func (c *Cockroach) postIsPlainRepost(ctx context.Context, postID string) (bool, error) {
+	const query = "SELECT repost_of_id IS NOT NULL AND content = '' FROM posts WHERE id = @post_id"
+	args := pgx.StrictNamedArgs{"post_id": postID}
+	plainRepost, err := pgxutil.SelectRow(ctx, c.db, query, []any{args}, pgx.RowTo[bool])
+	if db.IsNotFoundError(err) {
+		return false, errs.NotFoundError("post not found")
+	}
+
+	if err != nil {
+		return false, fmt.Errorf("sql select post is plain repost: %w", err)
+	}
+
+	return plainRepost, nil
+}
+
+// UpdatePost fails with a permission denied error when trying to add content
+// to a plain repost, since that would turn it into a quote without anyone being notified.
 func (c *Cockroach) UpdatePost(ctx context.Context, in types.UpdatePost) (types.UpdatedPost, error) {
 	var out types.UpdatedPost
 
 	return out, c.db.RunTx(ctx, func(ctx context.Context) error {
-		var err error
-		out, err = c.updatePost(ctx, in)
+		previousContent, err := c.postContent(ctx, in.ID)
+		if err != nil {
+			return err
+		}
+
+		if in.Content != nil && previousContent == "" {
+			plainRepost, err := c.postIsPlainRepost(ctx, in.ID)
+			if err != nil {
+				return err
+			}
+
+			if plainRepost {
+				return errs.PermissionDeniedError("cannot add content to a plain repost")
+			}
+		}
+
+		edited, err := c.createPostRevision(ctx, in)
 		if err != nil {
 			return err
 		}
 
+		out, err = c.updatePost(ctx, in, edited)
+		if err != nil {
+			return err
+		}
+
+		out.PreviousContent = previousContent
+
 		if in.Content != nil {
 			if err := c.deletePostsTags(ctx, in.ID); err != nil {
 				return err
@@ -318,24 +612,37 @@ func (c *Cockroach) UpdatePost(ctx context.Context, in types.UpdatePost) (types.
 	})
 }
 
-func (c *Cockroach) updatePost(ctx context.Context, in types.UpdatePost) (types.UpdatedPost, error) {
+func (c *Cockroach) updatePost(ctx context.Context, in types.UpdatePost, edited bool) (types.UpdatedPost, error) {
 	var out types.UpdatedPost
 
-	const query = `
+	// mentioned_user_ids follows the content, so users mentioned in an edit
+	// get access to the post and users no longer mentioned lose it.
+	query := fmt.Sprintf(`
 		UPDATE posts
 		SET
 			  content = COALESCE(@content, content)
+			, entities = CASE WHEN @content::STRING IS NULL THEN entities ELSE @entities END
+			, link_url = CASE WHEN @content::STRING IS NULL THEN link_url ELSE @link_url END
+			, mentioned_user_ids = CASE WHEN @content::STRING IS NULL THEN mentioned_user_ids ELSE COALESCE((
+				SELECT array_agg(users.id) FROM users
+				WHERE users.username = ANY(@mentions) AND users.id != posts.user_id AND %s
+			), '{}') END
 			, spoiler_of = COALESCE(@spoiler_of, spoiler_of)
 			, nsfw = COALESCE(@nsfw, nsfw)
+			, edited_at = CASE WHEN @edited THEN now() ELSE edited_at END
 			, updated_at = now()
 		WHERE id = @post_id
-		RETURNING content, spoiler_of, nsfw, updated_at
-	`
+		RETURNING content, entities, spoiler_of, nsfw, edited_at IS NOT NULL AS edited, updated_at
+	`, sqlNoBlockBetween("users.id", "posts.user_id"))
 	args := pgx.StrictNamedArgs{
 		"post_id":    in.ID,
 		"content":    in.Content,
+		"entities":   in.Entities(),
+		"mentions":   in.Mentions(),
+		"link_url":   types.LinkURL(in.Entities()),
 		"spoiler_of": in.SpoilerOf,
 		"nsfw":       in.NSFW,
+		"edited":     edited,
 	}
 	out, err := pgxutil.SelectRow(ctx, c.db, query, []any{args}, pgx.RowToStructByNameLax[types.UpdatedPost])
 	if db.IsNotFoundError(err) {
@@ -359,9 +666,9 @@ func (c *Cockroach) increasePostCommentsCount(ctx context.Context, postID string
 	return nil
 }
 
-func (c *Cockroach) decreasePostCommentsCount(ctx context.Context, postID string) error {
-	const query = "UPDATE posts SET comments_count = comments_count - 1 WHERE id = @post_id"
-	_, err := c.db.Exec(ctx, query, pgx.StrictNamedArgs{"post_id": postID})
+func (c *Cockroach) decreasePostCommentsCount(ctx context.Context, postID string, n int) error {
+	const query = "UPDATE posts SET comments_count = greatest(comments_count - @n, 0) WHERE id = @post_id"
+	_, err := c.db.Exec(ctx, query, pgx.StrictNamedArgs{"post_id": postID, "n": n})
 	if err != nil {
 		return fmt.Errorf("sql decrease post comments count: %w", err)
 	}
@@ -369,20 +676,51 @@ func (c *Cockroach) decreasePostCommentsCount(ctx context.Context, postID string
 	return nil
 }
 
-func (c *Cockroach) DeletePost(ctx context.Context, postID string) error {
-	const query = `
-		DELETE FROM posts
-		WHERE id = @post_id
-	`
-	args := pgx.StrictNamedArgs{"post_id": postID}
-	_, err := c.db.Exec(ctx, query, args)
+func (c *Cockroach) increasePostRepostsCount(ctx context.Context, postID string) error {
+	const query = "UPDATE posts SET reposts_count = reposts_count + 1 WHERE id = @post_id"
+	_, err := c.db.Exec(ctx, query, pgx.StrictNamedArgs{"post_id": postID})
 	if err != nil {
-		return fmt.Errorf("sql delete post: %w", err)
+		return fmt.Errorf("sql increase post reposts count: %w", err)
 	}
 
 	return nil
 }
 
+func (c *Cockroach) decreasePostRepostsCount(ctx context.Context, postID string) error {
+	const query = "UPDATE posts SET reposts_count = reposts_count - 1 WHERE id = @post_id AND reposts_count > 0"
+	_, err := c.db.Exec(ctx, query, pgx.StrictNamedArgs{"post_id": postID})
+	if err != nil {
+		return fmt.Errorf("sql decrease post reposts count: %w", err)
+	}
+
+	return nil
+}
+
+func (c *Cockroach) DeletePost(ctx context.Context, postID string) error {
+	return c.db.RunTx(ctx, func(ctx context.Context) error {
+		const query = `
+			DELETE FROM posts
+			WHERE id = @post_id
+			RETURNING repost_of_id
+		`
+		args := pgx.StrictNamedArgs{"post_id": postID}
+		repostOfID, err := pgxutil.SelectRow(ctx, c.db, query, []any{args}, pgx.RowTo[*string])
+		if db.IsNotFoundError(err) {
+			return nil // idempotent
+		}
+
+		if err != nil {
+			return fmt.Errorf("sql delete post: %w", err)
+		}
+
+		if repostOfID != nil {
+			return c.decreasePostRepostsCount(ctx, *repostOfID)
+		}
+
+		return nil
+	})
+}
+
 func (c *Cockroach) TogglePostReaction(ctx context.Context, in types.TogglePostReaction) ([]types.Reaction, error) {
 	var out []types.Reaction
 
@@ -397,6 +735,12 @@ func (c *Cockroach) TogglePostReaction(ctx context.Context, in types.TogglePostR
 				return err
 			}
 		} else {
+			if in.Kind == types.ReactionKindCustom {
+				if err := c.customEmojiExists(ctx, in.Reaction); err != nil {
+					return err
+				}
+			}
+
 			if err := c.createPostReaction(ctx, in); err != nil {
 				return err
 			}
@@ -488,8 +832,14 @@ func (c *Cockroach) refreshPostReactions(ctx context.Context, postID string) err
 		)
 		UPDATE posts
 		SET reactions = (
-			SELECT jsonb_agg(jsonb_build_object('kind', kind, 'reaction', reaction, 'count', count))
+			SELECT jsonb_agg(jsonb_strip_nulls(jsonb_build_object(
+				'kind', counts.kind,
+				'reaction', counts.reaction,
+				'count', counts.count,
+				'imageURL', custom_emojis.image
+			)))
 			FROM counts
+			LEFT JOIN custom_emojis ON counts.kind = 'custom' AND custom_emojis.shortcode = counts.reaction
 		)
 		WHERE id = @post_id
 	`
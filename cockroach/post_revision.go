@@ -0,0 +1,94 @@
+package cockroach
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgxutil"
+	"github.com/nakamauwu/nakama/types"
+)
+
+// createPostRevision saves the current state of the post before updating it.
+// It reports whether the update actually changes anything,
+// no revision is created otherwise.
+func (c *Cockroach) createPostRevision(ctx context.Context, in types.UpdatePost) (bool, error) {
+	const query = `
+		INSERT INTO post_revisions (post_id, content, spoiler_of, nsfw)
+		SELECT id, content, spoiler_of, nsfw
+		FROM posts
+		WHERE id = @post_id
+		AND (
+			content != COALESCE(@content, content)
+			OR spoiler_of IS DISTINCT FROM COALESCE(@spoiler_of, spoiler_of)
+			OR nsfw != COALESCE(@nsfw, nsfw)
+		)
+	`
+	args := pgx.StrictNamedArgs{
+		"post_id":    in.ID,
+		"content":    in.Content,
+		"spoiler_of": in.SpoilerOf,
+		"nsfw":       in.NSFW,
+	}
+	tag, err := c.db.Exec(ctx, query, args)
+	if err != nil {
+		return false, fmt.Errorf("sql insert post revision: %w", err)
+	}
+
+	return tag.RowsAffected() != 0, nil
+}
+
+func (c *Cockroach) PostRevisions(ctx context.Context, in types.ListPostRevisions) (types.Page[types.PostRevision], error) {
+	var out types.Page[types.PostRevision]
+
+	args := pgx.StrictNamedArgs{"post_id": in.PostID}
+	filters := []string{"post_id = @post_id"}
+
+	pageArgs, err := ParsePageArgs[time.Time](in.PageArgs)
+	if err != nil {
+		return out, err
+	}
+
+	if pageArgs.After != nil {
+		filters = append(filters, "(created_at, id) < (@after_created_at, @after_id)")
+		args["after_created_at"] = pageArgs.After.Value
+		args["after_id"] = pageArgs.After.ID
+	} else if pageArgs.Before != nil {
+		filters = append(filters, "(created_at, id) > (@before_created_at, @before_id)")
+		args["before_created_at"] = pageArgs.Before.Value
+		args["before_id"] = pageArgs.Before.ID
+	}
+
+	var order, limit string
+	if pageArgs.IsBackwards() {
+		order = "ORDER BY created_at ASC, id ASC"
+		limit = fmt.Sprintf("LIMIT %d", or(pageArgs.Last, defaultPageSize)+1) // +1 to check if there's a next page
+	} else {
+		order = "ORDER BY created_at DESC, id DESC"
+		limit = fmt.Sprintf("LIMIT %d", or(pageArgs.First, defaultPageSize)+1) // +1 to check if there's a next page
+	}
+
+	query := fmt.Sprintf(`
+		SELECT id, post_id, content, spoiler_of, nsfw, created_at
+		FROM post_revisions
+		WHERE %s
+		%s
+		%s`,
+		strings.Join(filters, " AND "),
+		order,
+		limit,
+	)
+
+	revisions, err := pgxutil.Select(ctx, c.db, query, []any{args}, pgx.RowToStructByNameLax[types.PostRevision])
+	if err != nil {
+		return out, fmt.Errorf("sql select post revisions: %w", err)
+	}
+
+	out.Items = revisions
+
+	return out, applyPageInfo(&out, pageArgs, func(r types.PostRevision) Cursor[time.Time] {
+		return Cursor[time.Time]{ID: r.ID, Value: r.CreatedAt}
+	})
+}
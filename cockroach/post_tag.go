@@ -6,29 +6,50 @@ import (
 
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgxutil"
+	"github.com/nakamauwu/nakama/textutil"
 	"github.com/nakamauwu/nakama/types"
 )
 
+// createPostTags stores the normalized form of the given tags.
+// Tags seen for the first time are also recorded along their display form.
 func (c *Cockroach) createPostTags(ctx context.Context, in types.CreatePostTags) error {
 	if len(in.Tags) == 0 {
 		return nil
 	}
 
 	rows := make([]map[string]any, len(in.Tags))
+	names := make([]string, len(in.Tags))
 	for i, tag := range in.Tags {
+		names[i] = textutil.NormalizeTag(tag)
 		rows[i] = map[string]any{
 			"post_id":    in.PostID,
 			"comment_id": in.CommentID,
-			"tag":        tag,
+			"tag":        names[i],
 		}
 	}
 
-	_, err := pgxutil.Insert(ctx, c.db, "post_tags", rows)
-	if err != nil {
-		return fmt.Errorf("sql insert post tags: %w", err)
-	}
+	return c.db.RunTx(ctx, func(ctx context.Context) error {
+		_, err := pgxutil.Insert(ctx, c.db, "post_tags", rows)
+		if err != nil {
+			return fmt.Errorf("sql insert post tags: %w", err)
+		}
 
-	return nil
+		const query = `
+			INSERT INTO tags (tag, display_tag)
+			SELECT * FROM unnest(@tags::VARCHAR[], @display_tags::VARCHAR[])
+			ON CONFLICT (tag) DO NOTHING
+		`
+		args := pgx.StrictNamedArgs{
+			"tags":         names,
+			"display_tags": in.Tags,
+		}
+		_, err = c.db.Exec(ctx, query, args)
+		if err != nil {
+			return fmt.Errorf("sql insert tags: %w", err)
+		}
+
+		return nil
+	})
 }
 
 func (c *Cockroach) deletePostsTags(ctx context.Context, postID string) error {
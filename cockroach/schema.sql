@@ -65,6 +65,15 @@ CREATE TABLE IF NOT EXISTS posts (
     INDEX sorted_posts (created_at DESC, id)
 );
 
+ALTER TABLE posts ADD COLUMN IF NOT EXISTS repost_of_id UUID REFERENCES posts ON DELETE CASCADE;
+ALTER TABLE posts ADD COLUMN IF NOT EXISTS reposts_count INT NOT NULL DEFAULT 0 CHECK (reposts_count >= 0);
+ALTER TABLE posts ADD COLUMN IF NOT EXISTS edited_at TIMESTAMPTZ;
+
+-- plain reposts have no content of their own, so a user can plain repost the same post only once.
+CREATE UNIQUE INDEX IF NOT EXISTS unique_plain_reposts
+ON posts (user_id, repost_of_id)
+WHERE repost_of_id IS NOT NULL AND content = '';
+
 CREATE TABLE IF NOT EXISTS post_reactions (
     user_id UUID NOT NULL REFERENCES users ON DELETE CASCADE,
     post_id UUID NOT NULL REFERENCES posts ON DELETE CASCADE,
@@ -106,6 +115,14 @@ CREATE TABLE IF NOT EXISTS comments (
 CREATE INDEX IF NOT EXISTS idx_comments_post_id_sorted
 ON comments (post_id, created_at DESC, id DESC);
 
+ALTER TABLE comments ADD COLUMN IF NOT EXISTS edited_at TIMESTAMPTZ;
+ALTER TABLE comments ADD COLUMN IF NOT EXISTS parent_id UUID REFERENCES comments ON DELETE CASCADE;
+ALTER TABLE comments ADD COLUMN IF NOT EXISTS depth INT NOT NULL DEFAULT 0;
+ALTER TABLE comments ADD COLUMN IF NOT EXISTS replies_count INT NOT NULL DEFAULT 0 CHECK (replies_count >= 0);
+
+CREATE INDEX IF NOT EXISTS idx_comments_parent_id_sorted
+ON comments (parent_id, created_at DESC, id DESC);
+
 CREATE TABLE IF NOT EXISTS comment_reactions (
     user_id UUID NOT NULL REFERENCES users ON DELETE CASCADE,
     comment_id UUID NOT NULL REFERENCES comments ON DELETE CASCADE,
@@ -147,6 +164,10 @@ CREATE UNIQUE INDEX IF NOT EXISTS unique_comment_unread_notifications
 ON notifications (user_id, kind, post_id)
 WHERE kind = 'comment' AND read_at IS NULL;
 
+CREATE UNIQUE INDEX IF NOT EXISTS unique_repost_unread_notifications
+ON notifications (user_id, kind, post_id)
+WHERE kind = 'repost' AND read_at IS NULL;
+
 ALTER TABLE notifications ADD COLUMN IF NOT EXISTS actor_user_ids UUID[] NOT NULL DEFAULT '{}'; -- only the last 2 actors. Used for showing: user_a and user_b did something.
 ALTER TABLE notifications ADD COLUMN IF NOT EXISTS actors_count INT NOT NULL DEFAULT 0; -- total count used for showing: user_a and 3 others did something.
 
@@ -212,6 +233,266 @@ CREATE TABLE IF NOT EXISTS user_web_push_subscriptions (
     UNIQUE INDEX unique_user_web_push_subscriptions (user_id, (sub->>'endpoint'))
 );
 
+CREATE TABLE IF NOT EXISTS polls (
+    post_id UUID NOT NULL PRIMARY KEY REFERENCES posts ON DELETE CASCADE,
+    multiple_choice BOOLEAN NOT NULL DEFAULT false,
+    voters_count INT NOT NULL DEFAULT 0 CHECK (voters_count >= 0),
+    closes_at TIMESTAMPTZ NOT NULL,
+    closed_notified_at TIMESTAMPTZ
+);
+
+CREATE INDEX IF NOT EXISTS idx_polls_pending_close
+ON polls (closes_at)
+WHERE closed_notified_at IS NULL;
+
+CREATE TABLE IF NOT EXISTS poll_options (
+    id UUID NOT NULL PRIMARY KEY DEFAULT gen_random_uuid(),
+    post_id UUID NOT NULL REFERENCES polls ON DELETE CASCADE,
+    position INT NOT NULL,
+    text VARCHAR NOT NULL,
+    votes_count INT NOT NULL DEFAULT 0 CHECK (votes_count >= 0),
+    UNIQUE INDEX unique_poll_option_positions (post_id, position)
+);
+
+CREATE TABLE IF NOT EXISTS poll_votes (
+    user_id UUID NOT NULL REFERENCES users ON DELETE CASCADE,
+    post_id UUID NOT NULL REFERENCES polls ON DELETE CASCADE,
+    option_id UUID NOT NULL REFERENCES poll_options ON DELETE CASCADE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    PRIMARY KEY (user_id, option_id),
+    INDEX idx_poll_votes_post_user (post_id, user_id)
+);
+
+-- drafts are posts not published yet.
+-- Those with publish_at set are scheduled and published by a background job.
+CREATE TABLE IF NOT EXISTS drafts (
+    id UUID NOT NULL PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id UUID NOT NULL REFERENCES users ON DELETE CASCADE,
+    content VARCHAR NOT NULL DEFAULT '',
+    media JSONB,
+    spoiler_of VARCHAR,
+    nsfw BOOLEAN NOT NULL DEFAULT false,
+    publish_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    CONSTRAINT scheduled_drafts_content CHECK (publish_at IS NULL OR content != ''),
+    INDEX sorted_drafts (user_id, created_at DESC, id DESC)
+);
+
+CREATE INDEX IF NOT EXISTS idx_drafts_due
+ON drafts (publish_at)
+WHERE publish_at IS NOT NULL;
+
+-- draft_id is set on notifications about scheduled drafts that could not be published.
+ALTER TABLE notifications ADD COLUMN IF NOT EXISTS draft_id UUID REFERENCES drafts ON DELETE CASCADE;
+
+-- revisions keep the previous state of edited posts and comments.
+CREATE TABLE IF NOT EXISTS post_revisions (
+    id UUID NOT NULL PRIMARY KEY DEFAULT gen_random_uuid(),
+    post_id UUID NOT NULL REFERENCES posts ON DELETE CASCADE,
+    content VARCHAR NOT NULL,
+    spoiler_of VARCHAR,
+    nsfw BOOLEAN NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    INDEX sorted_post_revisions (post_id, created_at DESC, id DESC)
+);
+
+CREATE TABLE IF NOT EXISTS comment_revisions (
+    id UUID NOT NULL PRIMARY KEY DEFAULT gen_random_uuid(),
+    comment_id UUID NOT NULL REFERENCES comments ON DELETE CASCADE,
+    content VARCHAR NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    INDEX sorted_comment_revisions (comment_id, created_at DESC, id DESC)
+);
+
+CREATE TABLE IF NOT EXISTS bookmarks (
+    user_id UUID NOT NULL REFERENCES users ON DELETE CASCADE,
+    post_id UUID NOT NULL REFERENCES posts ON DELETE CASCADE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    PRIMARY KEY (user_id, post_id),
+    INDEX sorted_bookmarks (user_id, created_at DESC, post_id DESC)
+);
+
+CREATE TABLE IF NOT EXISTS pinned_posts (
+    user_id UUID NOT NULL REFERENCES users ON DELETE CASCADE,
+    post_id UUID NOT NULL REFERENCES posts ON DELETE CASCADE,
+    pinned_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    PRIMARY KEY (user_id, post_id)
+);
+
+ALTER TABLE posts ADD COLUMN IF NOT EXISTS visibility VARCHAR NOT NULL DEFAULT 'public';
+ALTER TABLE posts
+ADD CONSTRAINT IF NOT EXISTS posts_visibility_check
+CHECK (visibility IN ('public', 'followers', 'mentioned'));
+-- users mentioned when the post was created. They can see the post regardless of its visibility.
+ALTER TABLE posts ADD COLUMN IF NOT EXISTS mentioned_user_ids UUID[] NOT NULL DEFAULT '{}';
+ALTER TABLE drafts ADD COLUMN IF NOT EXISTS visibility VARCHAR NOT NULL DEFAULT 'public';
+ALTER TABLE drafts
+ADD CONSTRAINT IF NOT EXISTS drafts_visibility_check
+CHECK (visibility IN ('public', 'followers', 'mentioned'));
+
+CREATE TABLE IF NOT EXISTS blocks (
+    blocker_id UUID NOT NULL REFERENCES users ON DELETE CASCADE,
+    blocked_id UUID NOT NULL REFERENCES users ON DELETE CASCADE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    PRIMARY KEY (blocker_id, blocked_id),
+    INDEX sorted_blocks (blocker_id, created_at DESC, blocked_id DESC),
+    INDEX idx_blocks_blocked_id (blocked_id)
+);
+
+CREATE TABLE IF NOT EXISTS mutes (
+    muter_id UUID NOT NULL REFERENCES users ON DELETE CASCADE,
+    muted_id UUID NOT NULL REFERENCES users ON DELETE CASCADE,
+    expires_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    PRIMARY KEY (muter_id, muted_id),
+    INDEX idx_mutes_expires_at (expires_at) WHERE expires_at IS NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS muted_words (
+    id UUID NOT NULL DEFAULT gen_random_uuid() PRIMARY KEY,
+    user_id UUID NOT NULL REFERENCES users ON DELETE CASCADE,
+    keyword VARCHAR NOT NULL,
+    scope VARCHAR NOT NULL DEFAULT 'both',
+    action VARCHAR NOT NULL DEFAULT 'hide',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    UNIQUE INDEX unique_muted_words (user_id, keyword)
+);
+
+ALTER TABLE muted_words
+ADD CONSTRAINT IF NOT EXISTS muted_words_scope_check
+CHECK (scope IN ('timeline', 'notifications', 'both'));
+
+ALTER TABLE muted_words
+ADD CONSTRAINT IF NOT EXISTS muted_words_action_check
+CHECK (action IN ('hide', 'filter'));
+
+ALTER TABLE users ADD COLUMN IF NOT EXISTS private BOOLEAN NOT NULL DEFAULT false;
+
+CREATE TABLE IF NOT EXISTS follow_requests (
+    follower_id UUID NOT NULL REFERENCES users ON DELETE CASCADE,
+    followee_id UUID NOT NULL REFERENCES users ON DELETE CASCADE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    PRIMARY KEY (follower_id, followee_id),
+    INDEX idx_follow_requests_followee (followee_id)
+);
+
+-- users with delete_after set are deactivated
+-- and get permanently deleted by a background job after that time.
+ALTER TABLE users ADD COLUMN IF NOT EXISTS delete_after TIMESTAMPTZ;
+
+CREATE INDEX IF NOT EXISTS idx_users_delete_after ON users (delete_after) WHERE delete_after IS NOT NULL;
+
+-- user_sessions keeps track of the session tokens of each user
+-- so they can be revoked.
+CREATE TABLE IF NOT EXISTS user_sessions (
+    token TEXT NOT NULL PRIMARY KEY,
+    user_id UUID NOT NULL REFERENCES users ON DELETE CASCADE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    INDEX idx_user_sessions_user_id (user_id)
+);
+
+CREATE TABLE IF NOT EXISTS data_exports (
+    id UUID NOT NULL PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id UUID NOT NULL REFERENCES users ON DELETE CASCADE,
+    status VARCHAR NOT NULL DEFAULT 'pending',
+    object_key VARCHAR,
+    expires_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    INDEX idx_data_exports_status (status, created_at)
+);
+
+ALTER TABLE data_exports
+ADD CONSTRAINT IF NOT EXISTS data_exports_status_check
+CHECK (status IN ('pending', 'processing', 'ready', 'failed'));
+
+-- a single export in progress per user.
+CREATE UNIQUE INDEX IF NOT EXISTS unique_data_exports_in_progress
+ON data_exports (user_id)
+WHERE status IN ('pending', 'processing');
+
+CREATE TABLE IF NOT EXISTS imported_posts (
+    user_id UUID NOT NULL REFERENCES users ON DELETE CASCADE,
+    source VARCHAR NOT NULL,
+    source_id VARCHAR NOT NULL,
+    post_id UUID NOT NULL REFERENCES posts ON DELETE CASCADE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    PRIMARY KEY (user_id, source, source_id)
+);
+
+-- search_vector columns back full-text search over post and comment content.
+ALTER TABLE posts ADD COLUMN IF NOT EXISTS search_vector TSVECTOR AS (to_tsvector('simple', content)) STORED;
+CREATE INVERTED INDEX IF NOT EXISTS idx_posts_search_vector ON posts (search_vector);
+
+ALTER TABLE comments ADD COLUMN IF NOT EXISTS search_vector TSVECTOR AS (to_tsvector('simple', content)) STORED;
+CREATE INVERTED INDEX IF NOT EXISTS idx_comments_search_vector ON comments (search_vector);
+
+-- trigram indexes back fuzzy search over usernames and tags.
+CREATE INVERTED INDEX IF NOT EXISTS idx_users_username_trgm ON users (username gin_trgm_ops);
+CREATE INVERTED INDEX IF NOT EXISTS idx_post_tags_tag_trgm ON post_tags (tag gin_trgm_ops);
+
+-- trending_tags is an aggregate refreshed by a background job
+-- so reading trending tags stays cheap.
+CREATE TABLE IF NOT EXISTS trending_tags (
+    time_window VARCHAR NOT NULL,
+    tag VARCHAR NOT NULL,
+    score FLOAT8 NOT NULL,
+    authors_count INT NOT NULL,
+    posts_count INT NOT NULL,
+    refreshed_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    PRIMARY KEY (time_window, tag),
+    INDEX sorted_trending_tags (time_window, score DESC)
+);
+
+CREATE INDEX IF NOT EXISTS idx_post_tags_post_id ON post_tags (post_id);
+
+CREATE TABLE IF NOT EXISTS tag_follows (
+    user_id UUID NOT NULL REFERENCES users ON DELETE CASCADE,
+    tag VARCHAR NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    PRIMARY KEY (user_id, tag),
+    INDEX idx_tag_follows_tag (tag)
+);
+
+-- reason tells why a post is in a timeline. It's NULL on items created before it was tracked.
+ALTER TABLE timeline ADD COLUMN IF NOT EXISTS reason VARCHAR;
+ALTER TABLE timeline ADD COLUMN IF NOT EXISTS reason_tag VARCHAR;
+
+-- tags keeps one row per normalized tag, with the form
+-- in which it was first written and when.
+CREATE TABLE IF NOT EXISTS tags (
+    tag VARCHAR NOT NULL PRIMARY KEY,
+    display_tag VARCHAR NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS idx_post_tags_tag ON post_tags (tag);
+
+-- entities are the mentions, hashtags, URLs and spoilers found in the content.
+ALTER TABLE posts ADD COLUMN IF NOT EXISTS entities JSONB;
+ALTER TABLE comments ADD COLUMN IF NOT EXISTS entities JSONB;
+
+-- link_previews caches the metadata unfurled from URLs.
+-- URLs with nothing to show are kept too, so they aren't fetched over and over.
+CREATE TABLE IF NOT EXISTS link_previews (
+    url VARCHAR NOT NULL PRIMARY KEY,
+    title VARCHAR NOT NULL DEFAULT '',
+    description VARCHAR NOT NULL DEFAULT '',
+    image_url VARCHAR,
+    site_name VARCHAR NOT NULL DEFAULT '',
+    fetched_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+-- link_url is the URL of the post that gets a link preview.
+ALTER TABLE posts ADD COLUMN IF NOT EXISTS link_url VARCHAR;
+
+CREATE TABLE IF NOT EXISTS custom_emojis (
+    shortcode VARCHAR NOT NULL PRIMARY KEY,
+    image VARCHAR NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
 -- INSERT INTO users (id, email, username) VALUES
 --     ('504c9492-bde3-4b86-862a-e2fbb6ea0363', 'shinji@example.org', 'shinji'),
 --     ('cc51e41c-f18c-43e2-a172-32a06faad175', 'rei@example.org', 'rei'),
@@ -0,0 +1,316 @@
+package cockroach
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgxutil"
+	"github.com/nakamauwu/nakama/textutil"
+	"github.com/nakamauwu/nakama/types"
+)
+
+// sqlSearchQuery parses `@query` into a full-text search query.
+const sqlSearchQuery = `plainto_tsquery('simple', @query)`
+
+// searchRow pairs an item with its rank, which is used as the cursor value.
+type searchRow[T any] struct {
+	Item T
+	Rank float64
+}
+
+// Search returns the items of the kind asked for that match the query,
+// the most relevant first.
+func (c *Cockroach) Search(ctx context.Context, in types.Search) (types.Page[types.SearchResult], error) {
+	switch in.Kind {
+	case types.SearchKindComment:
+		return c.searchComments(ctx, in)
+	case types.SearchKindUser:
+		return c.searchUsers(ctx, in)
+	case types.SearchKindTag:
+		return c.searchTags(ctx, in)
+	default:
+		return c.searchPosts(ctx, in)
+	}
+}
+
+func (c *Cockroach) searchPosts(ctx context.Context, in types.Search) (types.Page[types.SearchResult], error) {
+	const rank = `ts_rank(posts.search_vector, ` + sqlSearchQuery + `)::FLOAT8`
+
+	args := pgx.StrictNamedArgs{"query": in.Query}
+	selects := []string{sqlPostCols, sqlUserJSONB, sqlSelectRepostOf, sqlSelectLinkPreview, sqlSelectPoll(args, in.ViewerID()), rank + ` AS rank`}
+	joins := []string{"INNER JOIN users ON posts.user_id = users.id", sqlJoinRepostOf(args, in.ViewerID())}
+	filters := []string{
+		`posts.search_vector @@ ` + sqlSearchQuery,
+		sqlPostVisible(args, in.ViewerID()),
+	}
+
+	if !in.IncludeNSFW {
+		filters = append(filters, "posts.nsfw = false")
+	}
+
+	if in.ViewerID() != nil {
+		filters = append(filters,
+			sqlNoBlockBetween("@viewer_id", "posts.user_id"),
+			sqlNotMuted("@viewer_id", "posts.user_id"))
+	}
+
+	selects, joins = appendPostViewerFields(args, selects, joins, in.ViewerID())
+
+	rows, pageArgs, err := searchSelect(ctx, c, in.PageArgs, args, selects, joins, filters, "posts", rank, "posts.id",
+		func(row pgx.CollectableRow) (searchRow[types.Post], error) {
+			type postRow struct {
+				types.Post
+				Rank float64 `db:"rank"`
+			}
+			r, err := pgx.RowToStructByNameLax[postRow](row)
+			return searchRow[types.Post]{Item: r.Post, Rank: r.Rank}, err
+		})
+	if err != nil {
+		return types.Page[types.SearchResult]{}, fmt.Errorf("sql search posts: %w", err)
+	}
+
+	return searchPage(rows, pageArgs, func(p types.Post) (string, types.SearchResult) {
+		return p.ID, types.SearchResult{Kind: types.SearchKindPost, Post: &p}
+	})
+}
+
+func (c *Cockroach) searchComments(ctx context.Context, in types.Search) (types.Page[types.SearchResult], error) {
+	const rank = `ts_rank(comments.search_vector, ` + sqlSearchQuery + `)::FLOAT8`
+
+	args := pgx.StrictNamedArgs{"query": in.Query}
+	selects := []string{sqlCommentCols, sqlUserJSONB, rank + ` AS rank`}
+	joins := []string{
+		"INNER JOIN users ON comments.user_id = users.id",
+		"INNER JOIN posts ON comments.post_id = posts.id",
+	}
+	filters := []string{
+		`comments.search_vector @@ ` + sqlSearchQuery,
+		sqlPostVisible(args, in.ViewerID()),
+		sqlUserActive,
+	}
+
+	if !in.IncludeNSFW {
+		filters = append(filters, "posts.nsfw = false")
+	}
+
+	if in.ViewerID() != nil {
+		// neither from nor on posts by blocked or muted users.
+		filters = append(filters,
+			sqlNoBlockBetween("@viewer_id", "comments.user_id"),
+			sqlNotMuted("@viewer_id", "comments.user_id"),
+			sqlNoBlockBetween("@viewer_id", "posts.user_id"),
+			sqlNotMuted("@viewer_id", "posts.user_id"))
+		selects = append(selects, `(comments.user_id = @viewer_id) AS mine`, sqlSelectCommentsReactions)
+		joins = append(joins, sqlJoinCommentReactions)
+	} else {
+		selects = append(selects, `false AS mine`, `comments.reactions`)
+	}
+
+	rows, pageArgs, err := searchSelect(ctx, c, in.PageArgs, args, selects, joins, filters, "comments", rank, "comments.id",
+		func(row pgx.CollectableRow) (searchRow[types.Comment], error) {
+			type commentRow struct {
+				types.Comment
+				Rank float64 `db:"rank"`
+			}
+			r, err := pgx.RowToStructByNameLax[commentRow](row)
+			return searchRow[types.Comment]{Item: r.Comment, Rank: r.Rank}, err
+		})
+	if err != nil {
+		return types.Page[types.SearchResult]{}, fmt.Errorf("sql search comments: %w", err)
+	}
+
+	return searchPage(rows, pageArgs, func(c types.Comment) (string, types.SearchResult) {
+		return c.ID, types.SearchResult{Kind: types.SearchKindComment, Comment: &c}
+	})
+}
+
+func (c *Cockroach) searchUsers(ctx context.Context, in types.Search) (types.Page[types.SearchResult], error) {
+	const rank = `similarity(users.username, @query)::FLOAT8`
+
+	args := pgx.StrictNamedArgs{"query": strings.TrimPrefix(in.Query, "@")}
+	selects := []string{sqlUserProfileCols, rank + ` AS rank`}
+	joins := []string{}
+	filters := []string{
+		`(users.username % @query OR users.username ILIKE '%' || @query || '%')`,
+		sqlUserActive,
+	}
+
+	if in.ViewerID() != nil {
+		args["viewer_id"] = *in.ViewerID()
+		filters = append(filters, sqlNoBlockBetween("@viewer_id", "users.id"))
+		selects, joins = appendViewerRelationshipFields(selects, joins)
+	}
+
+	rows, pageArgs, err := searchSelect(ctx, c, in.PageArgs, args, selects, joins, filters, "users", rank, "users.id",
+		func(row pgx.CollectableRow) (searchRow[types.UserProfile], error) {
+			type userRow struct {
+				types.UserProfile
+				Rank float64 `db:"rank"`
+			}
+			r, err := pgx.RowToStructByNameLax[userRow](row)
+			return searchRow[types.UserProfile]{Item: r.UserProfile, Rank: r.Rank}, err
+		})
+	if err != nil {
+		return types.Page[types.SearchResult]{}, fmt.Errorf("sql search users: %w", err)
+	}
+
+	return searchPage(rows, pageArgs, func(u types.UserProfile) (string, types.SearchResult) {
+		return u.ID, types.SearchResult{Kind: types.SearchKindUser, User: &u}
+	})
+}
+
+// searchTags matches tags by similarity and counts the posts,
+// visible to the viewer, using them.
+func (c *Cockroach) searchTags(ctx context.Context, in types.Search) (types.Page[types.SearchResult], error) {
+	const rank = `similarity(post_tags.tag, @query)::FLOAT8`
+
+	args := pgx.StrictNamedArgs{"query": textutil.NormalizeTag(strings.TrimPrefix(in.Query, "#"))}
+	filters := []string{
+		`(post_tags.tag % @query OR post_tags.tag ILIKE '%' || @query || '%')`,
+		sqlPostVisible(args, in.ViewerID()),
+	}
+
+	if in.ViewerID() != nil {
+		filters = append(filters, sqlNoBlockBetween("@viewer_id", "posts.user_id"))
+	}
+
+	pageArgs, err := ParsePageArgs[float64](in.PageArgs)
+	if err != nil {
+		return types.Page[types.SearchResult]{}, err
+	}
+
+	var having string
+	if pageArgs.After != nil {
+		having = fmt.Sprintf("HAVING (%s, post_tags.tag) < (@after_rank, @after_tag)", rank)
+		args["after_rank"] = pageArgs.After.Value
+		args["after_tag"] = pageArgs.After.ID
+	} else if pageArgs.Before != nil {
+		having = fmt.Sprintf("HAVING (%s, post_tags.tag) > (@before_rank, @before_tag)", rank)
+		args["before_rank"] = pageArgs.Before.Value
+		args["before_tag"] = pageArgs.Before.ID
+	}
+
+	var order, limit string
+	if pageArgs.IsBackwards() {
+		order = "ORDER BY rank ASC, post_tags.tag ASC"
+		limit = fmt.Sprintf("LIMIT %d", or(pageArgs.Last, defaultPageSize)+1) // +1 to check if there's a next page
+	} else {
+		order = "ORDER BY rank DESC, post_tags.tag DESC"
+		limit = fmt.Sprintf("LIMIT %d", or(pageArgs.First, defaultPageSize)+1) // +1 to check if there's a next page
+	}
+
+	query := fmt.Sprintf(`
+		SELECT
+			  post_tags.tag AS name
+			, COALESCE(tags.display_tag, post_tags.tag) AS display
+			, count(DISTINCT posts.id) AS posts_count
+			, %s AS rank
+		FROM post_tags
+		INNER JOIN posts ON posts.id = post_tags.post_id
+		LEFT JOIN tags ON tags.tag = post_tags.tag
+		WHERE %s
+		GROUP BY post_tags.tag, tags.display_tag
+		%s
+		%s
+		%s`,
+		rank,
+		strings.Join(filters, " AND "),
+		having,
+		order,
+		limit,
+	)
+
+	rows, err := pgxutil.Select(ctx, c.db, query, []any{args}, func(row pgx.CollectableRow) (searchRow[types.Tag], error) {
+		type tagRow struct {
+			types.Tag
+			Rank float64 `db:"rank"`
+		}
+		r, err := pgx.RowToStructByNameLax[tagRow](row)
+		return searchRow[types.Tag]{Item: r.Tag, Rank: r.Rank}, err
+	})
+	if err != nil {
+		return types.Page[types.SearchResult]{}, fmt.Errorf("sql search tags: %w", err)
+	}
+
+	return searchPage(rows, pageArgs, func(t types.Tag) (string, types.SearchResult) {
+		return t.Name, types.SearchResult{Kind: types.SearchKindTag, Tag: &t}
+	})
+}
+
+// searchSelect runs a search query over the given table,
+// sorted by rank and paginated with a rank cursor.
+func searchSelect[T any](
+	ctx context.Context,
+	c *Cockroach,
+	page types.PageArgs,
+	args pgx.StrictNamedArgs,
+	selects, joins, filters []string,
+	table, rank, id string,
+	scan pgx.RowToFunc[searchRow[T]],
+) ([]searchRow[T], PageArgs[float64], error) {
+	pageArgs, err := ParsePageArgs[float64](page)
+	if err != nil {
+		return nil, pageArgs, err
+	}
+
+	if pageArgs.After != nil {
+		filters = append(filters, fmt.Sprintf("(%s, %s) < (@after_rank, @after_id)", rank, id))
+		args["after_rank"] = pageArgs.After.Value
+		args["after_id"] = pageArgs.After.ID
+	} else if pageArgs.Before != nil {
+		filters = append(filters, fmt.Sprintf("(%s, %s) > (@before_rank, @before_id)", rank, id))
+		args["before_rank"] = pageArgs.Before.Value
+		args["before_id"] = pageArgs.Before.ID
+	}
+
+	var order, limit string
+	if pageArgs.IsBackwards() {
+		order = fmt.Sprintf("ORDER BY rank ASC, %s ASC", id)
+		limit = fmt.Sprintf("LIMIT %d", or(pageArgs.Last, defaultPageSize)+1) // +1 to check if there's a next page
+	} else {
+		order = fmt.Sprintf("ORDER BY rank DESC, %s DESC", id)
+		limit = fmt.Sprintf("LIMIT %d", or(pageArgs.First, defaultPageSize)+1) // +1 to check if there's a next page
+	}
+
+	query := fmt.Sprintf(`
+		SELECT %s
+		FROM %s
+		%s
+		WHERE %s
+		%s
+		%s`,
+		strings.Join(selects, ",\n\t\t"),
+		table,
+		strings.Join(joins, "\n\t\t"),
+		strings.Join(filters, " AND "),
+		order,
+		limit,
+	)
+
+	rows, err := pgxutil.Select(ctx, c.db, query, []any{args}, scan)
+	return rows, pageArgs, err
+}
+
+// searchPage paginates the rows and turns them into search results.
+func searchPage[T any](rows []searchRow[T], pageArgs PageArgs[float64], result func(T) (string, types.SearchResult)) (types.Page[types.SearchResult], error) {
+	var out types.Page[types.SearchResult]
+
+	page := types.Page[searchRow[T]]{Items: rows}
+	err := applyPageInfo(&page, pageArgs, func(row searchRow[T]) Cursor[float64] {
+		id, _ := result(row.Item)
+		return Cursor[float64]{ID: id, Value: row.Rank}
+	})
+	if err != nil {
+		return out, err
+	}
+
+	out.PageInfo = page.PageInfo
+	for _, row := range page.Items {
+		_, r := result(row.Item)
+		out.Items = append(out.Items, r)
+	}
+
+	return out, nil
+}
@@ -0,0 +1,213 @@
+package cockroach
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgxutil"
+	"github.com/nakamauwu/nakama/textutil"
+	"github.com/nakamauwu/nakama/types"
+	"github.com/nicolasparada/go-db"
+	"github.com/nicolasparada/go-errs"
+)
+
+const relatedTagsLimit = 10
+
+// TagPage returns the tag details along with the tags most used together with it.
+func (c *Cockroach) TagPage(ctx context.Context, in types.RetrieveTagPage) (types.TagPage, error) {
+	var out types.TagPage
+
+	args := pgx.StrictNamedArgs{"tag": in.Tag}
+	filters := []string{
+		"post_tags.tag = tags.tag",
+		sqlPostVisible(args, in.ViewerID()),
+	}
+	followed := "false"
+	if in.ViewerID() != nil {
+		filters = append(filters, sqlNoBlockBetween("@viewer_id", "posts.user_id"))
+		followed = "EXISTS (SELECT 1 FROM tag_follows WHERE tag_follows.user_id = @viewer_id AND tag_follows.tag = tags.tag)"
+	}
+
+	query := fmt.Sprintf(`
+		SELECT
+			  tags.tag AS name
+			, tags.display_tag AS display
+			, tags.created_at AS first_seen_at
+			, (
+				SELECT count(DISTINCT posts.id)
+				FROM post_tags
+				INNER JOIN posts ON posts.id = post_tags.post_id
+				WHERE %s
+			) AS posts_count
+			, %s AS followed_by_viewer
+		FROM tags
+		WHERE tags.tag = @tag`,
+		strings.Join(filters, " AND "),
+		followed,
+	)
+
+	out, err := pgxutil.SelectRow(ctx, c.db, query, []any{args}, pgx.RowToStructByNameLax[types.TagPage])
+	if db.IsNotFoundError(err) {
+		return out, errs.NotFoundError("tag not found")
+	}
+
+	if err != nil {
+		return out, fmt.Errorf("sql select tag page: %w", err)
+	}
+
+	out.RelatedTags, err = c.relatedTags(ctx, in)
+	if err != nil {
+		return out, err
+	}
+
+	return out, nil
+}
+
+// relatedTags are the ones that show up the most on the same posts as the given tag.
+func (c *Cockroach) relatedTags(ctx context.Context, in types.RetrieveTagPage) ([]types.Tag, error) {
+	args := pgx.StrictNamedArgs{
+		"tag":   in.Tag,
+		"limit": relatedTagsLimit,
+	}
+	filters := []string{
+		"this.tag = @tag",
+		sqlPostVisible(args, in.ViewerID()),
+	}
+	if in.ViewerID() != nil {
+		filters = append(filters, sqlNoBlockBetween("@viewer_id", "posts.user_id"))
+	}
+
+	query := fmt.Sprintf(`
+		SELECT
+			  other.tag AS name
+			, COALESCE(tags.display_tag, other.tag) AS display
+			, count(DISTINCT posts.id) AS posts_count
+		FROM post_tags AS this
+		INNER JOIN post_tags AS other ON other.post_id = this.post_id AND other.tag != this.tag
+		INNER JOIN posts ON posts.id = this.post_id
+		LEFT JOIN tags ON tags.tag = other.tag
+		WHERE %s
+		GROUP BY other.tag, tags.display_tag
+		ORDER BY posts_count DESC, name
+		LIMIT @limit`,
+		strings.Join(filters, " AND "),
+	)
+
+	tags, err := pgxutil.Select(ctx, c.db, query, []any{args}, pgx.RowToStructByNameLax[types.Tag])
+	if err != nil {
+		return nil, fmt.Errorf("sql select related tags: %w", err)
+	}
+
+	return tags, nil
+}
+
+// migrateTags normalizes tags stored before they were normalized
+// and records them in the tags table.
+// Only tags missing from that table are visited, so it's cheap once done.
+func (c *Cockroach) migrateTags(ctx context.Context) error {
+	const selectPostTags = `
+		SELECT post_tags.tag, min(posts.created_at) AS first_seen_at
+		FROM post_tags
+		INNER JOIN posts ON posts.id = post_tags.post_id
+		WHERE NOT EXISTS (SELECT 1 FROM tags WHERE tags.tag = post_tags.tag)
+		GROUP BY post_tags.tag
+		ORDER BY first_seen_at
+	`
+
+	type legacyTag struct {
+		Tag         string
+		FirstSeenAt time.Time `db:"first_seen_at"`
+	}
+
+	legacy, err := pgxutil.Select(ctx, c.db, selectPostTags, nil, pgx.RowToStructByName[legacyTag])
+	if err != nil {
+		return fmt.Errorf("sql select legacy post tags: %w", err)
+	}
+
+	for _, t := range legacy {
+		err := c.db.RunTx(ctx, func(ctx context.Context) error {
+			name := textutil.NormalizeTag(t.Tag)
+			if name != t.Tag {
+				_, err := c.db.Exec(ctx, `UPDATE post_tags SET tag = @name WHERE tag = @tag`, pgx.StrictNamedArgs{
+					"name": name,
+					"tag":  t.Tag,
+				})
+				if err != nil {
+					return fmt.Errorf("sql update post tags: %w", err)
+				}
+			}
+
+			// the earliest use wins the display form.
+			const query = `
+				INSERT INTO tags (tag, display_tag, created_at)
+				VALUES (@tag, @display_tag, @created_at)
+				ON CONFLICT (tag) DO UPDATE
+				SET display_tag = excluded.display_tag, created_at = excluded.created_at
+				WHERE excluded.created_at < tags.created_at
+			`
+			_, err := c.db.Exec(ctx, query, pgx.StrictNamedArgs{
+				"tag":         name,
+				"display_tag": t.Tag,
+				"created_at":  t.FirstSeenAt,
+			})
+			if err != nil {
+				return fmt.Errorf("sql insert tag: %w", err)
+			}
+
+			return nil
+		})
+		if err != nil {
+			return fmt.Errorf("migrate tag %q: %w", t.Tag, err)
+		}
+	}
+
+	const selectFollowedTags = `
+		SELECT DISTINCT tag
+		FROM tag_follows
+		WHERE NOT EXISTS (SELECT 1 FROM tags WHERE tags.tag = tag_follows.tag)
+	`
+
+	followed, err := pgxutil.Select(ctx, c.db, selectFollowedTags, nil, pgx.RowTo[string])
+	if err != nil {
+		return fmt.Errorf("sql select legacy followed tags: %w", err)
+	}
+
+	for _, tag := range followed {
+		name := textutil.NormalizeTag(tag)
+		if name == tag {
+			continue
+		}
+
+		err := c.db.RunTx(ctx, func(ctx context.Context) error {
+			args := pgx.StrictNamedArgs{
+				"name": name,
+				"tag":  tag,
+			}
+
+			const query = `
+				INSERT INTO tag_follows (user_id, tag, created_at)
+				SELECT user_id, @name, created_at FROM tag_follows WHERE tag = @tag
+				ON CONFLICT (user_id, tag) DO NOTHING
+			`
+			_, err := c.db.Exec(ctx, query, args)
+			if err != nil {
+				return fmt.Errorf("sql insert normalized tag follows: %w", err)
+			}
+
+			_, err = c.db.Exec(ctx, `DELETE FROM tag_follows WHERE tag = @tag`, pgx.StrictNamedArgs{"tag": tag})
+			if err != nil {
+				return fmt.Errorf("sql delete legacy tag follows: %w", err)
+			}
+
+			return nil
+		})
+		if err != nil {
+			return fmt.Errorf("migrate followed tag %q: %w", tag, err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,100 @@
+package cockroach
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgxutil"
+	"github.com/nakamauwu/nakama/types"
+)
+
+// FollowTag so new posts with the tag show up in the user's timeline.
+// Following an already followed tag is a no-op.
+func (c *Cockroach) FollowTag(ctx context.Context, userID, tag string) error {
+	const query = `
+		INSERT INTO tag_follows (user_id, tag)
+		VALUES (@user_id, @tag)
+		ON CONFLICT (user_id, tag) DO NOTHING
+	`
+	args := pgx.StrictNamedArgs{
+		"user_id": userID,
+		"tag":     tag,
+	}
+	_, err := c.db.Exec(ctx, query, args)
+	if err != nil {
+		return fmt.Errorf("sql insert tag follow: %w", err)
+	}
+
+	return nil
+}
+
+func (c *Cockroach) UnfollowTag(ctx context.Context, userID, tag string) error {
+	const query = `
+		DELETE FROM tag_follows
+		WHERE user_id = @user_id AND tag = @tag
+	`
+	args := pgx.StrictNamedArgs{
+		"user_id": userID,
+		"tag":     tag,
+	}
+	_, err := c.db.Exec(ctx, query, args)
+	if err != nil {
+		return fmt.Errorf("sql delete tag follow: %w", err)
+	}
+
+	return nil
+}
+
+// TagFollows lists the tags followed by the given user in alphabetical order.
+func (c *Cockroach) TagFollows(ctx context.Context, in types.ListTagFollows) (types.Page[types.TagFollow], error) {
+	var out types.Page[types.TagFollow]
+
+	args := pgx.StrictNamedArgs{"user_id": in.UserID()}
+	filters := []string{"tag_follows.user_id = @user_id"}
+
+	pageArgs, err := ParsePageArgs[any](in.PageArgs)
+	if err != nil {
+		return out, err
+	}
+
+	if pageArgs.After != nil {
+		filters = append(filters, "tag_follows.tag > @after_tag")
+		args["after_tag"] = pageArgs.After.ID // Cursor ID is the tag in this case
+	} else if pageArgs.Before != nil {
+		filters = append(filters, "tag_follows.tag < @before_tag")
+		args["before_tag"] = pageArgs.Before.ID // Cursor ID is the tag in this case
+	}
+
+	var order, limit string
+	if pageArgs.IsBackwards() {
+		order = "ORDER BY tag_follows.tag DESC"
+		limit = fmt.Sprintf("LIMIT %d", or(pageArgs.Last, defaultPageSize)+1) // +1 to check if there's a next page
+	} else {
+		order = "ORDER BY tag_follows.tag ASC"
+		limit = fmt.Sprintf("LIMIT %d", or(pageArgs.First, defaultPageSize)+1) // +1 to check if there's a next page
+	}
+
+	query := fmt.Sprintf(`
+		SELECT tag_follows.tag, tag_follows.created_at
+		FROM tag_follows
+		WHERE %s
+		%s
+		%s`,
+		strings.Join(filters, " AND "),
+		order,
+		limit,
+	)
+
+	tags, err := pgxutil.Select(ctx, c.db, query, []any{args}, pgx.RowToStructByNameLax[types.TagFollow])
+	if err != nil {
+		return out, fmt.Errorf("sql select tag follows: %w", err)
+	}
+
+	out.Items = tags
+
+	return out, applyPageInfo(&out, pageArgs, func(tf types.TagFollow) Cursor[any] {
+		return Cursor[any]{ID: tf.Tag}
+	})
+}
@@ -13,10 +13,19 @@ import (
 	"github.com/nicolasparada/go-errs"
 )
 
+// sqlSelectTimelineItemReason falls back to guessing the reason
+// of items created before it was tracked.
+const sqlSelectTimelineItemReason = `
+	COALESCE(timeline.reason, CASE
+		WHEN posts.user_id = timeline.user_id THEN 'own'
+		WHEN posts.visibility = 'mentioned' THEN 'mention'
+		ELSE 'follow'
+	END) AS reason`
+
 func (c *Cockroach) createTimelineItem(ctx context.Context, userID, postID string) (string, error) {
 	const query = `
-		INSERT INTO timeline (user_id, post_id)
-		VALUES (@user_id, @post_id)
+		INSERT INTO timeline (user_id, post_id, reason)
+		VALUES (@user_id, @post_id, 'own')
 		RETURNING id
 	`
 	args := pgx.StrictNamedArgs{
@@ -40,17 +49,30 @@ func (c *Cockroach) Timeline(ctx context.Context, in types.ListTimeline) (types.
 	}
 	selects := []string{
 		`timeline.id AS timeline_item_id`,
+		sqlSelectTimelineItemReason,
+		`timeline.reason_tag`,
 		sqlPostCols,
 		sqlUserJSONB,
+		sqlSelectRepostOf,
+		sqlSelectLinkPreview,
+		sqlSelectPoll(args, new(in.UserID())),
 		`(posts.user_id = @viewer_id) AS mine`,
 		`(post_subscriptions.user_id IS NOT NULL) AS subscribed`,
+		`(bookmarks.user_id IS NOT NULL) AS bookmarked`,
 		sqlSelectPostsReactions}
 	joins := []string{
 		"INNER JOIN posts ON timeline.post_id = posts.id",
 		"INNER JOIN users ON posts.user_id = users.id",
+		sqlJoinRepostOf(args, new(in.UserID())),
 		`LEFT JOIN post_subscriptions ON post_subscriptions.post_id = posts.id AND post_subscriptions.user_id = @viewer_id`,
+		`LEFT JOIN bookmarks ON bookmarks.post_id = posts.id AND bookmarks.user_id = @viewer_id`,
 		sqlJoinPostReactions(args, in.UserID())}
-	filters := []string{"timeline.user_id = @viewer_id"}
+	filters := []string{
+		"timeline.user_id = @viewer_id",
+		sqlPostVisible(args, new(in.UserID())),
+		sqlNoBlockBetween("@viewer_id", "posts.user_id"),
+		sqlNotMuted("@viewer_id", "posts.user_id"),
+	}
 
 	pageArgs, err := ParsePageArgs[time.Time](in.PageArgs)
 	if err != nil {
@@ -102,17 +124,56 @@ func (c *Cockroach) Timeline(ctx context.Context, in types.ListTimeline) (types.
 	})
 }
 
-func (c *Cockroach) FanoutTimeline(ctx context.Context, postID, followeeID string) ([]types.TimelineItem, error) {
-	const query = `
-		INSERT INTO timeline (user_id, post_id)
-		SELECT follower_id, @post_id
-		FROM follows
-		WHERE followee_id = @followee_id
-		RETURNING id AS timeline_item_id, post_id, user_id
-	`
+// FanoutTimeline inserts the post into the timeline of its audience.
+// Followers get public and followers-only posts,
+// while posts visible only to mentioned users go to those users alone.
+// Followers of any of the given tags get the post too, as long as they can see it.
+// Each user gets the post once, marked with the most relevant reason.
+func (c *Cockroach) FanoutTimeline(ctx context.Context, postID, followeeID string, tags []string) ([]types.TimelineItem, error) {
+	query := fmt.Sprintf(`
+		INSERT INTO timeline (user_id, post_id, reason, reason_tag)
+		SELECT DISTINCT ON (audience.user_id) audience.user_id, @post_id, audience.reason, audience.reason_tag
+		FROM (
+			SELECT follows.follower_id AS user_id, 'follow' AS reason, NULL::VARCHAR AS reason_tag, 1 AS priority
+			FROM follows
+			INNER JOIN posts ON posts.id = @post_id
+			WHERE follows.followee_id = @followee_id
+			AND posts.visibility != 'mentioned'
+			UNION ALL
+			SELECT unnest(posts.mentioned_user_ids) AS user_id, 'mention' AS reason, NULL::VARCHAR AS reason_tag, 2 AS priority
+			FROM posts
+			WHERE posts.id = @post_id
+			AND posts.visibility = 'mentioned'
+			UNION ALL
+			SELECT tag_follows.user_id, 'tag' AS reason, tag_follows.tag AS reason_tag, 3 AS priority
+			FROM tag_follows
+			INNER JOIN posts ON posts.id = @post_id
+			WHERE tag_follows.tag = ANY(@tags)
+			AND %s
+			AND %s
+			AND %s
+		) AS audience
+		WHERE audience.user_id != @followee_id
+		-- skip followers that already have the original post of a plain repost in their timeline.
+		AND NOT EXISTS (
+			SELECT 1
+			FROM posts
+			INNER JOIN timeline ON timeline.post_id = posts.repost_of_id
+			WHERE posts.id = @post_id
+			AND posts.content = ''
+			AND timeline.user_id = audience.user_id
+		)
+		ORDER BY audience.user_id, audience.priority, audience.reason_tag
+		RETURNING id AS timeline_item_id, post_id, user_id, reason, reason_tag
+	`,
+		sqlPostVisibleTo("tag_follows.user_id"),
+		sqlNoBlockBetween("tag_follows.user_id", "posts.user_id"),
+		sqlNotMuted("tag_follows.user_id", "posts.user_id"),
+	)
 	args := pgx.StrictNamedArgs{
 		"post_id":     postID,
 		"followee_id": followeeID,
+		"tags":        tags,
 	}
 
 	timeline, err := pgxutil.Select(ctx, c.db, query, []any{args}, pgx.RowToStructByNameLax[types.TimelineItem])
@@ -0,0 +1,110 @@
+package cockroach
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgxutil"
+	"github.com/nakamauwu/nakama/types"
+)
+
+const (
+	// trendingTagsMinAuthors keeps tags used by a single account out of trending.
+	trendingTagsMinAuthors = 2
+	trendingTagsLimit      = 20
+	// trendingTagsKept per window in the aggregate.
+	trendingTagsKept = 100
+)
+
+// RefreshTrendingTags recomputes the trending tags of the given window.
+// Usage is measured in distinct authors of public posts, and compared
+// against the previous window of the same length, so tags that are growing
+// score higher than the ones that are just always popular.
+func (c *Cockroach) RefreshTrendingTags(ctx context.Context, window types.TrendingWindow) error {
+	return c.db.RunTx(ctx, func(ctx context.Context) error {
+		args := pgx.StrictNamedArgs{"time_window": window}
+
+		_, err := c.db.Exec(ctx, `DELETE FROM trending_tags WHERE time_window = @time_window`, args)
+		if err != nil {
+			return fmt.Errorf("sql delete trending tags: %w", err)
+		}
+
+		query := fmt.Sprintf(`
+			WITH current_usage AS (
+				SELECT
+					  post_tags.tag
+					, count(DISTINCT posts.user_id) AS authors_count
+					, count(DISTINCT posts.id) AS posts_count
+				FROM posts
+				INNER JOIN post_tags ON post_tags.post_id = posts.id AND post_tags.comment_id IS NULL
+				WHERE posts.created_at >= now() - @duration::INTERVAL
+				AND %[1]s
+				GROUP BY post_tags.tag
+				HAVING count(DISTINCT posts.user_id) >= @min_authors
+			), previous_usage AS (
+				SELECT
+					  post_tags.tag
+					, count(DISTINCT posts.user_id) AS authors_count
+				FROM posts
+				INNER JOIN post_tags ON post_tags.post_id = posts.id AND post_tags.comment_id IS NULL
+				WHERE posts.created_at >= now() - 2 * @duration::INTERVAL
+				AND posts.created_at < now() - @duration::INTERVAL
+				AND post_tags.tag IN (SELECT tag FROM current_usage)
+				AND %[1]s
+				GROUP BY post_tags.tag
+			)
+			INSERT INTO trending_tags (time_window, tag, score, authors_count, posts_count)
+			SELECT
+				  @time_window
+				, current_usage.tag
+				, power(current_usage.authors_count, 2)::FLOAT8 / (COALESCE(previous_usage.authors_count, 0) + 1) AS score
+				, current_usage.authors_count
+				, current_usage.posts_count
+			FROM current_usage
+			LEFT JOIN previous_usage ON previous_usage.tag = current_usage.tag
+			ORDER BY score DESC
+			LIMIT @limit
+		`, sqlPostPublic)
+		args = pgx.StrictNamedArgs{
+			"time_window": window,
+			"duration":    window.Duration(),
+			"min_authors": trendingTagsMinAuthors,
+			"limit":       trendingTagsKept,
+		}
+
+		_, err = c.db.Exec(ctx, query, args)
+		if err != nil {
+			return fmt.Errorf("sql insert trending tags: %w", err)
+		}
+
+		return nil
+	})
+}
+
+func (c *Cockroach) TrendingTags(ctx context.Context, in types.ListTrendingTags) ([]types.TrendingTag, error) {
+	const query = `
+		SELECT
+			  trending_tags.tag AS name
+			, COALESCE(tags.display_tag, trending_tags.tag) AS display
+			, trending_tags.score
+			, trending_tags.authors_count
+			, trending_tags.posts_count
+		FROM trending_tags
+		LEFT JOIN tags ON tags.tag = trending_tags.tag
+		WHERE trending_tags.time_window = @time_window
+		ORDER BY trending_tags.score DESC, trending_tags.tag
+		LIMIT @limit
+	`
+	args := pgx.StrictNamedArgs{
+		"time_window": in.Window,
+		"limit":       trendingTagsLimit,
+	}
+
+	tags, err := pgxutil.Select(ctx, c.db, query, []any{args}, pgx.RowToStructByNameLax[types.TrendingTag])
+	if err != nil {
+		return nil, fmt.Errorf("sql select trending tags: %w", err)
+	}
+
+	return tags, nil
+}
@@ -38,6 +38,7 @@ const (
 		, users.husbando
 		, users.followers_count
 		, users.followees_count
+		, users.private
 	`
 	sqlViewerFollowsUserJoin = `LEFT JOIN follows AS viewer_follows_user ON viewer_follows_user.follower_id = @viewer_id AND viewer_follows_user.followee_id = users.id`
 	sqlUserFollowsViewerJoin = `LEFT JOIN follows AS user_follows_viewer ON user_follows_viewer.follower_id = users.id AND user_follows_viewer.followee_id = @viewer_id`
@@ -47,7 +48,10 @@ func appendViewerRelationshipFields(selects, joins []string) ([]string, []string
 	selects = append(selects,
 		`users.id = @viewer_id AS is_me`,
 		`viewer_follows_user.follower_id IS NOT NULL AS followed_by_viewer`,
-		`user_follows_viewer.follower_id IS NOT NULL AS follows_viewer`)
+		`EXISTS (SELECT 1 FROM follow_requests WHERE follow_requests.follower_id = @viewer_id AND follow_requests.followee_id = users.id) AS follow_requested_by_viewer`,
+		`user_follows_viewer.follower_id IS NOT NULL AS follows_viewer`,
+		`EXISTS (SELECT 1 FROM blocks WHERE blocks.blocker_id = @viewer_id AND blocks.blocked_id = users.id) AS blocked_by_viewer`,
+		`NOT `+sqlNotMuted("@viewer_id", "users.id")+` AS muted_by_viewer`)
 	joins = append(joins, sqlViewerFollowsUserJoin, sqlUserFollowsViewerJoin)
 
 	return selects, joins
@@ -92,7 +96,7 @@ func (c *Cockroach) UserProfiles(ctx context.Context, in types.ListUserProfiles)
 	args := pgx.StrictNamedArgs{}
 	selects := []string{sqlUserProfileCols}
 	joins := []string{}
-	filters := []string{}
+	filters := []string{sqlUserActive}
 
 	if in.SearchUsername != nil {
 		args["search_username"] = *in.SearchUsername
@@ -165,6 +169,7 @@ func (c *Cockroach) Followers(ctx context.Context, in types.ListFollowers) (type
 	}
 	filters := []string{
 		`follows.followee_id = (SELECT id FROM users WHERE username = @username)`,
+		sqlUserActive,
 	}
 
 	if in.ViewerID() != nil {
@@ -228,6 +233,7 @@ func (c *Cockroach) Followees(ctx context.Context, in types.ListFollowees) (type
 	}
 	filters := []string{
 		`follows.follower_id = (SELECT id FROM users WHERE username = @username)`,
+		sqlUserActive,
 	}
 
 	if in.ViewerID() != nil {
@@ -285,7 +291,7 @@ func (c *Cockroach) Usernames(ctx context.Context, in types.ListUsernames) (type
 	var out types.Page[string]
 
 	args := pgx.StrictNamedArgs{"starting_with": in.StartingWith}
-	filters := []string{"users.username ILIKE @starting_with || '%'"}
+	filters := []string{"users.username ILIKE @starting_with || '%'", sqlUserActive}
 
 	if in.ViewerID() != nil {
 		args["viewer_id"] = *in.ViewerID()
@@ -416,8 +422,8 @@ func (c *Cockroach) UserProfileByUsername(ctx context.Context, in types.Retrieve
 		SELECT %s
 		FROM users
 		%s
-		WHERE users.username = @username
-	`, strings.Join(selects, ",\n\t\t"), strings.Join(joins, "\n\t\t"))
+		WHERE users.username = @username AND %s
+	`, strings.Join(selects, ",\n\t\t"), strings.Join(joins, "\n\t\t"), sqlUserActive)
 
 	user, err := pgxutil.SelectRow(ctx, c.db, query, []any{args}, pgx.RowToStructByNameLax[types.UserProfile])
 	if errors.Is(err, pgx.ErrNoRows) {
@@ -446,6 +452,33 @@ func (c *Cockroach) UserIDFromUsername(ctx context.Context, username string) (st
 	return userID, nil
 }
 
+// UserIDsFromUsernames maps each of the given usernames that exists to its user ID.
+func (c *Cockroach) UserIDsFromUsernames(ctx context.Context, usernames []string) (map[string]string, error) {
+	out := map[string]string{}
+	if len(usernames) == 0 {
+		return out, nil
+	}
+
+	query := fmt.Sprintf(`SELECT username, id FROM users WHERE username = ANY(@usernames) AND %s`, sqlUserActive)
+	args := pgx.StrictNamedArgs{"usernames": usernames}
+
+	type userID struct {
+		Username string
+		ID       string
+	}
+
+	users, err := pgxutil.Select(ctx, c.db, query, []any{args}, pgx.RowToStructByName[userID])
+	if err != nil {
+		return nil, fmt.Errorf("sql select user IDs from usernames: %w", err)
+	}
+
+	for _, u := range users {
+		out[u.Username] = u.ID
+	}
+
+	return out, nil
+}
+
 func (c *Cockroach) EmailTaken(ctx context.Context, email, userID string) (bool, error) {
 	const query = `
 		SELECT EXISTS (
@@ -465,36 +498,71 @@ func (c *Cockroach) EmailTaken(ctx context.Context, email, userID string) (bool,
 }
 
 func (c *Cockroach) UpdateUser(ctx context.Context, in types.UpdateUser) error {
-	const query = `
-		UPDATE users
-		SET
-			  username = COALESCE(@username, username)
-			, bio = COALESCE(@bio, bio)
-			, waifu = COALESCE(@waifu, waifu)
-			, husbando = COALESCE(@husbando, husbando)
-		WHERE id = @user_id
-	`
-	args := pgx.StrictNamedArgs{
-		"username": in.Username,
-		"bio":      in.Bio,
-		"waifu":    in.Waifu,
-		"husbando": in.Husbando,
-		"user_id":  in.UserID(),
-	}
-	cmd, err := c.db.Exec(ctx, query, args)
-	if db.IsUniqueViolationError(err, "username") {
-		return errs.ConflictError("username taken")
+	return c.db.RunTx(ctx, func(ctx context.Context) error {
+		const query = `
+			UPDATE users
+			SET
+				  username = COALESCE(@username, username)
+				, bio = COALESCE(@bio, bio)
+				, waifu = COALESCE(@waifu, waifu)
+				, husbando = COALESCE(@husbando, husbando)
+				, private = COALESCE(@private, private)
+			WHERE id = @user_id
+		`
+		args := pgx.StrictNamedArgs{
+			"username": in.Username,
+			"bio":      in.Bio,
+			"waifu":    in.Waifu,
+			"husbando": in.Husbando,
+			"private":  in.Private,
+			"user_id":  in.UserID(),
+		}
+		cmd, err := c.db.Exec(ctx, query, args)
+		if db.IsUniqueViolationError(err, "username") {
+			return errs.ConflictError("username taken")
+		}
+
+		if err != nil {
+			return fmt.Errorf("sql update user: %w", err)
+		}
+
+		if cmd.RowsAffected() == 0 {
+			return errs.NotFoundError("user not found")
+		}
+
+		if in.Private != nil && !*in.Private {
+			return c.approveAllFollowRequests(ctx, in.UserID())
+		}
+
+		return nil
+	})
+}
+
+// UserPrivate reports whether the given user has a private account.
+func (c *Cockroach) UserPrivate(ctx context.Context, userID string) (bool, error) {
+	const query = "SELECT private FROM users WHERE id = @user_id"
+	args := pgx.StrictNamedArgs{"user_id": userID}
+	private, err := pgxutil.SelectRow(ctx, c.db, query, []any{args}, pgx.RowTo[bool])
+	if errors.Is(err, pgx.ErrNoRows) {
+		return false, errs.NotFoundError("user not found")
 	}
 
 	if err != nil {
-		return fmt.Errorf("sql update user: %w", err)
+		return false, fmt.Errorf("sql select user private: %w", err)
 	}
 
-	if cmd.RowsAffected() == 0 {
-		return errs.NotFoundError("user not found")
+	return private, nil
+}
+
+func (c *Cockroach) followersCount(ctx context.Context, userID string) (uint, error) {
+	const query = "SELECT followers_count FROM users WHERE id = @user_id"
+	args := pgx.StrictNamedArgs{"user_id": userID}
+	count, err := pgxutil.SelectRow(ctx, c.db, query, []any{args}, pgx.RowTo[uint])
+	if err != nil {
+		return 0, fmt.Errorf("sql select followers count: %w", err)
 	}
 
-	return nil
+	return count, nil
 }
 
 func (c *Cockroach) UpdateEmail(ctx context.Context, userID, email string) (types.User, error) {
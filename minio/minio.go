@@ -5,13 +5,18 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"net/url"
 	"sync"
+	"time"
 
 	"github.com/minio/minio-go/v7"
 	"github.com/minio/minio-go/v7/pkg/credentials"
 	"golang.org/x/sync/errgroup"
 )
 
+// ErrObjectNotFound is returned when getting an object that does not exist.
+var ErrObjectNotFound = errors.New("object not found")
+
 type Upload struct {
 	Key         string
 	Reader      io.Reader
@@ -142,6 +147,38 @@ func (m *Store) Delete(ctx context.Context, bucket string, objectName string) er
 	return nil
 }
 
+// Get the contents of an object. Callers must close the returned reader.
+func (m *Store) Get(ctx context.Context, bucket string, objectName string) (io.ReadCloser, error) {
+	obj, err := m.client.GetObject(ctx, bucket, objectName, minio.GetObjectOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("get object: %w", err)
+	}
+
+	// GetObject is lazy, stat it to find out whether it exists.
+	if _, err := obj.Stat(); err != nil {
+		_ = obj.Close()
+
+		if minio.ToErrorResponse(err).Code == "NoSuchKey" {
+			return nil, ErrObjectNotFound
+		}
+
+		return nil, fmt.Errorf("stat object: %w", err)
+	}
+
+	return obj, nil
+}
+
+// PresignedGetURL returns a URL to download an object from a private bucket.
+// The URL is valid for the given expiry duration, up to 7 days.
+func (m *Store) PresignedGetURL(ctx context.Context, bucket string, objectName string, expiry time.Duration) (*url.URL, error) {
+	u, err := m.client.PresignedGetObject(ctx, bucket, objectName, expiry, nil)
+	if err != nil {
+		return nil, fmt.Errorf("presign get object: %w", err)
+	}
+
+	return u, nil
+}
+
 func (m *Store) CreateReadOnlyBuckets(ctx context.Context, buckets ...string) error {
 	g, gctx := errgroup.WithContext(ctx)
 
@@ -157,8 +194,9 @@ func (m *Store) CreateReadOnlyBuckets(ctx context.Context, buckets ...string) er
 	return g.Wait()
 }
 
-// CreateReadOnlyBucket creates a bucket and sets up read-only public access policy
-func (m *Store) CreateReadOnlyBucket(ctx context.Context, bucketName string) error {
+// CreatePrivateBucket creates a bucket without public access.
+// Objects in it can only be shared with presigned URLs.
+func (m *Store) CreatePrivateBucket(ctx context.Context, bucketName string) error {
 	exists, err := m.client.BucketExists(ctx, bucketName)
 	if err != nil {
 		return fmt.Errorf("check bucket exists: %w", err)
@@ -171,6 +209,15 @@ func (m *Store) CreateReadOnlyBucket(ctx context.Context, bucketName string) err
 		}
 	}
 
+	return nil
+}
+
+// CreateReadOnlyBucket creates a bucket and sets up read-only public access policy
+func (m *Store) CreateReadOnlyBucket(ctx context.Context, bucketName string) error {
+	if err := m.CreatePrivateBucket(ctx, bucketName); err != nil {
+		return err
+	}
+
 	// Define read-only policy for the bucket
 	readOnlyPolicy := fmt.Sprintf(`{
 		"Version": "2012-10-17",
@@ -184,7 +231,7 @@ func (m *Store) CreateReadOnlyBucket(ctx context.Context, bucketName string) err
 		]
 	}`, bucketName)
 
-	err = m.client.SetBucketPolicy(ctx, bucketName, readOnlyPolicy)
+	err := m.client.SetBucketPolicy(ctx, bucketName, readOnlyPolicy)
 	if err != nil {
 		return fmt.Errorf("set bucket policy: %w", err)
 	}
@@ -0,0 +1,120 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/nakamauwu/nakama/types"
+	"github.com/nicolasparada/go-errs"
+)
+
+// DefaultAccountDeletionGracePeriod is used when the service
+// has no AccountDeletionGracePeriod set.
+const DefaultAccountDeletionGracePeriod = time.Hour * 24 * 30
+
+const accountDeletionsBatch = 10
+
+// RequestAccountDeletion deactivates the authenticated user right away,
+// hiding their content and logging them out everywhere.
+// The account gets permanently deleted after the grace period,
+// unless they log back in before that.
+func (s *Service) RequestAccountDeletion(ctx context.Context) (types.AccountDeletion, error) {
+	var out types.AccountDeletion
+
+	uid, ok := ctx.Value(KeyAuthUserID).(string)
+	if !ok {
+		return out, errs.Unauthenticated
+	}
+
+	out.DeleteAfter = time.Now().Add(s.accountDeletionGracePeriod())
+
+	if err := s.Cockroach.RequestAccountDeletion(ctx, uid, out.DeleteAfter); err != nil {
+		return out, err
+	}
+
+	return out, nil
+}
+
+// StartSession records the session token of a user that just logged in,
+// so it can be revoked later on.
+// Logging in also cancels any pending account deletion.
+func (s *Service) StartSession(ctx context.Context, userID, token string) error {
+	if err := s.Cockroach.CancelAccountDeletion(ctx, userID); err != nil {
+		return err
+	}
+
+	return s.Cockroach.AddUserSession(ctx, userID, token)
+}
+
+// TrackSession records the session token of a user that logged in
+// before sessions were tracked, so it can be revoked later on too.
+// Unlike [Service.StartSession], it revokes the session of a deactivated user
+// instead of canceling its deletion.
+func (s *Service) TrackSession(ctx context.Context, userID, token string) error {
+	if !types.ValidUUIDv4(userID) {
+		return errs.InvalidArgumentError("invalid user ID")
+	}
+
+	return s.Cockroach.TrackUserSession(ctx, userID, token)
+}
+
+func (s *Service) accountDeletionGracePeriod() time.Duration {
+	if s.AccountDeletionGracePeriod <= 0 {
+		return DefaultAccountDeletionGracePeriod
+	}
+
+	return s.AccountDeletionGracePeriod
+}
+
+// deleteDueAccounts permanently deletes the accounts whose grace period is over
+// along with their objects in storage.
+func (s *Service) deleteDueAccounts(ctx context.Context) error {
+	userIDs, err := s.Cockroach.DueAccountDeletions(ctx, accountDeletionsBatch)
+	if err != nil {
+		return err
+	}
+
+	for _, userID := range userIDs {
+		deleted, err := s.Cockroach.DeleteUser(ctx, userID)
+		if errors.Is(err, errs.NotFound) {
+			continue // already deleted by another instance or canceled.
+		}
+
+		if err != nil {
+			_ = s.Logger.Log("error", fmt.Errorf("could not delete user %s: %w", userID, err))
+			continue
+		}
+
+		s.deleteUserObjects(ctx, deleted)
+	}
+
+	return nil
+}
+
+func (s *Service) deleteUserObjects(ctx context.Context, deleted types.DeletedUser) {
+	if deleted.Avatar != nil {
+		if err := s.MinioStore.Delete(ctx, AvatarsBucket, *deleted.Avatar); err != nil {
+			_ = s.Logger.Log("error", fmt.Errorf("could not delete avatar of deleted user: %w", err))
+		}
+	}
+
+	if deleted.Cover != nil {
+		if err := s.MinioStore.Delete(ctx, CoversBucket, *deleted.Cover); err != nil {
+			_ = s.Logger.Log("error", fmt.Errorf("could not delete cover of deleted user: %w", err))
+		}
+	}
+
+	s.deleteMedia(deleted.Media)
+
+	for _, key := range deleted.DataExports {
+		if err := s.MinioStore.Delete(ctx, ExportsBucket, key); err != nil {
+			_ = s.Logger.Log("error", fmt.Errorf("could not delete data export of deleted user: %w", err))
+		}
+	}
+}
+
+func (s *Service) deleteStaleUserSessions(ctx context.Context) error {
+	return s.Cockroach.DeleteStaleUserSessions(ctx)
+}
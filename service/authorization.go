@@ -3,6 +3,7 @@ package service
 import (
 	"context"
 	"fmt"
+	"slices"
 
 	"github.com/nicolasparada/go-errs"
 )
@@ -13,6 +14,7 @@ const (
 	ResourceKindPost         ResourceKind = "post"
 	ResourceKindComment      ResourceKind = "comment"
 	ResourceKindTimelineItem ResourceKind = "timeline_item"
+	ResourceKindDraft        ResourceKind = "draft"
 )
 
 func (svc *Service) authorize(ctx context.Context, resourceKind ResourceKind, resourceID string) error {
@@ -31,6 +33,8 @@ func (svc *Service) authorize(ctx context.Context, resourceKind ResourceKind, re
 		resourceUserID, err = svc.Cockroach.CommentUserID(ctx, resourceID)
 	case ResourceKindTimelineItem:
 		resourceUserID, err = svc.Cockroach.TimelineItemUserID(ctx, resourceID)
+	case ResourceKindDraft:
+		resourceUserID, err = svc.Cockroach.DraftUserID(ctx, resourceID)
 	default:
 		return fmt.Errorf("unknown resource kind %q", resourceKind)
 	}
@@ -45,3 +49,17 @@ func (svc *Service) authorize(ctx context.Context, resourceKind ResourceKind, re
 
 	return nil
 }
+
+// authorizeAdmin checks the authenticated user is one of [Service.AdminUserIDs].
+func (svc *Service) authorizeAdmin(ctx context.Context) error {
+	userID, ok := ctx.Value(KeyAuthUserID).(string)
+	if !ok {
+		return errs.Unauthenticated
+	}
+
+	if !slices.Contains(svc.AdminUserIDs, userID) {
+		return errs.PermissionDenied
+	}
+
+	return nil
+}
@@ -0,0 +1,61 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+const (
+	pollsCloseInterval          = time.Minute
+	draftsPublishInterval       = time.Minute
+	mutesCleanupInterval        = time.Hour
+	accountDeletionInterval     = time.Hour
+	sessionsCleanupInterval     = time.Hour * 24
+	dataExportsInterval         = time.Minute
+	dataExportsCleanupInterval  = time.Hour
+	trendingTagsRefreshInterval = time.Minute * 5
+)
+
+// RunBackgroundJobs runs the periodic jobs of the service until ctx is done.
+// Jobs are safe to run from several instances at the same time.
+func (s *Service) RunBackgroundJobs(ctx context.Context) {
+	var wg sync.WaitGroup
+
+	jobs := []struct {
+		name     string
+		interval time.Duration
+		run      func(context.Context) error
+	}{
+		{name: "close polls", interval: pollsCloseInterval, run: s.closePolls},
+		{name: "publish due drafts", interval: draftsPublishInterval, run: s.publishDueDrafts},
+		{name: "delete expired mutes", interval: mutesCleanupInterval, run: s.deleteExpiredMutes},
+		{name: "delete due accounts", interval: accountDeletionInterval, run: s.deleteDueAccounts},
+		{name: "delete stale user sessions", interval: sessionsCleanupInterval, run: s.deleteStaleUserSessions},
+		{name: "process data exports", interval: dataExportsInterval, run: s.processDataExports},
+		{name: "delete expired data exports", interval: dataExportsCleanupInterval, run: s.deleteExpiredDataExports},
+		{name: "refresh trending tags", interval: trendingTagsRefreshInterval, run: s.refreshTrendingTags},
+	}
+
+	for _, job := range jobs {
+		wg.Go(func() {
+			ticker := time.NewTicker(job.interval)
+			defer ticker.Stop()
+
+			for {
+				if err := job.run(ctx); err != nil && ctx.Err() == nil {
+					_ = s.Logger.Log("error", fmt.Errorf("could not %s: %w", job.name, err))
+				}
+
+				select {
+				case <-ticker.C:
+				case <-ctx.Done():
+					return
+				}
+			}
+		})
+	}
+
+	wg.Wait()
+}
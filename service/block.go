@@ -0,0 +1,111 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/nakamauwu/nakama/types"
+	"github.com/nicolasparada/go-errs"
+)
+
+// Block a user so you stop seeing each other's content and interacting.
+// Follows between the two of you are removed.
+func (s *Service) Block(ctx context.Context, username string) error {
+	username = strings.TrimSpace(username)
+	if !types.ValidUsername(username) {
+		return errs.InvalidArgumentError("invalid username")
+	}
+
+	uid, ok := ctx.Value(KeyAuthUserID).(string)
+	if !ok {
+		return errs.Unauthenticated
+	}
+
+	blockedID, err := s.Cockroach.UserIDFromUsername(ctx, username)
+	if err != nil {
+		return err
+	}
+
+	if uid == blockedID {
+		return errs.PermissionDeniedError("forbidden block")
+	}
+
+	return s.Cockroach.Block(ctx, uid, blockedID)
+}
+
+func (s *Service) Unblock(ctx context.Context, username string) error {
+	username = strings.TrimSpace(username)
+	if !types.ValidUsername(username) {
+		return errs.InvalidArgumentError("invalid username")
+	}
+
+	uid, ok := ctx.Value(KeyAuthUserID).(string)
+	if !ok {
+		return errs.Unauthenticated
+	}
+
+	blockedID, err := s.Cockroach.UserIDFromUsername(ctx, username)
+	if err != nil {
+		return err
+	}
+
+	return s.Cockroach.Unblock(ctx, uid, blockedID)
+}
+
+// Blocks lists the users blocked by the authenticated user.
+func (s *Service) Blocks(ctx context.Context, in types.ListBlocks) (types.Page[types.UserProfile], error) {
+	var out types.Page[types.UserProfile]
+
+	if err := in.Validate(); err != nil {
+		return out, err
+	}
+
+	uid, ok := ctx.Value(KeyAuthUserID).(string)
+	if !ok {
+		return out, errs.Unauthenticated
+	}
+
+	in.SetUserID(uid)
+
+	out, err := s.Cockroach.Blocks(ctx, in)
+	if err != nil {
+		return out, err
+	}
+
+	for i, u := range out.Items {
+		u.SetAvatarURL(s.ObjectsBaseURL, AvatarsBucket)
+		u.SetCoverURL(s.ObjectsBaseURL, CoversBucket)
+		u.Email = ""
+		out.Items[i] = u
+	}
+
+	return out, nil
+}
+
+// blockedInStream reports whether the item from the given user
+// should be skipped from the stream of the viewer because of a block.
+func (s *Service) blockedInStream(ctx context.Context, viewerID, userID string) bool {
+	blocked, err := s.Cockroach.BlockExists(ctx, viewerID, userID)
+	if err != nil {
+		_ = s.Logger.Log("error", fmt.Errorf("could not check block in stream: %w", err))
+		return true
+	}
+
+	return blocked
+}
+
+// checkNotBlocked returns a permission denied error
+// when either user blocked the other.
+func (s *Service) checkNotBlocked(ctx context.Context, userID, otherUserID string) error {
+	blocked, err := s.Cockroach.BlockExists(ctx, userID, otherUserID)
+	if err != nil {
+		return err
+	}
+
+	if blocked {
+		return errs.PermissionDeniedError("blocked")
+	}
+
+	return nil
+}
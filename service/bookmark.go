@@ -0,0 +1,58 @@
+package service
+
+import (
+	"context"
+
+	"github.com/nakamauwu/nakama/types"
+	"github.com/nicolasparada/go-errs"
+)
+
+// ToggleBookmark to privately save a post for later.
+func (s *Service) ToggleBookmark(ctx context.Context, in types.ToggleBookmark) (types.ToggledBookmark, error) {
+	var out types.ToggledBookmark
+
+	if err := in.Validate(); err != nil {
+		return out, err
+	}
+
+	uid, ok := ctx.Value(KeyAuthUserID).(string)
+	if !ok {
+		return out, errs.Unauthenticated
+	}
+
+	if err := s.postVisible(ctx, in.PostID); err != nil {
+		return out, err
+	}
+
+	in.SetUserID(uid)
+
+	return s.Cockroach.ToggleBookmark(ctx, in)
+}
+
+// Bookmarks from the authenticated user, most recently bookmarked first.
+func (s *Service) Bookmarks(ctx context.Context, in types.ListBookmarks) (types.Page[types.Bookmark], error) {
+	var out types.Page[types.Bookmark]
+
+	if err := in.Validate(); err != nil {
+		return out, err
+	}
+
+	uid, ok := ctx.Value(KeyAuthUserID).(string)
+	if !ok {
+		return out, errs.Unauthenticated
+	}
+
+	in.SetUserID(uid)
+
+	out, err := s.Cockroach.Bookmarks(ctx, in)
+	if err != nil {
+		return out, err
+	}
+
+	for i, b := range out.Items {
+		s.setPostURLs(&b.Post)
+		out.Items[i] = b
+	}
+
+	return out, nil
+}
@@ -27,28 +27,55 @@ func (s *Service) CreateComment(ctx context.Context, in types.CreateComment) (ty
 		return c, err
 	}
 
+	if err := s.postVisible(ctx, in.PostID); err != nil {
+		return c, err
+	}
+
+	if in.ParentID != nil {
+		parentUserID, err := s.Cockroach.CommentUserID(ctx, *in.ParentID)
+		if err != nil {
+			return c, err
+		}
+
+		if err := s.checkNotBlocked(ctx, uid, parentUserID); err != nil {
+			return c, err
+		}
+	}
+
 	in.SetUserID(uid)
 	in.SetTags(textutil.CollectTags(in.Content))
 
+	entities, err := s.entities(ctx, in.Content)
+	if err != nil {
+		return c, err
+	}
+
+	in.SetEntities(entities)
+
 	created, err := s.Cockroach.CreateComment(ctx, in)
 	if err != nil {
 		return c, err
 	}
 
 	c.ID = created.ID
+	c.ParentID = created.ParentID
+	c.Depth = created.Depth
 	c.CreatedAt = created.CreatedAt
 
 	c.UserID = uid
 	c.PostID = in.PostID
 	c.Content = in.Content
+	c.Entities = in.Entities()
 	c.Mine = true
 
-	go s.commentCreated(c)
+	go s.commentCreated(c, in.ParentID)
 
 	return c, nil
 }
 
-func (s *Service) commentCreated(c types.Comment) {
+// commentCreated takes the ID of the comment being replied to, if any,
+// which differs from the stored parent when the reply got flattened past [types.CommentMaxDepth].
+func (s *Service) commentCreated(c types.Comment, replyToID *string) {
 	u, err := s.userByID(context.Background(), c.UserID)
 	if err != nil {
 		_ = s.Logger.Log("error", fmt.Errorf("could not fetch comment user: %w", err))
@@ -58,7 +85,10 @@ func (s *Service) commentCreated(c types.Comment) {
 	c.User = &u
 	c.Mine = false
 
-	go s.notifyComment(c)
+	go s.notifyComment(c, replyToID)
+	if replyToID != nil {
+		go s.notifyCommentReply(c, *replyToID)
+	}
 	go s.notifyCommentMention(c)
 	go s.broadcastComment(c)
 }
@@ -81,6 +111,43 @@ func (s *Service) Comments(ctx context.Context, in types.ListComments) (types.Pa
 	}
 
 	for i, c := range out.Items {
+		s.setReactionURLs(c.Reactions)
+
+		if c.User == nil {
+			continue
+		}
+
+		c.User.SetAvatarURL(s.ObjectsBaseURL, AvatarsBucket)
+		out.Items[i] = c
+	}
+
+	return out, nil
+}
+
+// CommentReplies in descending order with backward pagination.
+func (s *Service) CommentReplies(ctx context.Context, in types.ListCommentReplies) (types.Page[types.Comment], error) {
+	var out types.Page[types.Comment]
+
+	if err := in.Validate(); err != nil {
+		return out, err
+	}
+
+	if userID, ok := ctx.Value(KeyAuthUserID).(string); ok {
+		in.SetViewerID(userID)
+	}
+
+	if err := s.commentVisible(ctx, in.CommentID); err != nil {
+		return out, err
+	}
+
+	out, err := s.Cockroach.CommentReplies(ctx, in)
+	if err != nil {
+		return out, err
+	}
+
+	for i, c := range out.Items {
+		s.setReactionURLs(c.Reactions)
+
 		if c.User == nil {
 			continue
 		}
@@ -98,6 +165,10 @@ func (s *Service) CommentStream(ctx context.Context, postID string) (<-chan type
 		return nil, errs.InvalidArgumentError("invalid post ID")
 	}
 
+	if err := s.postVisible(ctx, postID); err != nil {
+		return nil, err
+	}
+
 	cc := make(chan types.Comment)
 	uid, auth := ctx.Value(KeyAuthUserID).(string)
 	unsub, err := s.PubSub.Sub(commentTopic(postID), func(data []byte) {
@@ -113,6 +184,10 @@ func (s *Service) CommentStream(ctx context.Context, postID string) (<-chan type
 				return
 			}
 
+			if auth && s.blockedInStream(ctx, uid, c.UserID) {
+				return
+			}
+
 			cc <- c
 		}(bytes.NewReader(data))
 	})
@@ -145,6 +220,13 @@ func (s *Service) UpdateComment(ctx context.Context, in types.UpdateComment) (ty
 
 	if in.Content != nil {
 		in.SetTags(textutil.CollectTags(*in.Content))
+
+		entities, err := s.entities(ctx, *in.Content)
+		if err != nil {
+			return out, err
+		}
+
+		in.SetEntities(entities)
 	}
 
 	commentCreatedAt, err := s.Cockroach.CommentCreatedAt(ctx, in.ID)
@@ -156,9 +238,32 @@ func (s *Service) UpdateComment(ctx context.Context, in types.UpdateComment) (ty
 		return out, errs.PermissionDeniedError("update comment denied")
 	}
 
-	// TODO: recollect mentions
+	out, err = s.Cockroach.UpdateComment(ctx, in)
+	if err != nil {
+		return out, err
+	}
+
+	if in.Content != nil {
+		uid, _ := ctx.Value(KeyAuthUserID).(string) // already authorized.
+		go s.notifyMentionsUpdate(types.NotificationKindCommentMention, uid, out.PostID, &in.ID, out.PreviousContent, out.Content)
+	}
 
-	return s.Cockroach.UpdateComment(ctx, in)
+	return out, nil
+}
+
+// CommentRevisions lists the previous states of an edited comment, latest first.
+func (s *Service) CommentRevisions(ctx context.Context, in types.ListCommentRevisions) (types.Page[types.CommentRevision], error) {
+	var out types.Page[types.CommentRevision]
+
+	if err := in.Validate(); err != nil {
+		return out, err
+	}
+
+	if err := s.commentVisible(ctx, in.CommentID); err != nil {
+		return out, err
+	}
+
+	return s.Cockroach.CommentRevisions(ctx, in)
 }
 
 func (s *Service) DeleteComment(ctx context.Context, commentID string) error {
@@ -183,9 +288,29 @@ func (s *Service) ToggleCommentReaction(ctx context.Context, in types.ToggleComm
 		return nil, errs.Unauthenticated
 	}
 
+	if err := s.commentVisible(ctx, in.CommentID); err != nil {
+		return nil, err
+	}
+
+	commentUserID, err := s.Cockroach.CommentUserID(ctx, in.CommentID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.checkNotBlocked(ctx, uid, commentUserID); err != nil {
+		return nil, err
+	}
+
 	in.SetUserID(uid)
 
-	return s.Cockroach.ToggleCommentReaction(ctx, in)
+	reactions, err := s.Cockroach.ToggleCommentReaction(ctx, in)
+	if err != nil {
+		return nil, err
+	}
+
+	s.setReactionURLs(reactions)
+
+	return reactions, nil
 }
 
 func (s *Service) broadcastComment(c types.Comment) {
@@ -0,0 +1,142 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	gonanoid "github.com/matoous/go-nanoid/v2"
+	"github.com/nakamauwu/nakama/minio"
+	"github.com/nakamauwu/nakama/types"
+	"github.com/nicolasparada/go-errs"
+)
+
+const (
+	MaxCustomEmojiBytes = 512 << 10 // 512KB
+
+	EmojisBucket = "emojis"
+)
+
+// CreateCustomEmoji uploads a new instance-wide emoji. Only admins can.
+// Animated GIF and WebP images are stored as is so they keep playing.
+// Please limit the image reader before hand using MaxCustomEmojiBytes.
+func (s *Service) CreateCustomEmoji(ctx context.Context, in types.CreateCustomEmoji) (types.CustomEmoji, error) {
+	var out types.CustomEmoji
+
+	if err := in.Validate(); err != nil {
+		return out, err
+	}
+
+	if err := s.authorizeAdmin(ctx); err != nil {
+		return out, err
+	}
+
+	r := in.ImageReader
+
+	ct, err := detectContentType(r)
+	if err != nil {
+		return out, err
+	}
+
+	if !isMediaContentTypeSupported(ct) {
+		return out, errs.InvalidArgumentError("unsupported custom emoji format")
+	}
+
+	if _, _, err := decodeMedia(r, ct); err != nil {
+		return out, err
+	}
+
+	size, err := r.Seek(0, io.SeekEnd)
+	if err != nil {
+		return out, fmt.Errorf("seek custom emoji to end: %w", err)
+	}
+
+	if size > MaxCustomEmojiBytes {
+		return out, errs.InvalidArgumentError("custom emoji too large")
+	}
+
+	_, err = r.Seek(0, io.SeekStart)
+	if err != nil {
+		return out, fmt.Errorf("seek custom emoji to start: %w", err)
+	}
+
+	name, err := gonanoid.New()
+	if err != nil {
+		return out, fmt.Errorf("generate custom emoji name: %w", err)
+	}
+
+	name += contentTypeExtension(ct)
+
+	cleanupImage, err := s.MinioStore.Upload(ctx, EmojisBucket, minio.Upload{
+		Key:         name,
+		Reader:      r,
+		FileSize:    size,
+		ContentType: ct,
+	})
+	if err != nil {
+		return out, fmt.Errorf("could not upload custom emoji file: %w", err)
+	}
+
+	in.SetImage(name)
+
+	out, err = s.Cockroach.CreateCustomEmoji(ctx, in)
+	if err != nil {
+		go func() {
+			if errCleanup := cleanupImage(context.Background()); errCleanup != nil {
+				_ = s.Logger.Log("error", fmt.Errorf("could not cleanup custom emoji file after failed insert: %w", errCleanup))
+			}
+		}()
+
+		return out, err
+	}
+
+	out.SetImageURL(s.ObjectsBaseURL, EmojisBucket)
+
+	return out, nil
+}
+
+// CustomEmojis available to react with.
+func (s *Service) CustomEmojis(ctx context.Context) ([]types.CustomEmoji, error) {
+	out, err := s.Cockroach.CustomEmojis(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range out {
+		out[i].SetImageURL(s.ObjectsBaseURL, EmojisBucket)
+	}
+
+	return out, nil
+}
+
+// DeleteCustomEmoji removes the emoji and every reaction made with it.
+// Only admins can.
+func (s *Service) DeleteCustomEmoji(ctx context.Context, shortcode string) error {
+	if !types.ValidShortcode(shortcode) {
+		return errs.InvalidArgumentError("invalid shortcode")
+	}
+
+	if err := s.authorizeAdmin(ctx); err != nil {
+		return err
+	}
+
+	image, err := s.Cockroach.DeleteCustomEmoji(ctx, shortcode)
+	if err != nil {
+		return err
+	}
+
+	go func() {
+		if err := s.MinioStore.Delete(context.Background(), EmojisBucket, image); err != nil {
+			_ = s.Logger.Log("error", fmt.Errorf("could not delete custom emoji file: %w", err))
+		}
+	}()
+
+	return nil
+}
+
+// setReactionURLs turns the stored image paths of custom reactions into full URLs.
+func (s *Service) setReactionURLs(reactions []types.Reaction) {
+	for i := range reactions {
+		reactions[i].SetImageURL(s.ObjectsBaseURL, EmojisBucket)
+	}
+}
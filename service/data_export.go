@@ -0,0 +1,295 @@
+package service
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"html/template"
+	"io"
+	"net/url"
+	"os"
+	"path"
+	"time"
+
+	"github.com/nakamauwu/nakama/minio"
+	"github.com/nakamauwu/nakama/types"
+	"github.com/nakamauwu/nakama/web"
+	"github.com/nicolasparada/go-errs"
+)
+
+// ExportsBucket is private; archives are shared through presigned URLs only.
+const ExportsBucket = "exports"
+
+// dataExportTTL is how long the download link of an export stays valid.
+// Presigned URLs cannot last longer than 7 days.
+const dataExportTTL = time.Hour * 24 * 7
+
+// dataExportTouchInterval keeps an export claimed while its archive is being built.
+// It must stay well below the time after which other instances consider it stale.
+const dataExportTouchInterval = time.Minute * 5
+
+var tmplDataExportEmail = template.Must(template.New("data-export-email.tmpl").Funcs(emailTemplateFuncs).ParseFS(web.TemplateFiles, "template/data-export-email.tmpl"))
+
+type TemplDataDataExportEmail struct {
+	DownloadLink *url.URL
+	TTL          time.Duration
+}
+
+// RequestDataExport queues an archive with all the data of the authenticated user.
+// Once ready, a link to download it is sent to their email.
+func (s *Service) RequestDataExport(ctx context.Context) (types.DataExport, error) {
+	var out types.DataExport
+
+	uid, ok := ctx.Value(KeyAuthUserID).(string)
+	if !ok {
+		return out, errs.Unauthenticated
+	}
+
+	return s.Cockroach.CreateDataExport(ctx, uid)
+}
+
+// processDataExports builds the pending exports one at a time until there are none left.
+func (s *Service) processDataExports(ctx context.Context) error {
+	for ctx.Err() == nil {
+		export, err := s.Cockroach.ClaimDataExport(ctx)
+		if errors.Is(err, errs.NotFound) {
+			return nil
+		}
+
+		if err != nil {
+			return err
+		}
+
+		if err := s.processDataExport(ctx, export); err != nil {
+			_ = s.Logger.Log("error", fmt.Errorf("could not process data export %s: %w", export.ID, err))
+
+			if err := s.Cockroach.FailDataExport(context.WithoutCancel(ctx), export.ID); err != nil {
+				_ = s.Logger.Log("error", err)
+			}
+		}
+	}
+
+	return ctx.Err()
+}
+
+func (s *Service) processDataExport(ctx context.Context, export types.DataExport) error {
+	defer s.keepDataExportClaimed(ctx, export.ID)()
+
+	data, err := s.Cockroach.UserData(ctx, export.UserID)
+	if err != nil {
+		return err
+	}
+
+	// the archive can get big with media, so it's written to disk instead of memory.
+	f, err := os.CreateTemp("", "nakama-export-*.zip")
+	if err != nil {
+		return fmt.Errorf("create temp file: %w", err)
+	}
+
+	defer os.Remove(f.Name())
+	defer f.Close()
+
+	if err := s.writeDataExport(ctx, f, data); err != nil {
+		return err
+	}
+
+	info, err := f.Stat()
+	if err != nil {
+		return fmt.Errorf("stat export archive: %w", err)
+	}
+
+	if _, err := f.Seek(0, io.SeekStart); err != nil {
+		return fmt.Errorf("rewind export archive: %w", err)
+	}
+
+	objectKey := path.Join(export.UserID, export.ID+".zip")
+	cleanupArchive, err := s.MinioStore.Upload(ctx, ExportsBucket, minio.Upload{
+		Key:         objectKey,
+		Reader:      f,
+		FileSize:    info.Size(),
+		ContentType: "application/zip",
+	})
+	if err != nil {
+		return err
+	}
+
+	downloadLink, err := s.MinioStore.PresignedGetURL(ctx, ExportsBucket, objectKey, dataExportTTL)
+	if err != nil {
+		return err
+	}
+
+	if err := s.Cockroach.CompleteDataExport(ctx, export.ID, objectKey, time.Now().Add(dataExportTTL)); err != nil {
+		// without a row, nothing would ever remove the archive.
+		if errCleanup := cleanupArchive(context.WithoutCancel(ctx)); errCleanup != nil {
+			_ = s.Logger.Log("error", fmt.Errorf("could not cleanup data export archive after failed completion: %w", errCleanup))
+		}
+		return err
+	}
+
+	// the export is ready by now, failing it would throw the archive away.
+	if err := s.sendDataExportEmail(ctx, data.Email, downloadLink); err != nil {
+		_ = s.Logger.Log("error", fmt.Errorf("could not send data export %s email: %w", export.ID, err))
+	}
+
+	return nil
+}
+
+// keepDataExportClaimed touches the export every [dataExportTouchInterval]
+// until the returned func is called,
+// so other instances don't take a big archive that is still being built for a stale one.
+func (s *Service) keepDataExportClaimed(ctx context.Context, dataExportID string) func() {
+	ctx, cancel := context.WithCancel(ctx)
+	done := make(chan struct{})
+
+	go func() {
+		defer close(done)
+
+		ticker := time.NewTicker(dataExportTouchInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if err := s.Cockroach.TouchDataExport(ctx, dataExportID); err != nil && ctx.Err() == nil {
+					_ = s.Logger.Log("error", err)
+				}
+			}
+		}
+	}()
+
+	return func() {
+		cancel()
+		<-done
+	}
+}
+
+// writeDataExport writes a zip archive with a JSON file per kind of data
+// and the avatar, cover and media files of the user.
+func (s *Service) writeDataExport(ctx context.Context, w io.Writer, data types.UserData) error {
+	zw := zip.NewWriter(w)
+
+	files := []struct {
+		name string
+		data json.RawMessage
+	}{
+		{name: "profile.json", data: data.Profile},
+		{name: "posts.json", data: data.Posts},
+		{name: "drafts.json", data: data.Drafts},
+		{name: "comments.json", data: data.Comments},
+		{name: "post_reactions.json", data: data.PostReactions},
+		{name: "comment_reactions.json", data: data.CommentReactions},
+		{name: "following.json", data: data.Following},
+		{name: "followers.json", data: data.Followers},
+		{name: "notifications.json", data: data.Notifications},
+	}
+
+	for _, file := range files {
+		fw, err := zw.Create(file.name)
+		if err != nil {
+			return fmt.Errorf("create %s in export archive: %w", file.name, err)
+		}
+
+		var buf bytes.Buffer
+		if err := json.Indent(&buf, file.data, "", "\t"); err != nil {
+			return fmt.Errorf("indent %s: %w", file.name, err)
+		}
+
+		if _, err := buf.WriteTo(fw); err != nil {
+			return fmt.Errorf("write %s in export archive: %w", file.name, err)
+		}
+	}
+
+	if data.Avatar != nil {
+		if err := s.writeDataExportObject(ctx, zw, AvatarsBucket, *data.Avatar); err != nil {
+			return err
+		}
+	}
+
+	if data.Cover != nil {
+		if err := s.writeDataExportObject(ctx, zw, CoversBucket, *data.Cover); err != nil {
+			return err
+		}
+	}
+
+	for _, m := range data.Media {
+		if err := s.writeDataExportObject(ctx, zw, MediaBucket, m.Path); err != nil {
+			return err
+		}
+	}
+
+	if err := zw.Close(); err != nil {
+		return fmt.Errorf("close export archive: %w", err)
+	}
+
+	return nil
+}
+
+// writeDataExportObject copies an object from storage into the archive
+// under a directory named after its bucket.
+// Objects that no longer exist are skipped.
+func (s *Service) writeDataExportObject(ctx context.Context, zw *zip.Writer, bucket, objectName string) error {
+	obj, err := s.MinioStore.Get(ctx, bucket, objectName)
+	if errors.Is(err, minio.ErrObjectNotFound) {
+		return nil
+	}
+
+	if err != nil {
+		return fmt.Errorf("get %s/%s: %w", bucket, objectName, err)
+	}
+
+	defer obj.Close()
+
+	// media is already compressed.
+	fw, err := zw.CreateHeader(&zip.FileHeader{
+		Name:   path.Join(bucket, objectName),
+		Method: zip.Store,
+	})
+	if err != nil {
+		return fmt.Errorf("create %s/%s in export archive: %w", bucket, objectName, err)
+	}
+
+	if _, err := io.Copy(fw, obj); err != nil {
+		return fmt.Errorf("copy %s/%s into export archive: %w", bucket, objectName, err)
+	}
+
+	return nil
+}
+
+func (s *Service) sendDataExportEmail(ctx context.Context, email string, downloadLink *url.URL) error {
+	data := TemplDataDataExportEmail{
+		DownloadLink: downloadLink,
+		TTL:          dataExportTTL,
+	}
+
+	var buf bytes.Buffer
+	if err := tmplDataExportEmail.Execute(&buf, data); err != nil {
+		return fmt.Errorf("render data export email template: %w", err)
+	}
+
+	return s.Sender.Send(ctx, email, "Your Nakama data export is ready", buf.String(), fmt.Sprintf(
+		"Your data export is ready. Use this link to download it. The link is valid for %s.\n\n%s",
+		humanDuration(dataExportTTL),
+		downloadLink,
+	))
+}
+
+// deleteExpiredDataExports removes exports whose download link expired.
+func (s *Service) deleteExpiredDataExports(ctx context.Context) error {
+	objectKeys, err := s.Cockroach.DeleteExpiredDataExports(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, key := range objectKeys {
+		if err := s.MinioStore.Delete(ctx, ExportsBucket, key); err != nil {
+			_ = s.Logger.Log("error", fmt.Errorf("could not delete expired data export: %w", err))
+		}
+	}
+
+	return nil
+}
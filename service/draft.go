@@ -0,0 +1,268 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/nakamauwu/nakama/textutil"
+	"github.com/nakamauwu/nakama/types"
+	"github.com/nicolasparada/go-errs"
+)
+
+const draftsPublishBatch = 50
+
+func (s *Service) CreateDraft(ctx context.Context, in types.CreateDraft) (types.Draft, error) {
+	var out types.Draft
+
+	if err := in.Validate(); err != nil {
+		return out, err
+	}
+
+	uid, ok := ctx.Value(KeyAuthUserID).(string)
+	if !ok {
+		return out, errs.Unauthenticated
+	}
+
+	media, err := processMedia(in.MediaReaders)
+	if err != nil {
+		return out, err
+	}
+
+	in.SetMedia(media)
+	in.SetUserID(uid)
+
+	cleanupMedia, err := s.storeMedia(ctx, media)
+	if err != nil {
+		return out, err
+	}
+
+	created, err := s.Cockroach.CreateDraft(ctx, in)
+	if err != nil {
+		go func() {
+			if errCleanup := cleanupMedia(context.Background()); errCleanup != nil {
+				_ = s.Logger.Log("error", fmt.Errorf("cleanup media after failed CreateDraft: %w", errCleanup))
+			}
+		}()
+		return out, err
+	}
+
+	out = types.Draft{
+		ID:         created.ID,
+		UserID:     uid,
+		Content:    in.Content,
+		SpoilerOf:  in.SpoilerOf,
+		NSFW:       in.NSFW,
+		Visibility: in.Visibility,
+		Media:      media,
+		PublishAt:  in.PublishAt,
+		CreatedAt:  created.CreatedAt,
+		UpdatedAt:  created.CreatedAt,
+	}
+	out.SetMediaPaths(s.ObjectsBaseURL, MediaBucket)
+
+	return out, nil
+}
+
+// Drafts from the authenticated user, scheduled ones included.
+func (s *Service) Drafts(ctx context.Context, in types.ListDrafts) (types.Page[types.Draft], error) {
+	var out types.Page[types.Draft]
+
+	if err := in.Validate(); err != nil {
+		return out, err
+	}
+
+	uid, ok := ctx.Value(KeyAuthUserID).(string)
+	if !ok {
+		return out, errs.Unauthenticated
+	}
+
+	in.SetUserID(uid)
+
+	out, err := s.Cockroach.Drafts(ctx, in)
+	if err != nil {
+		return out, err
+	}
+
+	for i, d := range out.Items {
+		d.SetMediaPaths(s.ObjectsBaseURL, MediaBucket)
+		out.Items[i] = d
+	}
+
+	return out, nil
+}
+
+func (s *Service) Draft(ctx context.Context, draftID string) (types.Draft, error) {
+	var out types.Draft
+
+	if !types.ValidUUIDv4(draftID) {
+		return out, errs.InvalidArgumentError("invalid draft ID")
+	}
+
+	if err := s.authorize(ctx, ResourceKindDraft, draftID); err != nil {
+		return out, err
+	}
+
+	out, err := s.Cockroach.Draft(ctx, draftID)
+	if err != nil {
+		return out, err
+	}
+
+	out.SetMediaPaths(s.ObjectsBaseURL, MediaBucket)
+
+	return out, nil
+}
+
+func (s *Service) UpdateDraft(ctx context.Context, in types.UpdateDraft) (types.Draft, error) {
+	var out types.Draft
+
+	if err := in.Validate(); err != nil {
+		return out, err
+	}
+
+	if err := s.authorize(ctx, ResourceKindDraft, in.ID); err != nil {
+		return out, err
+	}
+
+	out, err := s.Cockroach.UpdateDraft(ctx, in)
+	if err != nil {
+		return out, err
+	}
+
+	out.SetMediaPaths(s.ObjectsBaseURL, MediaBucket)
+
+	return out, nil
+}
+
+func (s *Service) DeleteDraft(ctx context.Context, draftID string) error {
+	if !types.ValidUUIDv4(draftID) {
+		return errs.InvalidArgumentError("invalid draft ID")
+	}
+
+	if err := s.authorize(ctx, ResourceKindDraft, draftID); err != nil {
+		return err
+	}
+
+	media, err := s.Cockroach.DeleteDraft(ctx, draftID)
+	if err != nil {
+		return err
+	}
+
+	go s.deleteMedia(media)
+
+	return nil
+}
+
+// PublishDraft right away, regardless of it being scheduled or not.
+func (s *Service) PublishDraft(ctx context.Context, draftID string) (types.TimelineItem, error) {
+	var out types.TimelineItem
+
+	if !types.ValidUUIDv4(draftID) {
+		return out, errs.InvalidArgumentError("invalid draft ID")
+	}
+
+	if err := s.authorize(ctx, ResourceKindDraft, draftID); err != nil {
+		return out, err
+	}
+
+	return s.publishDraft(ctx, draftID)
+}
+
+func (s *Service) publishDraft(ctx context.Context, draftID string) (types.TimelineItem, error) {
+	var out types.TimelineItem
+
+	var in types.CreatePost
+	var created types.CreatedTimelineItem
+	err := s.Cockroach.PublishDraft(ctx, draftID, func(ctx context.Context, draft types.Draft) error {
+		in = types.CreatePost{
+			Content:    draft.Content,
+			SpoilerOf:  draft.SpoilerOf,
+			NSFW:       draft.NSFW,
+			Visibility: draft.Visibility,
+		}
+		if err := in.Validate(); err != nil {
+			return err
+		}
+
+		in.SetUserID(draft.UserID)
+		in.SetMedia(draft.Media)
+		in.SetTags(textutil.CollectTags(in.Content))
+		in.SetMentions(textutil.CollectMentions(in.Content))
+
+		entities, err := s.entities(ctx, in.Content)
+		if err != nil {
+			return err
+		}
+
+		in.SetEntities(entities)
+
+		created, err = s.Cockroach.CreatePost(ctx, in)
+		return err
+	})
+	if err != nil {
+		return out, err
+	}
+
+	post := s.createdPost(ctx, in.UserID(), in, created)
+
+	go s.postCreated(post)
+
+	out.ID = created.TimelineItemID
+	out.UserID = in.UserID()
+	out.PostID = post.ID
+	out.Post = post
+
+	return out, nil
+}
+
+// publishDueDrafts publishes scheduled drafts whose time has come.
+func (s *Service) publishDueDrafts(ctx context.Context) error {
+	draftIDs, err := s.Cockroach.DueDraftIDs(ctx, draftsPublishBatch)
+	if err != nil {
+		return err
+	}
+
+	for _, draftID := range draftIDs {
+		_, err := s.publishDraft(ctx, draftID)
+		if errors.Is(err, errs.NotFound) {
+			continue // already published by another instance
+		}
+
+		// retrying won't fix an invalid draft,
+		// so take it out of the queue and let the author know.
+		if errors.Is(err, errs.InvalidArgument) {
+			if err := s.failScheduledDraft(ctx, draftID); err != nil {
+				_ = s.Logger.Log("error", fmt.Errorf("could not unschedule failed draft %s: %w", draftID, err))
+			}
+			continue
+		}
+
+		if err != nil {
+			_ = s.Logger.Log("error", fmt.Errorf("could not publish draft %s: %w", draftID, err))
+		}
+	}
+
+	return nil
+}
+
+func (s *Service) failScheduledDraft(ctx context.Context, draftID string) error {
+	created, err := s.Cockroach.FailScheduledDraft(ctx, draftID)
+	if errors.Is(err, errs.NotFound) {
+		return nil // already handled by another instance
+	}
+
+	if err != nil {
+		return err
+	}
+
+	notifications, err := s.notificationsByIDs(ctx, []string{created.ID})
+	if err != nil {
+		return fmt.Errorf("could not get notifications by IDs: %w", err)
+	}
+
+	for _, n := range notifications {
+		go s.broadcastNotification(n)
+	}
+
+	return nil
+}
@@ -0,0 +1,71 @@
+package service
+
+import (
+	"context"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/nakamauwu/nakama/types"
+	"github.com/nicolasparada/go-errs"
+)
+
+const embedTimeout = time.Second * 15
+
+const (
+	// ErrUnsupportedEmbed denotes a link from a third party that can't be embedded.
+	ErrUnsupportedEmbed = errs.InvalidArgumentError("unsupported embed URL")
+	// ErrEmbedNotFound denotes a supported link the third party had nothing to embed for.
+	ErrEmbedNotFound = errs.NotFoundError("embed not found")
+)
+
+// Embed resolves a link to a supported third party into something clients can render.
+// Bluesky posts go through their oEmbed endpoint and Tenor GIFs through their page metadata,
+// so clients don't need the media proxy to fetch documents.
+func (s *Service) Embed(ctx context.Context, rawURL string) (types.Embed, error) {
+	var out types.Embed
+
+	u, err := url.Parse(rawURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.User != nil {
+		return out, ErrUnsupportedEmbed
+	}
+
+	if s.Unfurler == nil {
+		return out, ErrEmbedNotFound
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, embedTimeout)
+	defer cancel()
+
+	switch {
+	case u.Hostname() == "bsky.app" && strings.Contains(u.Path, "/post/"):
+		endpoint := "https://embed.bsky.app/oembed?url=" + url.QueryEscape(u.String())
+		oembed, err := s.Unfurler.OEmbed(ctx, endpoint)
+		if err != nil || oembed.HTML == "" {
+			return out, ErrEmbedNotFound
+		}
+
+		out.Kind = types.EmbedKindHTML
+		out.HTML = oembed.HTML
+	case u.Hostname() == "tenor.com" || u.Hostname() == "www.tenor.com":
+		meta, err := s.Unfurler.Unfurl(ctx, u.String())
+		if err != nil {
+			return out, ErrEmbedNotFound
+		}
+
+		switch {
+		case meta.ImageURL != "":
+			out.Kind = types.EmbedKindImage
+			out.URL = s.proxyURL(meta.ImageURL)
+		case meta.VideoURL != "":
+			out.Kind = types.EmbedKindVideo
+			out.URL = s.proxyURL(meta.VideoURL)
+		default:
+			return out, ErrEmbedNotFound
+		}
+	default:
+		return out, ErrUnsupportedEmbed
+	}
+
+	return out, nil
+}
@@ -0,0 +1,31 @@
+package service
+
+import (
+	"context"
+
+	"github.com/nakamauwu/nakama/textutil"
+	"github.com/nakamauwu/nakama/types"
+)
+
+// entities finds the entities in the given content
+// and resolves mentions to the IDs of the users that exist.
+func (s *Service) entities(ctx context.Context, content string) ([]types.Entity, error) {
+	entities := types.MakeEntities(content)
+
+	userIDs, err := s.Cockroach.UserIDsFromUsernames(ctx, textutil.CollectMentions(content))
+	if err != nil {
+		return nil, err
+	}
+
+	for i, e := range entities {
+		if e.Kind != textutil.EntityKindMention {
+			continue
+		}
+
+		if id, ok := userIDs[e.Value]; ok {
+			entities[i].UserID = &id
+		}
+	}
+
+	return entities, nil
+}
@@ -0,0 +1,85 @@
+package service
+
+import (
+	"context"
+	"strings"
+
+	"github.com/nakamauwu/nakama/types"
+	"github.com/nicolasparada/go-errs"
+)
+
+// FollowRequests pending approval by the authenticated user.
+func (s *Service) FollowRequests(ctx context.Context, in types.ListFollowRequests) (types.Page[types.UserProfile], error) {
+	var out types.Page[types.UserProfile]
+
+	if err := in.Validate(); err != nil {
+		return out, err
+	}
+
+	uid, ok := ctx.Value(KeyAuthUserID).(string)
+	if !ok {
+		return out, errs.Unauthenticated
+	}
+
+	in.SetUserID(uid)
+
+	out, err := s.Cockroach.FollowRequests(ctx, in)
+	if err != nil {
+		return out, err
+	}
+
+	for i, u := range out.Items {
+		u.SetAvatarURL(s.ObjectsBaseURL, AvatarsBucket)
+		u.SetCoverURL(s.ObjectsBaseURL, CoversBucket)
+		u.Email = ""
+		out.Items[i] = u
+	}
+
+	return out, nil
+}
+
+// ApproveFollowRequest from the given user to the authenticated user.
+func (s *Service) ApproveFollowRequest(ctx context.Context, username string) error {
+	followeeID, followerID, err := s.followRequestUsers(ctx, username)
+	if err != nil {
+		return err
+	}
+
+	if err := s.Cockroach.ApproveFollowRequest(ctx, followerID, followeeID); err != nil {
+		return err
+	}
+
+	go s.notifyFollowAccepted(followerID, followeeID)
+
+	return nil
+}
+
+// RejectFollowRequest from the given user to the authenticated user.
+// The follower is not notified.
+func (s *Service) RejectFollowRequest(ctx context.Context, username string) error {
+	followeeID, followerID, err := s.followRequestUsers(ctx, username)
+	if err != nil {
+		return err
+	}
+
+	return s.Cockroach.RejectFollowRequest(ctx, followerID, followeeID)
+}
+
+func (s *Service) followRequestUsers(ctx context.Context, username string) (followeeID, followerID string, err error) {
+	username = strings.TrimSpace(username)
+	if !types.ValidUsername(username) {
+		return "", "", errs.InvalidArgumentError("invalid username")
+	}
+
+	followeeID, ok := ctx.Value(KeyAuthUserID).(string)
+	if !ok {
+		return "", "", errs.Unauthenticated
+	}
+
+	followerID, err = s.Cockroach.UserIDFromUsername(ctx, username)
+	if err != nil {
+		return "", "", err
+	}
+
+	return followeeID, followerID, nil
+}
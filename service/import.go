@@ -0,0 +1,207 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"path"
+	"slices"
+	"time"
+
+	"github.com/nakamauwu/nakama/textutil"
+	"github.com/nakamauwu/nakama/types"
+	"github.com/nicolasparada/go-errs"
+)
+
+// archivePost is a post read from an archive of another network,
+// before being imported.
+type archivePost struct {
+	SourceID   string
+	Content    string
+	SpoilerOf  *string
+	NSFW       bool
+	Visibility types.PostVisibility
+	CreatedAt  time.Time
+	// MediaPaths are relative to the root of the archive.
+	MediaPaths []string
+}
+
+// ImportArchive imports the posts of the authenticated user
+// from a Mastodon export or a Twitter archive, keeping their original timestamps.
+// Mastodon exports are recognized by their outbox.json file at the root,
+// Twitter archives by their data/tweets.js file.
+// Importing the same archive again only adds the posts that are missing.
+func (s *Service) ImportArchive(ctx context.Context, fsys fs.FS) (types.ImportReport, error) {
+	var out types.ImportReport
+
+	uid, ok := ctx.Value(KeyAuthUserID).(string)
+	if !ok {
+		return out, errs.Unauthenticated
+	}
+
+	var posts []archivePost
+	var err error
+	switch {
+	case archiveFileExists(fsys, mastodonOutboxPath):
+		out.Source = types.ImportSourceMastodon
+		posts, out.Skipped, err = readMastodonOutbox(fsys)
+	case archiveFileExists(fsys, twitterTweetsPath) || archiveFileExists(fsys, twitterLegacyTweetsPath):
+		out.Source = types.ImportSourceTwitter
+		posts, out.Skipped, err = readTwitterArchive(fsys)
+	default:
+		return out, errs.InvalidArgumentError("unsupported archive")
+	}
+	if err != nil {
+		return out, err
+	}
+
+	// oldest first so they come out in order.
+	slices.SortFunc(posts, func(a, b archivePost) int {
+		return a.CreatedAt.Compare(b.CreatedAt)
+	})
+
+	for _, p := range posts {
+		skipped, err := s.importPost(ctx, uid, out.Source, fsys, p)
+		out.Skipped = append(out.Skipped, skipped...)
+		if errors.Is(err, errs.InvalidArgument) || errors.Is(err, errs.Conflict) {
+			out.Skipped = append(out.Skipped, types.ImportSkipped{
+				SourceID: p.SourceID,
+				Reason:   err.Error(),
+			})
+			continue
+		}
+
+		if err != nil {
+			return out, err
+		}
+
+		out.Imported++
+	}
+
+	return out, nil
+}
+
+// importPost creates the given post along with its media.
+// It returns the attachments that had to be left out.
+// Imported posts are history: they only go to the author's timeline,
+// without fanout nor notifications.
+func (s *Service) importPost(ctx context.Context, uid string, source types.ImportSource, fsys fs.FS, p archivePost) ([]types.ImportSkipped, error) {
+	exists, err := s.Cockroach.ImportedPostExists(ctx, uid, source, p.SourceID)
+	if err != nil {
+		return nil, err
+	}
+
+	if exists {
+		return nil, errs.ConflictError("post already imported")
+	}
+
+	in := types.CreatePost{
+		Content:    p.Content,
+		SpoilerOf:  p.SpoilerOf,
+		NSFW:       p.NSFW,
+		Visibility: p.Visibility,
+	}
+
+	if err := in.Validate(); err != nil {
+		return nil, err
+	}
+
+	var skipped []types.ImportSkipped
+	var media []types.Media
+	for _, name := range p.MediaPaths {
+		if len(media) == types.PostMaxMediaItems {
+			skipped = append(skipped, types.ImportSkipped{
+				SourceID: p.SourceID,
+				Reason:   fmt.Sprintf("media %s: too many media items", path.Base(name)),
+			})
+			continue
+		}
+
+		m, err := readArchiveMedia(fsys, name)
+		if errors.Is(err, errs.InvalidArgument) {
+			skipped = append(skipped, types.ImportSkipped{
+				SourceID: p.SourceID,
+				Reason:   fmt.Sprintf("media %s: %v", path.Base(name), err),
+			})
+			continue
+		}
+
+		if err != nil {
+			return skipped, err
+		}
+
+		media = append(media, m)
+	}
+
+	in.SetUserID(uid)
+	in.SetTags(textutil.CollectTags(in.Content))
+	in.SetMedia(media)
+	in.SetCreatedAt(p.CreatedAt)
+
+	entities, err := s.entities(ctx, in.Content)
+	if err != nil {
+		return skipped, err
+	}
+
+	in.SetEntities(entities)
+
+	cleanupMedia, err := s.storeMedia(ctx, media)
+	if err != nil {
+		return skipped, err
+	}
+
+	_, err = s.Cockroach.ImportPost(ctx, types.ImportPost{
+		CreatePost: in,
+		Source:     source,
+		SourceID:   p.SourceID,
+	})
+	if err != nil {
+		if errCleanup := cleanupMedia(context.WithoutCancel(ctx)); errCleanup != nil {
+			_ = s.Logger.Log("error", fmt.Errorf("cleanup media after failed ImportPost: %w", errCleanup))
+		}
+		return skipped, err
+	}
+
+	return skipped, nil
+}
+
+// readArchiveMedia reads and processes a media item from the archive.
+// It returns an invalid argument error when the item is missing or not supported.
+func readArchiveMedia(fsys fs.FS, name string) (types.Media, error) {
+	var out types.Media
+
+	f, err := fsys.Open(name)
+	if errors.Is(err, fs.ErrNotExist) {
+		return out, errs.InvalidArgumentError("media item not found in archive")
+	}
+
+	if err != nil {
+		return out, fmt.Errorf("open archive media item: %w", err)
+	}
+
+	defer f.Close()
+
+	b, err := io.ReadAll(io.LimitReader(f, MaxMediaItemBytes+1))
+	if err != nil {
+		return out, fmt.Errorf("read archive media item: %w", err)
+	}
+
+	if len(b) > MaxMediaItemBytes {
+		return out, ErrMediaItemTooLarge
+	}
+
+	media, err := processMedia([]io.ReadSeeker{bytes.NewReader(b)})
+	if err != nil {
+		return out, err
+	}
+
+	return media[0], nil
+}
+
+func archiveFileExists(fsys fs.FS, name string) bool {
+	_, err := fs.Stat(fsys, name)
+	return err == nil
+}
@@ -0,0 +1,182 @@
+package service
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/nakamauwu/nakama/types"
+	"github.com/nicolasparada/go-errs"
+	"golang.org/x/net/html"
+)
+
+const mastodonOutboxPath = "outbox.json"
+
+const activityStreamsPublic = "https://www.w3.org/ns/activitystreams#Public"
+
+type mastodonOutbox struct {
+	OrderedItems []mastodonActivity `json:"orderedItems"`
+}
+
+type mastodonActivity struct {
+	ID   string `json:"id"`
+	Type string `json:"type"`
+	// Object is a note for "Create" activities
+	// and just the ID of the boosted status for "Announce" ones.
+	Object json.RawMessage `json:"object"`
+}
+
+type mastodonNote struct {
+	ID           string               `json:"id"`
+	Type         string               `json:"type"`
+	Summary      *string              `json:"summary"`
+	InReplyTo    *string              `json:"inReplyTo"`
+	Published    time.Time            `json:"published"`
+	AttributedTo string               `json:"attributedTo"`
+	To           []string             `json:"to"`
+	CC           []string             `json:"cc"`
+	Sensitive    bool                 `json:"sensitive"`
+	Content      string               `json:"content"`
+	Attachment   []mastodonAttachment `json:"attachment"`
+}
+
+type mastodonAttachment struct {
+	URL string `json:"url"`
+}
+
+// readMastodonOutbox reads the statuses of an extracted Mastodon export.
+// Boosts, replies to others and direct messages are skipped.
+func readMastodonOutbox(fsys fs.FS) ([]archivePost, []types.ImportSkipped, error) {
+	b, err := fs.ReadFile(fsys, mastodonOutboxPath)
+	if err != nil {
+		return nil, nil, fmt.Errorf("read mastodon outbox: %w", err)
+	}
+
+	var outbox mastodonOutbox
+	if err := json.Unmarshal(b, &outbox); err != nil {
+		return nil, nil, errs.InvalidArgumentError("invalid mastodon outbox")
+	}
+
+	var posts []archivePost
+	var skipped []types.ImportSkipped
+	for _, activity := range outbox.OrderedItems {
+		if activity.Type == "Announce" {
+			skipped = append(skipped, types.ImportSkipped{
+				SourceID: activity.ID,
+				Reason:   "boosts not supported",
+			})
+			continue
+		}
+
+		if activity.Type != "Create" {
+			continue
+		}
+
+		var note mastodonNote
+		if err := json.Unmarshal(activity.Object, &note); err != nil || note.Type != "Note" {
+			skipped = append(skipped, types.ImportSkipped{
+				SourceID: activity.ID,
+				Reason:   "only notes supported",
+			})
+			continue
+		}
+
+		// replies to your own statuses are threads, those are kept.
+		if note.InReplyTo != nil && !strings.HasPrefix(*note.InReplyTo, note.AttributedTo+"/") {
+			skipped = append(skipped, types.ImportSkipped{
+				SourceID: note.ID,
+				Reason:   "replies not supported",
+			})
+			continue
+		}
+
+		visibility, ok := mastodonVisibility(note)
+		if !ok {
+			skipped = append(skipped, types.ImportSkipped{
+				SourceID: note.ID,
+				Reason:   "direct messages not supported",
+			})
+			continue
+		}
+
+		p := archivePost{
+			SourceID:   note.ID,
+			Content:    htmlToText(note.Content),
+			Visibility: visibility,
+			CreatedAt:  note.Published,
+		}
+
+		if note.Summary != nil && strings.TrimSpace(*note.Summary) != "" {
+			p.SpoilerOf = note.Summary
+		} else {
+			p.NSFW = note.Sensitive
+		}
+
+		for _, a := range note.Attachment {
+			p.MediaPaths = append(p.MediaPaths, mastodonMediaPath(a.URL))
+		}
+
+		posts = append(posts, p)
+	}
+
+	return posts, skipped, nil
+}
+
+// mastodonVisibility maps public and unlisted statuses to public posts,
+// and followers-only statuses to followers posts.
+// Direct statuses have no counterpart.
+func mastodonVisibility(note mastodonNote) (types.PostVisibility, bool) {
+	if slices.Contains(note.To, activityStreamsPublic) || slices.Contains(note.CC, activityStreamsPublic) {
+		return types.PostVisibilityPublic, true
+	}
+
+	for _, to := range note.To {
+		if strings.HasSuffix(to, "/followers") {
+			return types.PostVisibilityFollowers, true
+		}
+	}
+
+	return "", false
+}
+
+// mastodonMediaPath turns the URL of an attachment into its path inside the export.
+func mastodonMediaPath(s string) string {
+	if u, err := url.Parse(s); err == nil {
+		s = u.Path
+	}
+
+	return strings.TrimLeft(s, "/")
+}
+
+// htmlToText turns the HTML of a status into plain text,
+// keeping line and paragraph breaks.
+func htmlToText(s string) string {
+	doc, err := html.Parse(strings.NewReader(s))
+	if err != nil {
+		return s
+	}
+
+	var b strings.Builder
+	var walk func(n *html.Node)
+	walk = func(n *html.Node) {
+		switch {
+		case n.Type == html.TextNode:
+			b.WriteString(n.Data)
+		case n.Type == html.ElementNode && n.Data == "br":
+			b.WriteString("\n")
+		case n.Type == html.ElementNode && n.Data == "p" && b.Len() != 0:
+			b.WriteString("\n\n")
+		}
+
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			walk(c)
+		}
+	}
+	walk(doc)
+
+	return b.String()
+}
@@ -0,0 +1,186 @@
+package service
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"html"
+	"io/fs"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/nakamauwu/nakama/types"
+	"github.com/nicolasparada/go-errs"
+)
+
+const (
+	twitterTweetsPath       = "data/tweets.js"
+	twitterLegacyTweetsPath = "data/tweet.js"
+	twitterAccountPath      = "data/account.js"
+)
+
+type twitterTweet struct {
+	ID                string `json:"id_str"`
+	FullText          string `json:"full_text"`
+	CreatedAt         string `json:"created_at"`
+	InReplyToUserID   string `json:"in_reply_to_user_id_str"`
+	PossiblySensitive bool   `json:"possibly_sensitive"`
+	Entities          struct {
+		URLs []struct {
+			URL         string `json:"url"`
+			ExpandedURL string `json:"expanded_url"`
+		} `json:"urls"`
+	} `json:"entities"`
+	ExtendedEntities struct {
+		Media []struct {
+			URL           string `json:"url"`
+			MediaURLHTTPS string `json:"media_url_https"`
+		} `json:"media"`
+	} `json:"extended_entities"`
+}
+
+type twitterAccount struct {
+	AccountID string `json:"accountId"`
+}
+
+// readTwitterArchive reads the tweets of a Twitter archive.
+// Retweets and replies to others are skipped.
+func readTwitterArchive(fsys fs.FS) ([]archivePost, []types.ImportSkipped, error) {
+	var accounts []struct {
+		Account twitterAccount `json:"account"`
+	}
+	if err := readTwitterData(fsys, twitterAccountPath, &accounts); err != nil {
+		return nil, nil, err
+	}
+
+	var accountID string
+	if len(accounts) != 0 {
+		accountID = accounts[0].Account.AccountID
+	}
+
+	// big archives split tweets into several parts.
+	names, err := fs.Glob(fsys, "data/tweets-part*.js")
+	if err != nil {
+		return nil, nil, fmt.Errorf("glob twitter archive tweet parts: %w", err)
+	}
+
+	names = append([]string{twitterTweetsPath, twitterLegacyTweetsPath}, names...)
+
+	var posts []archivePost
+	var skipped []types.ImportSkipped
+	for _, name := range names {
+		if !archiveFileExists(fsys, name) {
+			continue
+		}
+
+		var items []struct {
+			Tweet twitterTweet `json:"tweet"`
+		}
+		if err := readTwitterData(fsys, name, &items); err != nil {
+			return nil, nil, err
+		}
+
+		for _, item := range items {
+			tweet := item.Tweet
+
+			if strings.HasPrefix(tweet.FullText, "RT @") {
+				skipped = append(skipped, types.ImportSkipped{
+					SourceID: tweet.ID,
+					Reason:   "retweets not supported",
+				})
+				continue
+			}
+
+			// replies to yourself are threads, those are kept.
+			if tweet.InReplyToUserID != "" && tweet.InReplyToUserID != accountID {
+				skipped = append(skipped, types.ImportSkipped{
+					SourceID: tweet.ID,
+					Reason:   "replies not supported",
+				})
+				continue
+			}
+
+			createdAt, err := time.Parse(time.RubyDate, tweet.CreatedAt)
+			if err != nil {
+				skipped = append(skipped, types.ImportSkipped{
+					SourceID: tweet.ID,
+					Reason:   "invalid creation date",
+				})
+				continue
+			}
+
+			p := archivePost{
+				SourceID:   tweet.ID,
+				Content:    twitterText(tweet),
+				NSFW:       tweet.PossiblySensitive,
+				Visibility: types.PostVisibilityPublic,
+				CreatedAt:  createdAt,
+			}
+
+			for _, m := range tweet.ExtendedEntities.Media {
+				p.MediaPaths = append(p.MediaPaths, twitterMediaPath(fsys, tweet.ID, m.MediaURLHTTPS))
+			}
+
+			posts = append(posts, p)
+		}
+	}
+
+	return posts, skipped, nil
+}
+
+// readTwitterData decodes a data file of the archive.
+// Those are JavaScript files that assign a JSON array to a global variable.
+// Missing files are left empty.
+func readTwitterData(fsys fs.FS, name string, v any) error {
+	b, err := fs.ReadFile(fsys, name)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+
+	if err != nil {
+		return fmt.Errorf("read twitter archive %s: %w", name, err)
+	}
+
+	_, b, ok := bytes.Cut(b, []byte("="))
+	if !ok {
+		return errs.InvalidArgumentError("invalid twitter archive")
+	}
+
+	if err := json.Unmarshal(b, v); err != nil {
+		return errs.InvalidArgumentError("invalid twitter archive")
+	}
+
+	return nil
+}
+
+// twitterText expands the shortened links of the tweet
+// and drops the ones pointing to its own media.
+func twitterText(tweet twitterTweet) string {
+	s := html.UnescapeString(tweet.FullText)
+
+	for _, u := range tweet.Entities.URLs {
+		s = strings.ReplaceAll(s, u.URL, u.ExpandedURL)
+	}
+
+	for _, m := range tweet.ExtendedEntities.Media {
+		s = strings.ReplaceAll(s, m.URL, "")
+	}
+
+	return s
+}
+
+// twitterMediaPath finds the file of a tweet media item in the archive.
+// Files are named after the tweet ID and the original file name.
+func twitterMediaPath(fsys fs.FS, tweetID, mediaURL string) string {
+	name := tweetID + "-" + path.Base(mediaURL)
+
+	// older archives use the singular.
+	legacy := path.Join("data", "tweet_media", name)
+	if archiveFileExists(fsys, legacy) {
+		return legacy
+	}
+
+	return path.Join("data", "tweets_media", name)
+}
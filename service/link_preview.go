@@ -0,0 +1,78 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/nakamauwu/nakama/types"
+	"github.com/nakamauwu/nakama/unfurl"
+)
+
+const (
+	// linkPreviewTTL before a cached URL gets unfurled again.
+	linkPreviewTTL     = time.Hour * 24
+	linkPreviewTimeout = time.Second * 15
+
+	linkPreviewTitleMaxLength       = 300
+	linkPreviewDescriptionMaxLength = 1000
+	linkPreviewSiteNameMaxLength    = 100
+)
+
+// unfurlLink caches the link preview of the given URL, if any,
+// unless it was unfurled recently.
+func (s *Service) unfurlLink(linkURL *string) {
+	if linkURL == nil || s.Unfurler == nil {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), linkPreviewTimeout)
+	defer cancel()
+
+	cached, err := s.Cockroach.LinkPreviewCached(ctx, *linkURL, linkPreviewTTL)
+	if err != nil {
+		_ = s.Logger.Log("error", err)
+		return
+	}
+
+	if cached {
+		return
+	}
+
+	// pages that can't be unfurled are cached with no metadata,
+	// so they aren't fetched again on every post that links them.
+	// Transient failures are not, so the next post linking it tries again.
+	meta, err := s.Unfurler.Unfurl(ctx, *linkURL)
+	if err != nil {
+		_ = s.Logger.Log("error", fmt.Errorf("could not unfurl link: %w", err))
+		if !unfurl.IsPermanent(err) {
+			return
+		}
+
+		meta = unfurl.Metadata{}
+	}
+
+	preview := types.LinkPreview{
+		URL:         *linkURL,
+		Title:       truncateRunes(meta.Title, linkPreviewTitleMaxLength),
+		Description: truncateRunes(meta.Description, linkPreviewDescriptionMaxLength),
+		SiteName:    truncateRunes(meta.SiteName, linkPreviewSiteNameMaxLength),
+	}
+	if meta.ImageURL != "" {
+		preview.ImageURL = &meta.ImageURL
+	}
+
+	if err := s.Cockroach.UpsertLinkPreview(ctx, preview); err != nil {
+		_ = s.Logger.Log("error", fmt.Errorf("could not cache link preview: %w", err))
+	}
+}
+
+func truncateRunes(s string, n int) string {
+	for i := range s {
+		if n == 0 {
+			return s[:i]
+		}
+		n--
+	}
+	return s
+}
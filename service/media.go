@@ -52,6 +52,14 @@ func (svc *Service) storeMedia(ctx context.Context, media []types.Media) (func(c
 	return svc.MinioStore.UploadMany(ctx, MediaBucket, uploadItems)
 }
 
+func (svc *Service) deleteMedia(media []types.Media) {
+	for _, m := range media {
+		if err := svc.MinioStore.Delete(context.Background(), MediaBucket, m.Path); err != nil {
+			_ = svc.Logger.Log("error", fmt.Errorf("could not delete media item %q: %w", m.Path, err))
+		}
+	}
+}
+
 func processMedia(readers []io.ReadSeeker) ([]types.Media, error) {
 	if len(readers) == 0 {
 		return nil, nil
@@ -0,0 +1,100 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/nakamauwu/nakama/types"
+	"github.com/nicolasparada/go-errs"
+)
+
+// Mute a user so their posts stop showing in your timeline and feed,
+// and they stop generating notifications for you.
+// They are not told about it.
+func (s *Service) Mute(ctx context.Context, in types.MuteUser) error {
+	if err := in.Validate(); err != nil {
+		return err
+	}
+
+	uid, ok := ctx.Value(KeyAuthUserID).(string)
+	if !ok {
+		return errs.Unauthenticated
+	}
+
+	mutedID, err := s.Cockroach.UserIDFromUsername(ctx, in.Username)
+	if err != nil {
+		return err
+	}
+
+	if uid == mutedID {
+		return errs.PermissionDeniedError("forbidden mute")
+	}
+
+	return s.Cockroach.Mute(ctx, uid, mutedID, in.ExpiresAt)
+}
+
+func (s *Service) Unmute(ctx context.Context, username string) error {
+	username = strings.TrimSpace(username)
+	if !types.ValidUsername(username) {
+		return errs.InvalidArgumentError("invalid username")
+	}
+
+	uid, ok := ctx.Value(KeyAuthUserID).(string)
+	if !ok {
+		return errs.Unauthenticated
+	}
+
+	mutedID, err := s.Cockroach.UserIDFromUsername(ctx, username)
+	if err != nil {
+		return err
+	}
+
+	return s.Cockroach.Unmute(ctx, uid, mutedID)
+}
+
+// Mutes lists the users muted by the authenticated user.
+func (s *Service) Mutes(ctx context.Context, in types.ListMutes) (types.Page[types.UserProfile], error) {
+	var out types.Page[types.UserProfile]
+
+	if err := in.Validate(); err != nil {
+		return out, err
+	}
+
+	uid, ok := ctx.Value(KeyAuthUserID).(string)
+	if !ok {
+		return out, errs.Unauthenticated
+	}
+
+	in.SetUserID(uid)
+
+	out, err := s.Cockroach.Mutes(ctx, in)
+	if err != nil {
+		return out, err
+	}
+
+	for i, u := range out.Items {
+		u.SetAvatarURL(s.ObjectsBaseURL, AvatarsBucket)
+		u.SetCoverURL(s.ObjectsBaseURL, CoversBucket)
+		u.Email = ""
+		out.Items[i] = u
+	}
+
+	return out, nil
+}
+
+// mutedInStream reports whether the post from the given user
+// should be skipped from the feed stream of the viewer because of a mute.
+func (s *Service) mutedInStream(ctx context.Context, viewerID, userID string) bool {
+	muted, err := s.Cockroach.MuteExists(ctx, viewerID, userID)
+	if err != nil {
+		_ = s.Logger.Log("error", fmt.Errorf("could not check mute in stream: %w", err))
+		return true
+	}
+
+	return muted
+}
+
+func (s *Service) deleteExpiredMutes(ctx context.Context) error {
+	return s.Cockroach.DeleteExpiredMutes(ctx)
+}
@@ -0,0 +1,221 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"github.com/nakamauwu/nakama/types"
+	"github.com/nicolasparada/go-errs"
+)
+
+func (s *Service) CreateMutedWord(ctx context.Context, in types.CreateMutedWord) (types.MutedWord, error) {
+	var out types.MutedWord
+
+	if err := in.Validate(); err != nil {
+		return out, err
+	}
+
+	uid, ok := ctx.Value(KeyAuthUserID).(string)
+	if !ok {
+		return out, errs.Unauthenticated
+	}
+
+	in.SetUserID(uid)
+
+	created, err := s.Cockroach.CreateMutedWord(ctx, in)
+	if err != nil {
+		return out, err
+	}
+
+	return types.MutedWord{
+		ID:        created.ID,
+		UserID:    uid,
+		Keyword:   in.Keyword,
+		Scope:     in.Scope,
+		Action:    in.Action,
+		CreatedAt: created.CreatedAt,
+	}, nil
+}
+
+// MutedWords from the authenticated user.
+func (s *Service) MutedWords(ctx context.Context) ([]types.MutedWord, error) {
+	uid, ok := ctx.Value(KeyAuthUserID).(string)
+	if !ok {
+		return nil, errs.Unauthenticated
+	}
+
+	return s.Cockroach.MutedWords(ctx, uid)
+}
+
+func (s *Service) DeleteMutedWord(ctx context.Context, mutedWordID string) error {
+	if !types.ValidUUIDv4(mutedWordID) {
+		return errs.InvalidArgumentError("invalid muted word ID")
+	}
+
+	uid, ok := ctx.Value(KeyAuthUserID).(string)
+	if !ok {
+		return errs.Unauthenticated
+	}
+
+	return s.Cockroach.DeleteMutedWord(ctx, uid, mutedWordID)
+}
+
+// mutedWordsIn returns the muted words of the given user that apply to the scope.
+func (s *Service) mutedWordsIn(ctx context.Context, userID string, scope types.MutedWordScope) ([]types.MutedWord, error) {
+	words, err := s.Cockroach.MutedWords(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return slices.DeleteFunc(words, func(w types.MutedWord) bool {
+		return !mutedWordApplies(w, scope)
+	}), nil
+}
+
+// maxMutedWordsFetches caps how many times a page that lost items
+// to hidden muted words gets topped up, so heavily muted lists stay cheap.
+// Pages can still come back short after that, but with the right cursors.
+const maxMutedWordsFetches = 5
+
+// fillPage fetches items until the page asked for is full with the ones hide lets through,
+// or there is nothing left. Each fetch asks only for the missing items,
+// so the cursors point at the last fetched ones, hidden or not,
+// and the next page continues right after them.
+func fillPage[T any](pageArgs types.PageArgs, fetch func(types.PageArgs) (types.Page[T], error), hide func(*T) bool) (types.Page[T], error) {
+	var out types.Page[T]
+
+	backwards := pageArgs.IsBackwards()
+	want := uint(types.DefaultPageSize)
+	if backwards && pageArgs.Last != nil {
+		want = *pageArgs.Last
+	} else if !backwards && pageArgs.First != nil {
+		want = *pageArgs.First
+	}
+
+	out.Items = make([]T, 0, want)
+	for i := range maxMutedWordsFetches {
+		page, err := fetch(pageArgs)
+		if err != nil {
+			return out, err
+		}
+
+		kept := make([]T, 0, len(page.Items))
+		for _, item := range page.Items {
+			if !hide(&item) {
+				kept = append(kept, item)
+			}
+		}
+
+		if i == 0 {
+			out.PageInfo = page.PageInfo
+		}
+
+		// items come in display order either way,
+		// backwards pages grow towards the start.
+		if backwards {
+			out.Items = append(kept, out.Items...)
+			if page.PageInfo.StartCursor != nil {
+				out.PageInfo.StartCursor = page.PageInfo.StartCursor
+			}
+			out.PageInfo.HasPreviousPage = page.PageInfo.HasPreviousPage
+		} else {
+			out.Items = append(out.Items, kept...)
+			if page.PageInfo.EndCursor != nil {
+				out.PageInfo.EndCursor = page.PageInfo.EndCursor
+			}
+			out.PageInfo.HasNextPage = page.PageInfo.HasNextPage
+		}
+
+		missing := want - uint(len(out.Items))
+		if missing == 0 {
+			break
+		}
+
+		if backwards {
+			if !page.PageInfo.HasPreviousPage || page.PageInfo.StartCursor == nil {
+				break
+			}
+
+			pageArgs = types.PageArgs{Last: &missing, Before: page.PageInfo.StartCursor}
+		} else {
+			if !page.PageInfo.HasNextPage || page.PageInfo.EndCursor == nil {
+				break
+			}
+
+			pageArgs = types.PageArgs{First: &missing, After: page.PageInfo.EndCursor}
+		}
+	}
+
+	return out, nil
+}
+
+// applyMutedWords marks the post as filtered when it matches a muted word
+// and reports whether it should be hidden instead.
+func applyMutedWords(p *types.Post, viewerID string, words []types.MutedWord) (hide bool) {
+	if p.UserID == viewerID {
+		return false
+	}
+
+	for _, w := range words {
+		if !matchMutedWord(*p, w) {
+			continue
+		}
+
+		if w.Action == types.MutedWordActionHide {
+			return true
+		}
+
+		p.Filtered = true
+	}
+
+	return false
+}
+
+func matchMutedWord(p types.Post, w types.MutedWord) bool {
+	if w.Match(p.Content) || (p.SpoilerOf != nil && w.Match(*p.SpoilerOf)) {
+		return true
+	}
+
+	return p.RepostOf != nil && matchMutedWord(*p.RepostOf, w)
+}
+
+func mutedWordApplies(w types.MutedWord, scope types.MutedWordScope) bool {
+	return w.Scope == scope || w.Scope == types.MutedWordScopeBoth
+}
+
+// mentionsNotMuting removes the mentioned usernames
+// that have a notifications muted word matching the given text,
+// so they don't get notified about it.
+func (s *Service) mentionsNotMuting(ctx context.Context, mentions []string, text string) []string {
+	words, err := s.Cockroach.MutedWordsByUsernames(ctx, mentions)
+	if err != nil {
+		_ = s.Logger.Log("error", fmt.Errorf("could not get muted words of mentioned users: %w", err))
+		return mentions
+	}
+
+	muting := map[string]struct{}{}
+	for _, w := range words {
+		if mutedWordApplies(w, types.MutedWordScopeNotifications) && w.Match(text) {
+			muting[w.Username] = struct{}{}
+		}
+	}
+
+	return slices.DeleteFunc(mentions, func(username string) bool {
+		_, ok := muting[username]
+		return ok
+	})
+}
+
+// mutedWordsInStream reports whether the post should be skipped
+// from a stream of the viewer because of a muted word,
+// marking it as filtered otherwise when needed.
+func (s *Service) mutedWordsInStream(ctx context.Context, viewerID string, p *types.Post) bool {
+	words, err := s.mutedWordsIn(ctx, viewerID, types.MutedWordScopeTimeline)
+	if err != nil {
+		_ = s.Logger.Log("error", fmt.Errorf("could not get muted words in stream: %w", err))
+		return false
+	}
+
+	return applyMutedWords(p, viewerID, words)
+}
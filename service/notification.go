@@ -6,6 +6,7 @@ import (
 	"encoding/gob"
 	"fmt"
 	"io"
+	"slices"
 
 	"github.com/nakamauwu/nakama/textutil"
 	"github.com/nakamauwu/nakama/types"
@@ -39,6 +40,10 @@ func (s *Service) Notifications(ctx context.Context, in types.ListNotifications)
 		}
 		if n.Post != nil {
 			n.Post.SetMediaPaths(s.ObjectsBaseURL, MediaBucket)
+			n.Post.RedactSpoilers()
+		}
+		if n.Comment != nil {
+			n.Comment.RedactSpoilers()
 		}
 		nn.Items[i] = n
 	}
@@ -117,10 +122,22 @@ func (s *Service) MarkNotificationsAsRead(ctx context.Context) error {
 }
 
 func (s *Service) notifyFollow(followerID, followeeID string) {
+	s.notifyUser(types.NotificationKindFollow, followeeID, followerID, s.Cockroach.CreateFollowNotification)
+}
+
+func (s *Service) notifyFollowRequest(followerID, followeeID string) {
+	s.notifyUser(types.NotificationKindFollowRequest, followeeID, followerID, s.Cockroach.CreateFollowRequestNotification)
+}
+
+func (s *Service) notifyFollowAccepted(followerID, followeeID string) {
+	s.notifyUser(types.NotificationKindFollowAccepted, followerID, followeeID, s.Cockroach.CreateFollowAcceptedNotification)
+}
+
+func (s *Service) notifyUser(kind types.NotificationKind, userID, actorUserID string, create func(ctx context.Context, userID, actorUserID string) (*string, error)) {
 	ctx := context.Background()
-	notificationID, err := s.Cockroach.CreateFollowNotification(ctx, followeeID, followerID)
+	notificationID, err := create(ctx, userID, actorUserID)
 	if err != nil {
-		_ = s.Logger.Log("error", fmt.Errorf("could not create follow notification: %w", err))
+		_ = s.Logger.Log("error", fmt.Errorf("could not create %q notification: %w", kind, err))
 		return
 	}
 
@@ -130,18 +147,19 @@ func (s *Service) notifyFollow(followerID, followeeID string) {
 
 	n, err := s.notification(ctx, *notificationID)
 	if err != nil {
-		_ = s.Logger.Log("error", fmt.Errorf("could not get follow notification: %w", err))
+		_ = s.Logger.Log("error", fmt.Errorf("could not get %q notification: %w", kind, err))
 		return
 	}
 
 	go s.broadcastNotification(n)
 }
 
-func (s *Service) notifyComment(c types.Comment) {
+func (s *Service) notifyComment(c types.Comment, replyToID *string) {
 	ctx := context.Background()
 	createdList, err := s.Cockroach.FanoutCommentNotification(ctx, types.FanoutCommentNotification{
 		ActorUserID: c.UserID,
 		PostID:      c.PostID,
+		ParentID:    replyToID,
 	})
 	if err != nil {
 		_ = s.Logger.Log("error", fmt.Errorf("could not fanout comment notification: %w", err))
@@ -159,9 +177,33 @@ func (s *Service) notifyComment(c types.Comment) {
 	}
 }
 
+func (s *Service) notifyCommentReply(c types.Comment, replyToID string) {
+	ctx := context.Background()
+	createdList, err := s.Cockroach.CreateCommentReplyNotification(ctx, types.CreateCommentReplyNotification{
+		ActorUserID: c.UserID,
+		PostID:      c.PostID,
+		CommentID:   c.ID,
+		ParentID:    replyToID,
+	})
+	if err != nil {
+		_ = s.Logger.Log("error", fmt.Errorf("could not create comment reply notification: %w", err))
+		return
+	}
+
+	notifications, err := s.notificationsByIDs(ctx, collectNotificationIDs(createdList))
+	if err != nil {
+		_ = s.Logger.Log("error", fmt.Errorf("could not get notifications by IDs: %w", err))
+		return
+	}
+
+	for _, n := range notifications {
+		go s.broadcastNotification(n)
+	}
+}
+
 func (s *Service) notifyPostMention(p types.Post) {
 	ctx := context.Background()
-	mentions := textutil.CollectMentions(p.Content)
+	mentions := s.mentionsNotMuting(ctx, textutil.CollectMentions(p.Content), p.Content)
 	createdList, err := s.Cockroach.CreateMentionNotifications(ctx, types.CreateMentionNotifications{
 		ActorUserID: p.UserID,
 		PostID:      p.ID,
@@ -186,7 +228,7 @@ func (s *Service) notifyPostMention(p types.Post) {
 
 func (s *Service) notifyCommentMention(c types.Comment) {
 	ctx := context.Background()
-	mentions := textutil.CollectMentions(c.Content)
+	mentions := s.mentionsNotMuting(ctx, textutil.CollectMentions(c.Content), c.Content)
 	createdList, err := s.Cockroach.CreateMentionNotifications(ctx, types.CreateMentionNotifications{
 		ActorUserID: c.UserID,
 		PostID:      c.PostID,
@@ -210,6 +252,70 @@ func (s *Service) notifyCommentMention(c types.Comment) {
 	}
 }
 
+// notifyMentionsUpdate diffs the mentions of an edited post or comment.
+// Newly mentioned users get notified,
+// while the unread notifications of users no longer mentioned get retracted.
+func (s *Service) notifyMentionsUpdate(kind types.NotificationKind, actorUserID, postID string, commentID *string, previousContent, content string) {
+	ctx := context.Background()
+	added, removed := diffMentions(textutil.CollectMentions(previousContent), textutil.CollectMentions(content))
+
+	err := s.Cockroach.DeleteMentionNotifications(ctx, types.DeleteMentionNotifications{
+		PostID:    postID,
+		CommentID: commentID,
+		Kind:      kind,
+		Mentions:  removed,
+	})
+	if err != nil {
+		_ = s.Logger.Log("error", fmt.Errorf("could not delete %q notifications: %w", kind, err))
+	}
+
+	createdList, err := s.Cockroach.CreateMentionNotifications(ctx, types.CreateMentionNotifications{
+		ActorUserID: actorUserID,
+		PostID:      postID,
+		CommentID:   commentID,
+		Kind:        kind,
+		Mentions:    s.mentionsNotMuting(ctx, added, content),
+	})
+	if err != nil {
+		_ = s.Logger.Log("error", fmt.Errorf("could not create %q notifications: %w", kind, err))
+		return
+	}
+
+	notifications, err := s.notificationsByIDs(ctx, collectNotificationIDs(createdList))
+	if err != nil {
+		_ = s.Logger.Log("error", fmt.Errorf("could not get notifications by IDs: %w", err))
+		return
+	}
+
+	for _, n := range notifications {
+		go s.broadcastNotification(n)
+	}
+}
+
+func (s *Service) notifyRepost(p types.Post) {
+	ctx := context.Background()
+	createdList, err := s.Cockroach.CreateRepostNotification(ctx, types.CreateRepostNotification{
+		ActorUserID: p.UserID,
+		PostID:      p.ID,
+		RepostOfID:  *p.RepostOfID,
+		Quote:       !p.IsPlainRepost(),
+	})
+	if err != nil {
+		_ = s.Logger.Log("error", fmt.Errorf("could not create repost notification: %w", err))
+		return
+	}
+
+	notifications, err := s.notificationsByIDs(ctx, collectNotificationIDs(createdList))
+	if err != nil {
+		_ = s.Logger.Log("error", fmt.Errorf("could not get notifications by IDs: %w", err))
+		return
+	}
+
+	for _, n := range notifications {
+		go s.broadcastNotification(n)
+	}
+}
+
 func (s *Service) notification(ctx context.Context, notificationID string) (types.Notification, error) {
 	n, err := s.Cockroach.Notification(ctx, notificationID)
 	if err != nil {
@@ -222,6 +328,10 @@ func (s *Service) notification(ctx context.Context, notificationID string) (type
 	}
 	if n.Post != nil {
 		n.Post.SetMediaPaths(s.ObjectsBaseURL, MediaBucket)
+		n.Post.RedactSpoilers()
+	}
+	if n.Comment != nil {
+		n.Comment.RedactSpoilers()
 	}
 
 	return n, nil
@@ -240,6 +350,10 @@ func (s *Service) notificationsByIDs(ctx context.Context, ids []string) ([]types
 		}
 		if n.Post != nil {
 			n.Post.SetMediaPaths(s.ObjectsBaseURL, MediaBucket)
+			n.Post.RedactSpoilers()
+		}
+		if n.Comment != nil {
+			n.Comment.RedactSpoilers()
 		}
 		nn[i] = n
 	}
@@ -264,6 +378,22 @@ func (s *Service) broadcastNotification(n types.Notification) {
 	go s.sendWebPushNotifications(n)
 }
 
+func diffMentions(previous, current []string) (added, removed []string) {
+	for _, mention := range current {
+		if !slices.Contains(previous, mention) {
+			added = append(added, mention)
+		}
+	}
+
+	for _, mention := range previous {
+		if !slices.Contains(current, mention) {
+			removed = append(removed, mention)
+		}
+	}
+
+	return added, removed
+}
+
 func collectNotificationIDs(notifications []types.CreatedNotification) []string {
 	ids := make([]string, len(notifications))
 	for i, n := range notifications {
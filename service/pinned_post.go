@@ -0,0 +1,55 @@
+package service
+
+import (
+	"context"
+
+	"github.com/nakamauwu/nakama/types"
+	"github.com/nicolasparada/go-errs"
+)
+
+// PinPost to the top of your profile.
+// Only your own posts can be pinned, up to [types.MaxPinnedPosts].
+func (s *Service) PinPost(ctx context.Context, postID string) error {
+	if !types.ValidUUIDv4(postID) {
+		return errs.InvalidArgumentError("invalid post ID")
+	}
+
+	if err := s.authorize(ctx, ResourceKindPost, postID); err != nil {
+		return err
+	}
+
+	uid, _ := ctx.Value(KeyAuthUserID).(string) // already authorized.
+
+	return s.Cockroach.PinPost(ctx, uid, postID)
+}
+
+func (s *Service) UnpinPost(ctx context.Context, postID string) error {
+	if !types.ValidUUIDv4(postID) {
+		return errs.InvalidArgumentError("invalid post ID")
+	}
+
+	uid, ok := ctx.Value(KeyAuthUserID).(string)
+	if !ok {
+		return errs.Unauthenticated
+	}
+
+	return s.Cockroach.UnpinPost(ctx, uid, postID)
+}
+
+func (s *Service) pinnedPosts(ctx context.Context, in types.ListPinnedPosts) ([]types.Post, error) {
+	if err := in.Validate(); err != nil {
+		return nil, err
+	}
+
+	posts, err := s.Cockroach.PinnedPosts(ctx, in)
+	if err != nil {
+		return nil, err
+	}
+
+	for i, p := range posts {
+		s.setPostURLs(&p)
+		posts[i] = p
+	}
+
+	return posts, nil
+}
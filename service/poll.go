@@ -0,0 +1,53 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/nakamauwu/nakama/types"
+	"github.com/nicolasparada/go-errs"
+)
+
+// VotePoll of the given post. Each user can vote only once.
+func (s *Service) VotePoll(ctx context.Context, in types.VotePoll) (types.Poll, error) {
+	var out types.Poll
+
+	if err := in.Validate(); err != nil {
+		return out, err
+	}
+
+	uid, ok := ctx.Value(KeyAuthUserID).(string)
+	if !ok {
+		return out, errs.Unauthenticated
+	}
+
+	if err := s.postVisible(ctx, in.PostID); err != nil {
+		return out, err
+	}
+
+	in.SetUserID(uid)
+
+	return s.Cockroach.VotePoll(ctx, in)
+}
+
+func (s *Service) closePolls(ctx context.Context) error {
+	createdList, err := s.Cockroach.ClosePolls(ctx)
+	if err != nil {
+		return err
+	}
+
+	if len(createdList) == 0 {
+		return nil
+	}
+
+	notifications, err := s.notificationsByIDs(ctx, collectNotificationIDs(createdList))
+	if err != nil {
+		return fmt.Errorf("could not get notifications by IDs: %w", err)
+	}
+
+	for _, n := range notifications {
+		go s.broadcastNotification(n)
+	}
+
+	return nil
+}
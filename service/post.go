@@ -40,6 +40,14 @@ func (s *Service) CreatePost(ctx context.Context, in types.CreatePost) (types.Ti
 	in.SetMedia(media)
 	in.SetUserID(uid)
 	in.SetTags(textutil.CollectTags(in.Content))
+	in.SetMentions(textutil.CollectMentions(in.Content))
+
+	entities, err := s.entities(ctx, in.Content)
+	if err != nil {
+		return out, err
+	}
+
+	in.SetEntities(entities)
 
 	cleanupMedia, err := s.storeMedia(ctx, media)
 	if err != nil {
@@ -56,29 +64,51 @@ func (s *Service) CreatePost(ctx context.Context, in types.CreatePost) (types.Ti
 		return out, err
 	}
 
+	post := s.createdPost(ctx, uid, in, createdTimelineItem)
+
+	go s.postCreated(post)
+
+	out.ID = createdTimelineItem.TimelineItemID
+	out.UserID = uid
+	out.Reason = types.TimelineItemReasonOwn
+	out.PostID = post.ID
+	out.Post = post
+
+	return out, nil
+
+}
+
+// createdPost builds the post that was just created by the given user
+// out of its creation input.
+func (s *Service) createdPost(ctx context.Context, uid string, in types.CreatePost, created types.CreatedTimelineItem) types.Post {
 	post := types.Post{
-		ID:         createdTimelineItem.PostID,
+		ID:         created.PostID,
 		UserID:     uid,
 		Content:    in.Content,
+		Entities:   in.Entities(),
 		SpoilerOf:  in.SpoilerOf,
 		NSFW:       in.NSFW,
-		Media:      media,
+		Media:      in.Media(),
+		RepostOfID: created.RepostOfID,
+		Visibility: in.Visibility,
+		Poll:       created.Poll,
 		Mine:       true,
 		Subscribed: true,
-		CreatedAt:  createdTimelineItem.CreatedAt,
-		UpdatedAt:  createdTimelineItem.CreatedAt,
+		CreatedAt:  created.CreatedAt,
+		UpdatedAt:  created.CreatedAt,
 	}
 	post.SetMediaPaths(s.ObjectsBaseURL, MediaBucket)
 
-	go s.postCreated(post)
-
-	out.ID = createdTimelineItem.TimelineItemID
-	out.UserID = uid
-	out.PostID = post.ID
-	out.Post = post
-
-	return out, nil
+	if post.RepostOfID != nil {
+		repostOf, err := s.Post(ctx, *post.RepostOfID)
+		if err != nil {
+			_ = s.Logger.Log("error", fmt.Errorf("could not fetch repost of post: %w", err))
+		} else {
+			post.RepostOf = &repostOf
+		}
+	}
 
+	return post
 }
 
 func (s *Service) Posts(ctx context.Context, in types.ListPosts) (types.Page[types.Post], error) {
@@ -88,21 +118,32 @@ func (s *Service) Posts(ctx context.Context, in types.ListPosts) (types.Page[typ
 		return out, err
 	}
 
-	if uid, ok := ctx.Value(KeyAuthUserID).(string); ok {
+	uid, auth := ctx.Value(KeyAuthUserID).(string)
+	if auth {
 		in.SetViewerID(uid)
 	}
 
-	out, err := s.Cockroach.Posts(ctx, in)
+	var words []types.MutedWord
+	if auth {
+		var err error
+		words, err = s.mutedWordsIn(ctx, uid, types.MutedWordScopeTimeline)
+		if err != nil {
+			return out, err
+		}
+	}
+
+	out, err := fillPage(in.PageArgs, func(pageArgs types.PageArgs) (types.Page[types.Post], error) {
+		in.PageArgs = pageArgs
+		return s.Cockroach.Posts(ctx, in)
+	}, func(p *types.Post) bool {
+		return applyMutedWords(p, uid, words)
+	})
 	if err != nil {
 		return out, err
 	}
 
-	for i, p := range out.Items {
-		if p.User != nil {
-			p.User.SetAvatarURL(s.ObjectsBaseURL, AvatarsBucket)
-		}
-		p.SetMediaPaths(s.ObjectsBaseURL, MediaBucket)
-		out.Items[i] = p
+	for i := range out.Items {
+		s.setPostURLs(&out.Items[i])
 	}
 
 	return out, nil
@@ -111,6 +152,7 @@ func (s *Service) Posts(ctx context.Context, in types.ListPosts) (types.Page[typ
 // PostStream to receive posts in realtime.
 func (s *Service) PostStream(ctx context.Context) (<-chan types.Post, error) {
 	pp := make(chan types.Post)
+	uid, auth := ctx.Value(KeyAuthUserID).(string)
 	unsub, err := s.PubSub.Sub(postsTopic, func(data []byte) {
 		go func(r io.Reader) {
 			var p types.Post
@@ -120,6 +162,14 @@ func (s *Service) PostStream(ctx context.Context) (<-chan types.Post, error) {
 				return
 			}
 
+			if p.Visibility != types.PostVisibilityPublic {
+				return
+			}
+
+			if auth && (s.blockedInStream(ctx, uid, p.UserID) || s.mutedInStream(ctx, uid, p.UserID) || s.mutedWordsInStream(ctx, uid, &p)) {
+				return
+			}
+
 			pp <- p
 		}(bytes.NewReader(data))
 	})
@@ -158,14 +208,50 @@ func (s *Service) Post(ctx context.Context, postID string) (types.Post, error) {
 		return out, err
 	}
 
-	if post.User != nil {
-		post.User.SetAvatarURL(s.ObjectsBaseURL, AvatarsBucket)
-	}
-	post.SetMediaPaths(s.ObjectsBaseURL, MediaBucket)
+	s.setPostURLs(&post)
 
 	return post, nil
 }
 
+// postVisible returns a not found error when the post does not exist
+// or the authenticated user, if any, is not allowed to see it.
+func (s *Service) postVisible(ctx context.Context, postID string) error {
+	var viewerID *string
+	if uid, ok := ctx.Value(KeyAuthUserID).(string); ok {
+		viewerID = &uid
+	}
+
+	return s.Cockroach.PostVisible(ctx, postID, viewerID)
+}
+
+// commentVisible is like [Service.postVisible] but for the post of the given comment.
+func (s *Service) commentVisible(ctx context.Context, commentID string) error {
+	postID, err := s.Cockroach.CommentPostID(ctx, commentID)
+	if err != nil {
+		return err
+	}
+
+	return s.postVisible(ctx, postID)
+}
+
+// setPostURLs turns the stored object paths of the post,
+// and the one it reposts, into full URLs.
+func (s *Service) setPostURLs(p *types.Post) {
+	if p.User != nil {
+		p.User.SetAvatarURL(s.ObjectsBaseURL, AvatarsBucket)
+	}
+	p.SetMediaPaths(s.ObjectsBaseURL, MediaBucket)
+	s.setReactionURLs(p.Reactions)
+
+	if p.LinkPreview != nil && p.LinkPreview.ImageURL != nil {
+		p.LinkPreview.ImageURL = new(s.proxyURL(*p.LinkPreview.ImageURL))
+	}
+
+	if p.RepostOf != nil {
+		s.setPostURLs(p.RepostOf)
+	}
+}
+
 func (s *Service) UpdatePost(ctx context.Context, in types.UpdatePost) (types.UpdatedPost, error) {
 	var out types.UpdatedPost
 
@@ -179,6 +265,14 @@ func (s *Service) UpdatePost(ctx context.Context, in types.UpdatePost) (types.Up
 
 	if in.Content != nil {
 		in.SetTags(textutil.CollectTags(*in.Content))
+		in.SetMentions(textutil.CollectMentions(*in.Content))
+
+		entities, err := s.entities(ctx, *in.Content)
+		if err != nil {
+			return out, err
+		}
+
+		in.SetEntities(entities)
 	}
 
 	postCreatedAt, err := s.Cockroach.PostCreatedAt(ctx, in.ID)
@@ -190,9 +284,33 @@ func (s *Service) UpdatePost(ctx context.Context, in types.UpdatePost) (types.Up
 		return out, errs.PermissionDeniedError("update post denied")
 	}
 
-	// TODO: recollect mentions
+	out, err = s.Cockroach.UpdatePost(ctx, in)
+	if err != nil {
+		return out, err
+	}
+
+	if in.Content != nil {
+		uid, _ := ctx.Value(KeyAuthUserID).(string) // already authorized.
+		go s.notifyMentionsUpdate(types.NotificationKindPostMention, uid, in.ID, nil, out.PreviousContent, out.Content)
+		go s.unfurlLink(types.LinkURL(out.Entities))
+	}
+
+	return out, nil
+}
+
+// PostRevisions lists the previous states of an edited post, latest first.
+func (s *Service) PostRevisions(ctx context.Context, in types.ListPostRevisions) (types.Page[types.PostRevision], error) {
+	var out types.Page[types.PostRevision]
+
+	if err := in.Validate(); err != nil {
+		return out, err
+	}
+
+	if err := s.postVisible(ctx, in.PostID); err != nil {
+		return out, err
+	}
 
-	return s.Cockroach.UpdatePost(ctx, in)
+	return s.Cockroach.PostRevisions(ctx, in)
 }
 
 func (s *Service) DeletePost(ctx context.Context, postID string) error {
@@ -217,9 +335,20 @@ func (s *Service) TogglePostReaction(ctx context.Context, in types.TogglePostRea
 		return nil, errs.Unauthenticated
 	}
 
+	if err := s.postVisible(ctx, in.PostID); err != nil {
+		return nil, err
+	}
+
 	in.SetUserID(uid)
 
-	return s.Cockroach.TogglePostReaction(ctx, in)
+	reactions, err := s.Cockroach.TogglePostReaction(ctx, in)
+	if err != nil {
+		return nil, err
+	}
+
+	s.setReactionURLs(reactions)
+
+	return reactions, nil
 }
 
 // TogglePostSubscription so you can stop receiving notifications from a thread.
@@ -235,6 +364,10 @@ func (s *Service) TogglePostSubscription(ctx context.Context, postID string) (ty
 		return out, errs.Unauthenticated
 	}
 
+	if err := s.postVisible(ctx, postID); err != nil {
+		return out, err
+	}
+
 	in := types.ToggleSubscription{PostID: postID}
 	in.SetUserID(uid)
 
@@ -269,13 +402,31 @@ func (s *Service) postCreated(p types.Post) {
 	p.Mine = false
 	p.Subscribed = false
 
-	go s.broadcastPost(p)
+	// the global stream is public.
+	if p.Visibility == types.PostVisibilityPublic {
+		private, err := s.Cockroach.UserPrivate(context.Background(), p.UserID)
+		if err != nil {
+			_ = s.Logger.Log("error", fmt.Errorf("could not check post user private: %w", err))
+		} else if !private {
+			go s.broadcastPost(p)
+		}
+	}
 	go s.fanoutPost(p)
 	go s.notifyPostMention(p)
+	go s.unfurlLink(types.LinkURL(p.Entities))
+
+	if p.RepostOfID != nil {
+		go s.notifyRepost(p)
+	}
 }
 
 func (s *Service) fanoutPost(p types.Post) {
-	timeline, err := s.Cockroach.FanoutTimeline(context.Background(), p.ID, p.UserID)
+	tags := textutil.CollectTags(p.Content)
+	for i, tag := range tags {
+		tags[i] = textutil.NormalizeTag(tag)
+	}
+
+	timeline, err := s.Cockroach.FanoutTimeline(context.Background(), p.ID, p.UserID, tags)
 	if err != nil {
 		_ = s.Logger.Log("error", err)
 		return
@@ -0,0 +1,54 @@
+package service
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/url"
+
+	"github.com/nicolasparada/go-errs"
+)
+
+const (
+	// ErrInvalidProxySignature denotes a proxy target URL that was not signed by the server.
+	ErrInvalidProxySignature = errs.PermissionDeniedError("invalid proxy signature")
+)
+
+// proxyURL routes the target URL through /api/proxy
+// so clients don't reach third parties directly.
+// It's signed when [Service.ProxyKey] is set.
+func (s *Service) proxyURL(target string) string {
+	q := url.Values{"target": {target}}
+	if len(s.ProxyKey) != 0 {
+		q.Set("sig", hex.EncodeToString(s.proxyMAC(target)))
+	}
+
+	u := s.Origin.JoinPath("/api/proxy")
+	u.RawQuery = q.Encode()
+	return u.String()
+}
+
+// VerifyProxyTarget checks the signature of a target URL given to /api/proxy.
+// Any target is allowed when [Service.ProxyKey] is not set.
+func (s *Service) VerifyProxyTarget(target, sig string) error {
+	if len(s.ProxyKey) == 0 {
+		return nil
+	}
+
+	got, err := hex.DecodeString(sig)
+	if err != nil {
+		return ErrInvalidProxySignature
+	}
+
+	if !hmac.Equal(got, s.proxyMAC(target)) {
+		return ErrInvalidProxySignature
+	}
+
+	return nil
+}
+
+func (s *Service) proxyMAC(target string) []byte {
+	mac := hmac.New(sha256.New, s.ProxyKey)
+	mac.Write([]byte(target))
+	return mac.Sum(nil)
+}
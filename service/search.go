@@ -0,0 +1,64 @@
+package service
+
+import (
+	"context"
+
+	"github.com/nakamauwu/nakama/types"
+)
+
+// Search posts, comments, users or tags.
+// Posts and comments are matched by their content, users by username
+// and tags by name.
+func (s *Service) Search(ctx context.Context, in types.Search) (types.Page[types.SearchResult], error) {
+	var out types.Page[types.SearchResult]
+
+	if err := in.Validate(); err != nil {
+		return out, err
+	}
+
+	uid, auth := ctx.Value(KeyAuthUserID).(string)
+	if auth {
+		in.SetViewerID(uid)
+	}
+
+	var words []types.MutedWord
+	if auth && in.Kind == types.SearchKindPost {
+		var err error
+		words, err = s.mutedWordsIn(ctx, uid, types.MutedWordScopeTimeline)
+		if err != nil {
+			return out, err
+		}
+	}
+
+	out, err := fillPage(in.PageArgs, func(pageArgs types.PageArgs) (types.Page[types.SearchResult], error) {
+		in.PageArgs = pageArgs
+		return s.Cockroach.Search(ctx, in)
+	}, func(r *types.SearchResult) bool {
+		return r.Post != nil && applyMutedWords(r.Post, uid, words)
+	})
+	if err != nil {
+		return out, err
+	}
+
+	for _, r := range out.Items {
+		switch {
+		case r.Post != nil:
+			s.setPostURLs(r.Post)
+		case r.Comment != nil:
+			s.setReactionURLs(r.Comment.Reactions)
+
+			if r.Comment.User != nil {
+				r.Comment.User.SetAvatarURL(s.ObjectsBaseURL, AvatarsBucket)
+			}
+		case r.User != nil:
+			r.User.SetAvatarURL(s.ObjectsBaseURL, AvatarsBucket)
+			r.User.SetCoverURL(s.ObjectsBaseURL, CoversBucket)
+
+			if !r.User.IsMe {
+				r.User.Email = ""
+			}
+		}
+	}
+
+	return out, nil
+}
@@ -3,6 +3,7 @@ package service
 import (
 	_ "embed"
 	"net/url"
+	"time"
 
 	"github.com/go-kit/log"
 
@@ -11,6 +12,7 @@ import (
 	"github.com/nakamauwu/nakama/mailing"
 	"github.com/nakamauwu/nakama/minio"
 	"github.com/nakamauwu/nakama/pubsub"
+	"github.com/nakamauwu/nakama/unfurl"
 )
 
 // Service contains the core business logic separated from the transport layer.
@@ -29,4 +31,14 @@ type Service struct {
 	AllowedOrigins   []string
 	VAPIDPrivateKey  string
 	VAPIDPublicKey   string
+	// Unfurler fetches link previews. They are skipped when nil.
+	Unfurler *unfurl.Unfurler
+	// AdminUserIDs are allowed to manage instance-wide resources like custom emojis.
+	AdminUserIDs []string
+	// ProxyKey signs the URLs the server routes through /api/proxy.
+	// When set, the proxy rejects targets without a valid signature.
+	ProxyKey []byte
+	// AccountDeletionGracePeriod before a deactivated account gets permanently deleted.
+	// Defaults to [DefaultAccountDeletionGracePeriod].
+	AccountDeletionGracePeriod time.Duration
 }
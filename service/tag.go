@@ -0,0 +1,35 @@
+package service
+
+import (
+	"context"
+
+	"github.com/nakamauwu/nakama/types"
+)
+
+// TagPage returns the tag details, related tags and the posts using it.
+func (s *Service) TagPage(ctx context.Context, in types.RetrieveTagPage) (types.TagPage, error) {
+	var out types.TagPage
+
+	if err := in.Validate(); err != nil {
+		return out, err
+	}
+
+	if uid, ok := ctx.Value(KeyAuthUserID).(string); ok {
+		in.SetViewerID(uid)
+	}
+
+	out, err := s.Cockroach.TagPage(ctx, in)
+	if err != nil {
+		return out, err
+	}
+
+	out.Posts, err = s.Posts(ctx, types.ListPosts{
+		Tag:      &in.Tag,
+		PageArgs: in.PageArgs,
+	})
+	if err != nil {
+		return out, err
+	}
+
+	return out, nil
+}
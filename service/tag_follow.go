@@ -0,0 +1,57 @@
+package service
+
+import (
+	"context"
+	"strings"
+
+	"github.com/nakamauwu/nakama/textutil"
+	"github.com/nakamauwu/nakama/types"
+	"github.com/nicolasparada/go-errs"
+)
+
+// FollowTag so new posts with the tag show up in your timeline.
+func (s *Service) FollowTag(ctx context.Context, tag string) error {
+	tag = strings.TrimPrefix(strings.TrimSpace(tag), "#")
+	if !types.ValidTag(tag) {
+		return errs.InvalidArgumentError("invalid tag")
+	}
+
+	uid, ok := ctx.Value(KeyAuthUserID).(string)
+	if !ok {
+		return errs.Unauthenticated
+	}
+
+	return s.Cockroach.FollowTag(ctx, uid, textutil.NormalizeTag(tag))
+}
+
+func (s *Service) UnfollowTag(ctx context.Context, tag string) error {
+	tag = strings.TrimPrefix(strings.TrimSpace(tag), "#")
+	if !types.ValidTag(tag) {
+		return errs.InvalidArgumentError("invalid tag")
+	}
+
+	uid, ok := ctx.Value(KeyAuthUserID).(string)
+	if !ok {
+		return errs.Unauthenticated
+	}
+
+	return s.Cockroach.UnfollowTag(ctx, uid, textutil.NormalizeTag(tag))
+}
+
+// TagFollows lists the tags followed by the authenticated user.
+func (s *Service) TagFollows(ctx context.Context, in types.ListTagFollows) (types.Page[types.TagFollow], error) {
+	var out types.Page[types.TagFollow]
+
+	if err := in.Validate(); err != nil {
+		return out, err
+	}
+
+	uid, ok := ctx.Value(KeyAuthUserID).(string)
+	if !ok {
+		return out, errs.Unauthenticated
+	}
+
+	in.SetUserID(uid)
+
+	return s.Cockroach.TagFollows(ctx, in)
+}
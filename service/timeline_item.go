@@ -25,17 +25,23 @@ func (s *Service) Timeline(ctx context.Context, in types.ListTimeline) (types.Pa
 
 	in.SetUserID(uid)
 
-	out, err := s.Cockroach.Timeline(ctx, in)
+	words, err := s.mutedWordsIn(ctx, uid, types.MutedWordScopeTimeline)
 	if err != nil {
 		return out, err
 	}
 
-	for i, ti := range out.Items {
-		if ti.Post.User != nil {
-			ti.Post.User.SetAvatarURL(s.ObjectsBaseURL, AvatarsBucket)
-		}
-		ti.Post.SetMediaPaths(s.ObjectsBaseURL, MediaBucket)
-		out.Items[i] = ti
+	out, err = fillPage(in.PageArgs, func(pageArgs types.PageArgs) (types.Page[types.TimelineItem], error) {
+		in.PageArgs = pageArgs
+		return s.Cockroach.Timeline(ctx, in)
+	}, func(ti *types.TimelineItem) bool {
+		return applyMutedWords(&ti.Post, uid, words)
+	})
+	if err != nil {
+		return out, err
+	}
+
+	for i := range out.Items {
+		s.setPostURLs(&out.Items[i].Post)
 	}
 
 	return out, nil
@@ -57,6 +63,10 @@ func (s *Service) TimelineItemStream(ctx context.Context) (<-chan types.Timeline
 				return
 			}
 
+			if s.mutedWordsInStream(ctx, uid, &ti.Post) {
+				return
+			}
+
 			tt <- ti
 		}(bytes.NewReader(data))
 	})
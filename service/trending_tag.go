@@ -0,0 +1,27 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/nakamauwu/nakama/types"
+)
+
+// TrendingTags returns the tags whose usage grew the most within the window.
+func (s *Service) TrendingTags(ctx context.Context, in types.ListTrendingTags) ([]types.TrendingTag, error) {
+	if err := in.Validate(); err != nil {
+		return nil, err
+	}
+
+	return s.Cockroach.TrendingTags(ctx, in)
+}
+
+func (s *Service) refreshTrendingTags(ctx context.Context) error {
+	for _, window := range types.TrendingWindows {
+		if err := s.Cockroach.RefreshTrendingTags(ctx, window); err != nil {
+			return fmt.Errorf("refresh %s trending tags: %w", window, err)
+		}
+	}
+
+	return nil
+}
@@ -238,6 +238,16 @@ func (s *Service) UserProfileByUsername(ctx context.Context, in types.RetrieveUs
 		user.Email = ""
 	}
 
+	pinned := types.ListPinnedPosts{UserID: user.ID}
+	if in.ViewerID() != nil {
+		pinned.SetViewerID(*in.ViewerID())
+	}
+
+	user.PinnedPosts, err = s.pinnedPosts(ctx, pinned)
+	if err != nil {
+		return user, err
+	}
+
 	return user, nil
 }
 
@@ -422,6 +432,8 @@ func (s *Service) UpdateCover(ctx context.Context, r io.ReadSeeker) (string, err
 	return s.objectStoreURL(CoversBucket, coverFileName), nil
 }
 
+// ToggleFollow follows or unfollows the given user.
+// Private accounts get a follow request instead.
 func (s *Service) ToggleFollow(ctx context.Context, username string) (types.ToggledFollow, error) {
 	var out types.ToggledFollow
 
@@ -444,6 +456,10 @@ func (s *Service) ToggleFollow(ctx context.Context, username string) (types.Togg
 		return out, errs.PermissionDeniedError("forbidden follow")
 	}
 
+	if err := s.checkNotBlocked(ctx, followerID, followeeID); err != nil {
+		return out, err
+	}
+
 	out, err = s.Cockroach.ToggleFollow(ctx, followerID, followeeID)
 	if err != nil {
 		return out, err
@@ -453,6 +469,10 @@ func (s *Service) ToggleFollow(ctx context.Context, username string) (types.Togg
 		go s.notifyFollow(followerID, followeeID)
 	}
 
+	if out.FollowRequested {
+		go s.notifyFollowRequest(followerID, followeeID)
+	}
+
 	return out, nil
 }
 
@@ -0,0 +1,140 @@
+// Package ssrf guards outgoing requests to user provided URLs
+// so they can't be used to reach internal services.
+package ssrf
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/netip"
+	"syscall"
+	"time"
+)
+
+var ErrNonPublicAddress = errors.New("ssrf: non public address")
+
+// nonPublicPrefixes not covered by the [netip.Addr] methods.
+var nonPublicPrefixes = []netip.Prefix{
+	netip.MustParsePrefix("0.0.0.0/8"),       // "this" network.
+	netip.MustParsePrefix("100.64.0.0/10"),   // carrier-grade NAT.
+	netip.MustParsePrefix("192.0.0.0/24"),    // IETF protocol assignments.
+	netip.MustParsePrefix("198.18.0.0/15"),   // benchmarking.
+	netip.MustParsePrefix("240.0.0.0/4"),     // reserved.
+	netip.MustParsePrefix("64:ff9b::/96"),    // NAT64, embeds IPv4 addresses.
+	netip.MustParsePrefix("64:ff9b:1::/48"),  // local-use NAT64.
+	netip.MustParsePrefix("2002::/16"),       // 6to4, embeds IPv4 addresses.
+	netip.MustParsePrefix("2001::/32"),       // Teredo, embeds IPv4 addresses.
+	netip.MustParsePrefix("fec0::/10"),       // deprecated site-local.
+	netip.MustParsePrefix("100::/64"),        // discard-only.
+	netip.MustParsePrefix("2001:db8::/32"),   // documentation.
+	netip.MustParsePrefix("192.0.2.0/24"),    // documentation.
+	netip.MustParsePrefix("198.51.100.0/24"), // documentation.
+	netip.MustParsePrefix("203.0.113.0/24"),  // documentation.
+}
+
+// IsPublicAddr reports whether addr is routable on the public internet.
+// Private, loopback, link-local, multicast and reserved addresses are not.
+func IsPublicAddr(addr netip.Addr) bool {
+	addr = addr.Unmap()
+	if !addr.IsValid() ||
+		addr.IsUnspecified() ||
+		addr.IsLoopback() ||
+		addr.IsPrivate() ||
+		addr.IsLinkLocalUnicast() ||
+		addr.IsLinkLocalMulticast() ||
+		addr.IsInterfaceLocalMulticast() ||
+		addr.IsMulticast() {
+		return false
+	}
+
+	for _, prefix := range nonPublicPrefixes {
+		if prefix.Contains(addr) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Dialer resolves hostnames itself and only connects to the resolved addresses
+// when all of them are public. Connecting to the very addresses that were checked
+// pins the DNS answer, so a second lookup can't point somewhere else.
+type Dialer struct {
+	Resolver *net.Resolver
+	Dialer   *net.Dialer
+}
+
+func (d *Dialer) DialContext(ctx context.Context, network, address string) (net.Conn, error) {
+	host, port, err := net.SplitHostPort(address)
+	if err != nil {
+		return nil, err
+	}
+
+	resolver := d.Resolver
+	if resolver == nil {
+		resolver = net.DefaultResolver
+	}
+
+	addrs, err := resolver.LookupNetIP(ctx, "ip", host)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, addr := range addrs {
+		if !IsPublicAddr(addr) {
+			return nil, fmt.Errorf("%w: %s resolves to %s", ErrNonPublicAddress, host, addr)
+		}
+	}
+
+	dialer := d.Dialer
+	if dialer == nil {
+		dialer = &net.Dialer{}
+	}
+
+	// also check right before connecting, should the resolver be bypassed.
+	pinned := *dialer
+	pinned.Control = control
+
+	var errs []error
+	for _, addr := range addrs {
+		conn, err := pinned.DialContext(ctx, network, net.JoinHostPort(addr.Unmap().String(), port))
+		if err == nil {
+			return conn, nil
+		}
+
+		errs = append(errs, err)
+	}
+
+	return nil, errors.Join(errs...)
+}
+
+func control(_, address string, _ syscall.RawConn) error {
+	addrPort, err := netip.ParseAddrPort(address)
+	if err != nil {
+		return err
+	}
+
+	if !IsPublicAddr(addrPort.Addr()) {
+		return fmt.Errorf("%w: %s", ErrNonPublicAddress, addrPort.Addr())
+	}
+
+	return nil
+}
+
+// NewTransport returns an HTTP transport that dials with a [Dialer]
+// and ignores proxy environment variables.
+func NewTransport() *http.Transport {
+	dialer := &Dialer{
+		Dialer: &net.Dialer{
+			Timeout:   time.Second * 5,
+			KeepAlive: time.Second * 30,
+		},
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = nil
+	transport.DialContext = dialer.DialContext
+	return transport
+}
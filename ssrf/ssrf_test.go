@@ -0,0 +1,56 @@
+package ssrf_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"testing"
+
+	"github.com/nakamauwu/nakama/ssrf"
+)
+
+func TestIsPublicAddr(t *testing.T) {
+	tt := []struct {
+		given string
+		want  bool
+	}{
+		{given: "1.1.1.1", want: true},
+		{given: "2606:4700:4700::1111", want: true},
+		{given: "127.0.0.1", want: false},
+		{given: "::1", want: false},
+		{given: "0.0.0.0", want: false},
+		{given: "10.0.0.1", want: false},
+		{given: "172.16.0.1", want: false},
+		{given: "192.168.1.1", want: false},
+		{given: "169.254.169.254", want: false},
+		{given: "100.64.0.1", want: false},
+		{given: "fc00::1", want: false},
+		{given: "fe80::1", want: false},
+		{given: "::ffff:127.0.0.1", want: false},
+		{given: "::ffff:169.254.169.254", want: false},
+		{given: "64:ff9b::a9fe:a9fe", want: false},
+		{given: "224.0.0.1", want: false},
+	}
+	for _, tc := range tt {
+		t.Run(tc.given, func(t *testing.T) {
+			got := ssrf.IsPublicAddr(netip.MustParseAddr(tc.given))
+			if got != tc.want {
+				t.Errorf("IsPublicAddr(%s) = %v, want %v", tc.given, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestNewTransport(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Error("non public address reached")
+	}))
+	defer srv.Close()
+
+	client := &http.Client{Transport: ssrf.NewTransport()}
+	_, err := client.Get(srv.URL)
+	if !errors.Is(err, ssrf.ErrNonPublicAddress) {
+		t.Errorf("Get() error = %v, want %v", err, ssrf.ErrNonPublicAddress)
+	}
+}
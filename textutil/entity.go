@@ -0,0 +1,144 @@
+package textutil
+
+import (
+	"regexp"
+	"slices"
+	"strings"
+	"unicode/utf16"
+)
+
+var (
+	reURLs     = regexp.MustCompile(`\bhttps?://[^\s<>"|]+`)
+	reSpoilers = regexp.MustCompile(`(?s)\|\|(.+?)\|\|`)
+)
+
+// EntityKind tells what an [Entity] stands for.
+type EntityKind string
+
+const (
+	EntityKindMention EntityKind = "mention"
+	EntityKindHashtag EntityKind = "hashtag"
+	EntityKindURL     EntityKind = "url"
+	EntityKindSpoiler EntityKind = "spoiler"
+)
+
+// Entity is a span of text with a special meaning.
+// Offsets include markup like the "@" of a mention or the "||" around a spoiler.
+type Entity struct {
+	Kind EntityKind `json:"kind"`
+	// Value is the username, the tag, the URL or the spoiler text, without markup.
+	Value string `json:"value"`
+	// Start and End are byte offsets.
+	Start int `json:"start"`
+	End   int `json:"end"`
+	// UTF16Start and UTF16End are offsets in UTF-16 code units,
+	// which is how JavaScript indexes strings.
+	UTF16Start int `json:"utf16Start"`
+	UTF16End   int `json:"utf16End"`
+}
+
+// Entities finds the mentions, hashtags, URLs and spoilers in s, sorted by offset.
+// Mentions and hashtags that are part of an URL are left out.
+// Spoilers may contain other entities.
+func Entities(s string) []Entity {
+	var out []Entity
+
+	for _, loc := range reURLs.FindAllStringIndex(s, -1) {
+		start, end := loc[0], loc[0]+len(trimURL(s[loc[0]:loc[1]]))
+		out = append(out, Entity{Kind: EntityKindURL, Value: s[start:end], Start: start, End: end})
+	}
+
+	urls := len(out)
+	withinURL := func(start, end int) bool {
+		return slices.ContainsFunc(out[:urls], func(e Entity) bool {
+			return start < e.End && end > e.Start
+		})
+	}
+
+	for _, loc := range reMentions.FindAllStringSubmatchIndex(s, -1) {
+		start, end := loc[2]-len("@"), loc[3]
+		if !withinURL(start, end) {
+			out = append(out, Entity{Kind: EntityKindMention, Value: s[loc[2]:loc[3]], Start: start, End: end})
+		}
+	}
+
+	for _, loc := range reTags.FindAllStringSubmatchIndex(s, -1) {
+		start, end := loc[2]-len("#"), loc[3]
+		if !withinURL(start, end) {
+			out = append(out, Entity{Kind: EntityKindHashtag, Value: s[loc[2]:loc[3]], Start: start, End: end})
+		}
+	}
+
+	for _, loc := range reSpoilers.FindAllStringSubmatchIndex(s, -1) {
+		out = append(out, Entity{Kind: EntityKindSpoiler, Value: s[loc[2]:loc[3]], Start: loc[0], End: loc[1]})
+	}
+
+	slices.SortStableFunc(out, func(a, b Entity) int {
+		if a.Start != b.Start {
+			return a.Start - b.Start
+		}
+		// enclosing entities first.
+		return b.End - a.End
+	})
+
+	for i := range out {
+		out[i].UTF16Start = utf16Len(s[:out[i].Start])
+		out[i].UTF16End = out[i].UTF16Start + utf16Len(s[out[i].Start:out[i].End])
+	}
+
+	return out
+}
+
+// trimURL removes the trailing punctuation that more likely belongs
+// to the surrounding sentence than to the URL.
+func trimURL(u string) string {
+	for u != "" {
+		switch last := u[len(u)-1]; last {
+		case '.', ',', ':', ';', '!', '?', '\'', '"':
+			u = u[:len(u)-1]
+			continue
+		case ')':
+			if strings.Count(u, "(") < strings.Count(u, ")") {
+				u = u[:len(u)-1]
+				continue
+			}
+		case ']':
+			if strings.Count(u, "[") < strings.Count(u, "]") {
+				u = u[:len(u)-1]
+				continue
+			}
+		}
+
+		return u
+	}
+
+	return u
+}
+
+// SpoilerPlaceholder takes the place of spoilers in redacted text.
+const SpoilerPlaceholder = "[spoiler]"
+
+// ValidSpoilers reports whether every "||" in s opens or closes a spoiler
+// and none of them is blank.
+func ValidSpoilers(s string) bool {
+	for _, submatch := range reSpoilers.FindAllStringSubmatch(s, -1) {
+		if strings.TrimSpace(submatch[1]) == "" {
+			return false
+		}
+	}
+
+	return !strings.Contains(reSpoilers.ReplaceAllLiteralString(s, ""), "||")
+}
+
+// RedactSpoilers replaces each spoiler in s with [SpoilerPlaceholder].
+func RedactSpoilers(s string) string {
+	return reSpoilers.ReplaceAllLiteralString(s, SpoilerPlaceholder)
+}
+
+func utf16Len(s string) int {
+	var n int
+	for _, r := range s {
+		n += utf16.RuneLen(r)
+	}
+	return n
+}
@@ -0,0 +1,156 @@
+package textutil_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/nakamauwu/nakama/textutil"
+)
+
+func TestEntities(t *testing.T) {
+	tt := []struct {
+		name  string
+		given string
+		want  []textutil.Entity
+	}{
+		{
+			given: "nope",
+			want:  nil,
+		},
+		{
+			given: "hi @shinji",
+			want: []textutil.Entity{
+				{Kind: textutil.EntityKindMention, Value: "shinji", Start: 3, End: 10, UTF16Start: 3, UTF16End: 10},
+			},
+		},
+		{
+			given: "#tag",
+			want: []textutil.Entity{
+				{Kind: textutil.EntityKindHashtag, Value: "tag", Start: 0, End: 4, UTF16Start: 0, UTF16End: 4},
+			},
+		},
+		{
+			given: "see https://example.org.",
+			want: []textutil.Entity{
+				{Kind: textutil.EntityKindURL, Value: "https://example.org", Start: 4, End: 23, UTF16Start: 4, UTF16End: 23},
+			},
+		},
+		{
+			given: "(https://en.wikipedia.org/wiki/Eva_(anime))",
+			want: []textutil.Entity{
+				{Kind: textutil.EntityKindURL, Value: "https://en.wikipedia.org/wiki/Eva_(anime)", Start: 1, End: 42, UTF16Start: 1, UTF16End: 42},
+			},
+		},
+		{
+			given: "https://example.org/@shinji#nope",
+			want: []textutil.Entity{
+				{Kind: textutil.EntityKindURL, Value: "https://example.org/@shinji#nope", Start: 0, End: 32, UTF16Start: 0, UTF16End: 32},
+			},
+		},
+		{
+			given: "||Gojo dies #jjk||",
+			want: []textutil.Entity{
+				{Kind: textutil.EntityKindSpoiler, Value: "Gojo dies #jjk", Start: 0, End: 18, UTF16Start: 0, UTF16End: 18},
+				{Kind: textutil.EntityKindHashtag, Value: "jjk", Start: 12, End: 16, UTF16Start: 12, UTF16End: 16},
+			},
+		},
+		{
+			given: "café @rei",
+			want: []textutil.Entity{
+				{Kind: textutil.EntityKindMention, Value: "rei", Start: 6, End: 10, UTF16Start: 5, UTF16End: 9},
+			},
+		},
+		{
+			given: "😂 #世界",
+			want: []textutil.Entity{
+				{Kind: textutil.EntityKindHashtag, Value: "世界", Start: 5, End: 12, UTF16Start: 3, UTF16End: 6},
+			},
+		},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			got := textutil.Entities(tc.given)
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("Entities(%q) = %+v, want %+v", tc.given, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestValidSpoilers(t *testing.T) {
+	tt := []struct {
+		name  string
+		given string
+		want  bool
+	}{
+		{
+			given: "nope",
+			want:  true,
+		},
+		{
+			given: "||Gojo dies||",
+			want:  true,
+		},
+		{
+			given: "||Gojo|| and ||Nanami|| die",
+			want:  true,
+		},
+		{
+			given: "||Gojo dies",
+			want:  false,
+		},
+		{
+			given: "||Gojo|| dies||",
+			want:  false,
+		},
+		{
+			given: "||||",
+			want:  false,
+		},
+		{
+			given: "|| ||",
+			want:  false,
+		},
+		{
+			given: "a | b",
+			want:  true,
+		},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			got := textutil.ValidSpoilers(tc.given)
+			if got != tc.want {
+				t.Errorf("ValidSpoilers(%q) = %v, want %v", tc.given, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestRedactSpoilers(t *testing.T) {
+	tt := []struct {
+		name  string
+		given string
+		want  string
+	}{
+		{
+			given: "nope",
+			want:  "nope",
+		},
+		{
+			given: "turns out ||Gojo dies||!",
+			want:  "turns out [spoiler]!",
+		},
+		{
+			given: "||Gojo||\n||Nanami||",
+			want:  "[spoiler]\n[spoiler]",
+		},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			got := textutil.RedactSpoilers(tc.given)
+			if got != tc.want {
+				t.Errorf("RedactSpoilers(%q) = %q, want %q", tc.given, got, tc.want)
+			}
+		})
+	}
+}
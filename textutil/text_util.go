@@ -3,13 +3,18 @@ package textutil
 import (
 	"regexp"
 	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"golang.org/x/text/cases"
+	"golang.org/x/text/unicode/norm"
 )
 
 var (
 	reMultiSpace          = regexp.MustCompile(`(\s)+`)
 	reMoreThan2Linebreaks = regexp.MustCompile(`(\n){2,}`)
 	reMentions            = regexp.MustCompile(`\B@([a-zA-Z][a-zA-Z0-9_-]{0,17})(?:\b[^@]|$)`)
-	reTags                = regexp.MustCompile(`\B#((?:\p{L}|\p{N}|_)+)(?:\b[^#]|$)`)
+	reTags                = regexp.MustCompile(`\B#((?:\p{L}|\p{M}|\p{N}|_)+)(?:[^\p{L}\p{M}\p{N}_#]|$)`)
 )
 
 func SmartTrim(s string) string {
@@ -24,28 +29,115 @@ func SmartTrim(s string) string {
 	return strings.TrimSpace(s)
 }
 
+// CollectMentions returns the usernames mentioned in s.
 func CollectMentions(s string) []string {
 	mentions := map[string]struct{}{}
 	var unique []string
-	for _, submatch := range reMentions.FindAllStringSubmatch(s, -1) {
-		mention := submatch[1]
-		if _, ok := mentions[mention]; !ok {
-			mentions[mention] = struct{}{}
-			unique = append(unique, mention)
+	for _, e := range Entities(s) {
+		if e.Kind != EntityKindMention {
+			continue
+		}
+
+		if _, ok := mentions[e.Value]; !ok {
+			mentions[e.Value] = struct{}{}
+			unique = append(unique, e.Value)
 		}
 	}
 	return unique
 }
 
+// CollectTags returns the tags in s as written.
+// Tags that only differ once normalized are collected once, in their first form.
 func CollectTags(s string) []string {
 	tags := map[string]struct{}{}
 	var unique []string
-	for _, submatch := range reTags.FindAllStringSubmatch(s, -1) {
-		tag := submatch[1]
-		if _, ok := tags[tag]; !ok {
-			tags[tag] = struct{}{}
-			unique = append(unique, tag)
+	for _, e := range Entities(s) {
+		if e.Kind != EntityKindHashtag {
+			continue
+		}
+
+		normalized := NormalizeTag(e.Value)
+		if _, ok := tags[normalized]; !ok {
+			tags[normalized] = struct{}{}
+			unique = append(unique, e.Value)
 		}
 	}
 	return unique
 }
+
+// NormalizeTag folds case and composes the Unicode form of the tag,
+// so the different ways of writing the same tag compare equal.
+func NormalizeTag(tag string) string {
+	return norm.NFC.String(cases.Fold().String(norm.NFC.String(tag)))
+}
+
+// NormalizeKeyword folds case and collapses whitespace
+// so keywords and text can be compared against each other.
+func NormalizeKeyword(s string) string {
+	return strings.ToLower(reMultiSpace.ReplaceAllString(strings.TrimSpace(s), " "))
+}
+
+// MatchKeyword reports whether the text contains the keyword as whole words.
+// Keywords starting with "#" only match hashtags.
+func MatchKeyword(text, keyword string) bool {
+	keyword = NormalizeKeyword(keyword)
+	if tag, ok := strings.CutPrefix(keyword, "#"); ok {
+		if tag == "" {
+			return false
+		}
+
+		tag = NormalizeTag(tag)
+		for _, t := range CollectTags(text) {
+			if NormalizeTag(t) == tag {
+				return true
+			}
+		}
+
+		return false
+	}
+
+	if keyword == "" {
+		return false
+	}
+
+	text = NormalizeKeyword(text)
+	for offset := 0; offset < len(text); {
+		i := strings.Index(text[offset:], keyword)
+		if i == -1 {
+			return false
+		}
+
+		start := offset + i
+		end := start + len(keyword)
+		if isWordBoundary(text, start, end) {
+			return true
+		}
+
+		_, size := utf8.DecodeRuneInString(text[start:])
+		offset = start + size
+	}
+
+	return false
+}
+
+func isWordBoundary(s string, start, end int) bool {
+	if start > 0 {
+		r, _ := utf8.DecodeLastRuneInString(s[:start])
+		if isWordRune(r) {
+			return false
+		}
+	}
+
+	if end < len(s) {
+		r, _ := utf8.DecodeRuneInString(s[end:])
+		if isWordRune(r) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func isWordRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsNumber(r) || r == '_'
+}
@@ -61,6 +61,22 @@ func TestCollectTags(t *testing.T) {
 			given: "#repeated #repeated #repeated",
 			want:  []string{"repeated"},
 		},
+		{
+			given: "#Naruto #naruto #NARUTO",
+			want:  []string{"Naruto"},
+		},
+		{
+			given: "#café #cafe\u0301",
+			want:  []string{"café"},
+		},
+		{
+			given: "#cafe\u0301 bar",
+			want:  []string{"cafe\u0301"},
+		},
+		{
+			given: "#café, #tág.",
+			want:  []string{"café", "tág"},
+		},
 	}
 	for _, tc := range tt {
 		t.Run(tc.name, func(t *testing.T) {
@@ -71,3 +87,122 @@ func TestCollectTags(t *testing.T) {
 		})
 	}
 }
+
+func TestNormalizeTag(t *testing.T) {
+	tt := []struct {
+		name  string
+		given string
+		want  string
+	}{
+		{
+			given: "naruto",
+			want:  "naruto",
+		},
+		{
+			given: "Naruto",
+			want:  "naruto",
+		},
+		{
+			given: "cafe\u0301",
+			want:  "café",
+		},
+		{
+			given: "CAFE\u0301",
+			want:  "café",
+		},
+		{
+			given: "Straße",
+			want:  "strasse",
+		},
+		{
+			given: "世界",
+			want:  "世界",
+		},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			got := textutil.NormalizeTag(tc.given)
+			if got != tc.want {
+				t.Errorf("NormalizeTag(%q) = %q, want %q", tc.given, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestMatchKeyword(t *testing.T) {
+	tt := []struct {
+		name    string
+		text    string
+		keyword string
+		want    bool
+	}{
+		{
+			text:    "spoilers ahead",
+			keyword: "spoilers",
+			want:    true,
+		},
+		{
+			text:    "SPOILERS ahead",
+			keyword: "Spoilers",
+			want:    true,
+		},
+		{
+			text:    "no spoilers\n  ahead",
+			keyword: "spoilers ahead",
+			want:    true,
+		},
+		{
+			text:    "spoilerse",
+			keyword: "spoilers",
+			want:    false,
+		},
+		{
+			text:    "spoilerse spoilers",
+			keyword: "spoilers",
+			want:    true,
+		},
+		{
+			text:    "¡café!",
+			keyword: "café",
+			want:    true,
+		},
+		{
+			text:    "foo #Tag bar",
+			keyword: "#tag",
+			want:    true,
+		},
+		{
+			text:    "foo tag bar",
+			keyword: "#tag",
+			want:    false,
+		},
+		{
+			text:    "foo #Cafe\u0301 bar",
+			keyword: "#café",
+			want:    true,
+		},
+		{
+			text:    "foo #tags bar",
+			keyword: "#tag",
+			want:    false,
+		},
+		{
+			text:    "anything",
+			keyword: "  ",
+			want:    false,
+		},
+		{
+			text:    "anything",
+			keyword: "#",
+			want:    false,
+		},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			got := textutil.MatchKeyword(tc.text, tc.keyword)
+			if got != tc.want {
+				t.Errorf("MatchKeyword(%q, %q) = %v, want %v", tc.text, tc.keyword, got, tc.want)
+			}
+		})
+	}
+}
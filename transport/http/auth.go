@@ -7,10 +7,12 @@ import (
 	"encoding/base32"
 	"encoding/gob"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
 	"net/url"
 
+	"github.com/alexedwards/scs/v2"
 	"github.com/nakamauwu/nakama/service"
 	"github.com/nakamauwu/nakama/types"
 	"github.com/nicolasparada/go-errs"
@@ -68,8 +70,7 @@ func (h *handler) verifyLogin(w http.ResponseWriter, r *http.Request) {
 
 	switch resp.Status {
 	case types.LoginResultSuccess:
-		h.sess.Put(ctx, sessKeyUserID, resp.User.ID)
-		if err := h.sess.RenewToken(ctx); err != nil {
+		if err := h.login(ctx, resp.User.ID); err != nil {
 			h.respondErr(w, err)
 			return
 		}
@@ -153,12 +154,11 @@ func (h *handler) oauthCallback(w http.ResponseWriter, r *http.Request) {
 
 	switch resp.Status {
 	case types.LoginResultSuccess:
-		h.sess.Put(ctx, sessKeyUserID, resp.User.ID)
-		if err := h.sess.RenewToken(ctx); err != nil {
-			h.logger.Log("msg", "renew session token", "err", err)
+		if err := h.login(ctx, resp.User.ID); err != nil {
+			h.logger.Log("msg", "login", "err", err)
 			redirectWithHashFragment(w, r, redirectURI, url.Values{
 				"result": []string{"error"},
-				"error":  []string{fmt.Sprintf("renew session token: %v", err)},
+				"error":  []string{err.Error()},
 			}, http.StatusFound)
 			return
 		}
@@ -208,10 +208,9 @@ func (h *handler) completeSignup(w http.ResponseWriter, r *http.Request) {
 
 	h.sess.Remove(ctx, sessKeyPendingSignup)
 
-	h.sess.Put(ctx, sessKeyUserID, user.ID)
-	if err := h.sess.RenewToken(ctx); err != nil {
-		h.logger.Log("msg", "renew session token", "err", err)
-		h.respondErr(w, fmt.Errorf("renew session token: %w", err))
+	if err := h.login(ctx, user.ID); err != nil {
+		h.logger.Log("msg", "login", "err", err)
+		h.respondErr(w, err)
 		return
 	}
 
@@ -236,8 +235,7 @@ func (h *handler) devLogin(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	h.sess.Put(ctx, sessKeyUserID, user.ID)
-	if err := h.sess.RenewToken(ctx); err != nil {
+	if err := h.login(ctx, user.ID); err != nil {
 		h.respondErr(w, err)
 		return
 	}
@@ -265,6 +263,55 @@ func (h *handler) logout(w http.ResponseWriter, r *http.Request) {
 	w.WriteHeader(http.StatusNoContent)
 }
 
+// login puts the user in a renewed session,
+// and lets the service know about it.
+func (h *handler) login(ctx context.Context, userID string) error {
+	h.sess.Put(ctx, sessKeyUserID, userID)
+	if err := h.sess.RenewToken(ctx); err != nil {
+		return fmt.Errorf("renew session token: %w", err)
+	}
+
+	return h.svc.StartSession(ctx, userID, h.sess.Token(ctx))
+}
+
+// TrackSessions records the sessions of every logged in user,
+// so those started before sessions were tracked can be revoked too.
+// It's safe to run more than once.
+func TrackSessions(ctx context.Context, svc *service.Service, sessStore scs.Store) error {
+	sess := scs.New()
+	sess.Store = sessStore
+
+	return sess.Iterate(ctx, func(ctx context.Context) error {
+		userID := sess.GetString(ctx, sessKeyUserID)
+		if userID == "" {
+			return nil // not logged in.
+		}
+
+		err := svc.TrackSession(ctx, userID, sess.Token(ctx))
+		if errors.Is(err, errs.InvalidArgument) {
+			return nil
+		}
+
+		return err
+	})
+}
+
+func (h *handler) deleteAccount(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	out, err := h.svc.RequestAccountDeletion(ctx)
+	if err != nil {
+		h.respondErr(w, err)
+		return
+	}
+
+	if err := h.sess.Destroy(ctx); err != nil {
+		h.respondErr(w, err)
+		return
+	}
+
+	h.respond(w, out, http.StatusAccepted)
+}
+
 func (h *handler) withAuth(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		ctx := r.Context()
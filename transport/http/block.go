@@ -0,0 +1,56 @@
+package http
+
+import (
+	"net/http"
+
+	"github.com/nakamauwu/nakama/types"
+)
+
+func (h *handler) block(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	username := r.PathValue("username")
+	err := h.svc.Block(ctx, username)
+	if err != nil {
+		h.respondErr(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *handler) unblock(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	username := r.PathValue("username")
+	err := h.svc.Unblock(ctx, username)
+	if err != nil {
+		h.respondErr(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *handler) blocks(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	q := r.URL.Query()
+	pageArgs, err := parsePageArgs(q)
+	if err != nil {
+		h.respondErr(w, err)
+		return
+	}
+
+	in := types.ListBlocks{
+		PageArgs: pageArgs,
+	}
+	out, err := h.svc.Blocks(ctx, in)
+	if err != nil {
+		h.respondErr(w, err)
+		return
+	}
+
+	if out.Items == nil {
+		out.Items = []types.UserProfile{} // non null array
+	}
+
+	h.respond(w, out, http.StatusOK)
+}
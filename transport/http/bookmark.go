@@ -0,0 +1,48 @@
+package http
+
+import (
+	"net/http"
+
+	"github.com/nakamauwu/nakama/types"
+)
+
+func (h *handler) toggleBookmark(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	in := types.ToggleBookmark{PostID: r.PathValue("postID")}
+	out, err := h.svc.ToggleBookmark(ctx, in)
+	if err != nil {
+		h.respondErr(w, err)
+		return
+	}
+
+	h.respond(w, out, http.StatusOK)
+}
+
+func (h *handler) bookmarks(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	q := r.URL.Query()
+	pageArgs, err := parsePageArgs(q)
+	if err != nil {
+		h.respondErr(w, err)
+		return
+	}
+
+	in := types.ListBookmarks{
+		PageArgs: pageArgs,
+	}
+	page, err := h.svc.Bookmarks(ctx, in)
+	if err != nil {
+		h.respondErr(w, err)
+		return
+	}
+
+	if page.Items == nil {
+		page.Items = []types.Bookmark{} // non null array
+	}
+
+	for i := range page.Items {
+		nonNullPostArrays(&page.Items[i].Post)
+	}
+
+	h.respond(w, page, http.StatusOK)
+}
@@ -69,6 +69,38 @@ func (h *handler) comments(w http.ResponseWriter, r *http.Request) {
 	h.respond(w, page, http.StatusOK)
 }
 
+func (h *handler) commentReplies(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	q := r.URL.Query()
+	pageArgs, err := parsePageArgs(q)
+	if err != nil {
+		h.respondErr(w, err)
+		return
+	}
+
+	in := types.ListCommentReplies{
+		CommentID: r.PathValue("commentID"),
+		PageArgs:  pageArgs,
+	}
+	page, err := h.svc.CommentReplies(ctx, in)
+	if err != nil {
+		h.respondErr(w, err)
+		return
+	}
+
+	if page.Items == nil {
+		page.Items = []types.Comment{} // non null array
+	}
+
+	for i := range page.Items {
+		if page.Items[i].Reactions == nil {
+			page.Items[i].Reactions = []types.Reaction{} // non null array
+		}
+	}
+
+	h.respond(w, page, http.StatusOK)
+}
+
 func (h *handler) commentStream(w http.ResponseWriter, r *http.Request) {
 	f, ok := w.(http.Flusher)
 	if !ok {
@@ -122,6 +154,32 @@ func (h *handler) updateComment(w http.ResponseWriter, r *http.Request) {
 	h.respond(w, out, http.StatusOK)
 }
 
+func (h *handler) commentRevisions(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	q := r.URL.Query()
+	pageArgs, err := parsePageArgs(q)
+	if err != nil {
+		h.respondErr(w, err)
+		return
+	}
+
+	in := types.ListCommentRevisions{
+		CommentID: r.PathValue("commentID"),
+		PageArgs:  pageArgs,
+	}
+	page, err := h.svc.CommentRevisions(ctx, in)
+	if err != nil {
+		h.respondErr(w, err)
+		return
+	}
+
+	if page.Items == nil {
+		page.Items = []types.CommentRevision{} // non null array
+	}
+
+	h.respond(w, page, http.StatusOK)
+}
+
 func (h *handler) deleteComment(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 	commentID := r.PathValue("commentID")
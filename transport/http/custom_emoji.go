@@ -0,0 +1,56 @@
+package http
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+
+	"github.com/nakamauwu/nakama/service"
+	"github.com/nakamauwu/nakama/types"
+)
+
+func (h *handler) createCustomEmoji(w http.ResponseWriter, r *http.Request) {
+	defer r.Body.Close()
+
+	b, err := io.ReadAll(http.MaxBytesReader(w, r.Body, service.MaxCustomEmojiBytes))
+	if err != nil {
+		h.respondErr(w, errBadRequest)
+		return
+	}
+
+	in := types.CreateCustomEmoji{
+		Shortcode:   r.PathValue("shortcode"),
+		ImageReader: bytes.NewReader(b),
+	}
+	out, err := h.svc.CreateCustomEmoji(r.Context(), in)
+	if err != nil {
+		h.respondErr(w, err)
+		return
+	}
+
+	h.respond(w, out, http.StatusCreated)
+}
+
+func (h *handler) customEmojis(w http.ResponseWriter, r *http.Request) {
+	out, err := h.svc.CustomEmojis(r.Context())
+	if err != nil {
+		h.respondErr(w, err)
+		return
+	}
+
+	if out == nil {
+		out = []types.CustomEmoji{} // non null array
+	}
+
+	h.respond(w, out, http.StatusOK)
+}
+
+func (h *handler) deleteCustomEmoji(w http.ResponseWriter, r *http.Request) {
+	err := h.svc.DeleteCustomEmoji(r.Context(), r.PathValue("shortcode"))
+	if err != nil {
+		h.respondErr(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,15 @@
+package http
+
+import (
+	"net/http"
+)
+
+func (h *handler) requestDataExport(w http.ResponseWriter, r *http.Request) {
+	out, err := h.svc.RequestDataExport(r.Context())
+	if err != nil {
+		h.respondErr(w, err)
+		return
+	}
+
+	h.respond(w, out, http.StatusAccepted)
+}
@@ -0,0 +1,191 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-kit/log/level"
+	"github.com/nakamauwu/nakama/service"
+	"github.com/nakamauwu/nakama/types"
+)
+
+func (h *handler) createDraft(w http.ResponseWriter, r *http.Request) {
+	defer r.Body.Close()
+
+	var in types.CreateDraft
+
+	var closeFuncs []func() error
+
+	defer func() {
+		for _, f := range closeFuncs {
+			_ = f()
+		}
+	}()
+
+	if strings.Contains(strings.ToLower(r.Header.Get("Content-Type")), "multipart/form-data") {
+		if err := r.ParseMultipartForm(service.MaxMediaItemBytes); err != nil {
+			level.Warn(h.logger).Log("msg", "failed to parse multipart form", "err", err)
+			h.respondErr(w, errBadRequest)
+			return
+		}
+
+		defer r.MultipartForm.RemoveAll()
+
+		in.Content = r.FormValue("content")
+		if s := strings.TrimSpace(r.FormValue("spoiler_of")); s != "" {
+			in.SpoilerOf = &s
+		}
+		if v, err := strconv.ParseBool(r.FormValue("nsfw")); err == nil {
+			in.NSFW = v
+		}
+		in.Visibility = types.PostVisibility(r.FormValue("visibility"))
+		if s := strings.TrimSpace(r.FormValue("publish_at")); s != "" {
+			publishAt, err := time.Parse(time.RFC3339, s)
+			if err != nil {
+				h.respondErr(w, errBadRequest)
+				return
+			}
+
+			in.PublishAt = &publishAt
+		}
+		if s := strings.TrimSpace(r.FormValue("poll")); s != "" {
+			in.Poll = &types.CreatePoll{} // only to be rejected
+		}
+		if files, ok := r.MultipartForm.File["media"]; ok {
+			for _, header := range files {
+				if header.Size > service.MaxMediaItemBytes {
+					h.respondErr(w, service.ErrMediaItemTooLarge)
+					return
+				}
+
+				f, err := header.Open()
+				if err != nil {
+					level.Warn(h.logger).Log("msg", "failed to open media file", "err", err)
+					h.respondErr(w, errBadRequest)
+					return
+				}
+
+				closeFuncs = append(closeFuncs, f.Close)
+
+				in.MediaReaders = append(in.MediaReaders, f)
+			}
+		}
+	} else {
+		if err := json.NewDecoder(r.Body).Decode(&in); err != nil {
+			h.respondErr(w, errBadRequest)
+			return
+		}
+	}
+
+	out, err := h.svc.CreateDraft(r.Context(), in)
+	if err != nil {
+		h.respondErr(w, err)
+		return
+	}
+
+	if out.Media == nil {
+		out.Media = []types.Media{} // non null array
+	}
+
+	h.respond(w, out, http.StatusCreated)
+}
+
+func (h *handler) drafts(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	q := r.URL.Query()
+	pageArgs, err := parsePageArgs(q)
+	if err != nil {
+		h.respondErr(w, err)
+		return
+	}
+
+	in := types.ListDrafts{
+		PageArgs: pageArgs,
+	}
+	page, err := h.svc.Drafts(ctx, in)
+	if err != nil {
+		h.respondErr(w, err)
+		return
+	}
+
+	if page.Items == nil {
+		page.Items = []types.Draft{} // non null array
+	}
+
+	for i := range page.Items {
+		if page.Items[i].Media == nil {
+			page.Items[i].Media = []types.Media{} // non null array
+		}
+	}
+
+	h.respond(w, page, http.StatusOK)
+}
+
+func (h *handler) draft(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	draftID := r.PathValue("draftID")
+	out, err := h.svc.Draft(ctx, draftID)
+	if err != nil {
+		h.respondErr(w, err)
+		return
+	}
+
+	if out.Media == nil {
+		out.Media = []types.Media{} // non null array
+	}
+
+	h.respond(w, out, http.StatusOK)
+}
+
+func (h *handler) updateDraft(w http.ResponseWriter, r *http.Request) {
+	defer r.Body.Close()
+
+	var in types.UpdateDraft
+	if err := json.NewDecoder(r.Body).Decode(&in); err != nil {
+		h.respondErr(w, errBadRequest)
+		return
+	}
+
+	ctx := r.Context()
+	in.ID = r.PathValue("draftID")
+	out, err := h.svc.UpdateDraft(ctx, in)
+	if err != nil {
+		h.respondErr(w, err)
+		return
+	}
+
+	if out.Media == nil {
+		out.Media = []types.Media{} // non null array
+	}
+
+	h.respond(w, out, http.StatusOK)
+}
+
+func (h *handler) deleteDraft(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	draftID := r.PathValue("draftID")
+	err := h.svc.DeleteDraft(ctx, draftID)
+	if err != nil {
+		h.respondErr(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *handler) publishDraft(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	draftID := r.PathValue("draftID")
+	ti, err := h.svc.PublishDraft(ctx, draftID)
+	if err != nil {
+		h.respondErr(w, err)
+		return
+	}
+
+	nonNullPostArrays(&ti.Post)
+
+	h.respond(w, ti, http.StatusCreated)
+}
@@ -0,0 +1,25 @@
+package http
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+)
+
+const embedCacheControl = time.Hour * 24
+
+func (h *handler) embed(w http.ResponseWriter, r *http.Request) {
+	if !h.allowProxyRequest(w, r) {
+		return
+	}
+
+	out, err := h.svc.Embed(r.Context(), r.URL.Query().Get("url"))
+	if err != nil {
+		h.respondErr(w, err)
+		return
+	}
+
+	// embeds only depend on the URL.
+	w.Header().Set("Cache-Control", fmt.Sprintf("max-age=%d, public", int64(embedCacheControl.Seconds())))
+	h.respond(w, out, http.StatusOK)
+}
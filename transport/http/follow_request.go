@@ -0,0 +1,56 @@
+package http
+
+import (
+	"net/http"
+
+	"github.com/nakamauwu/nakama/types"
+)
+
+func (h *handler) followRequests(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	q := r.URL.Query()
+	pageArgs, err := parsePageArgs(q)
+	if err != nil {
+		h.respondErr(w, err)
+		return
+	}
+
+	in := types.ListFollowRequests{
+		PageArgs: pageArgs,
+	}
+	out, err := h.svc.FollowRequests(ctx, in)
+	if err != nil {
+		h.respondErr(w, err)
+		return
+	}
+
+	if out.Items == nil {
+		out.Items = []types.UserProfile{} // non null array
+	}
+
+	h.respond(w, out, http.StatusOK)
+}
+
+func (h *handler) approveFollowRequest(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	username := r.PathValue("username")
+	err := h.svc.ApproveFollowRequest(ctx, username)
+	if err != nil {
+		h.respondErr(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *handler) rejectFollowRequest(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	username := r.PathValue("username")
+	err := h.svc.RejectFollowRequest(ctx, username)
+	if err != nil {
+		h.respondErr(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -17,10 +17,16 @@ type handler struct {
 	origin           *url.URL
 	logger           log.Logger
 	embedStaticFiles bool
+	proxyTransport   http.RoundTripper
+	proxyLimiter     *rateLimiter
+	// clientIPHeader is set by a trusted reverse proxy with the client IP address.
+	clientIPHeader string
 }
 
 // New makes use of the service to provide an http.Handler with predefined routing.
-func New(svc *service.Service, sessStore scs.Store, origin *url.URL, logger log.Logger, promHandler http.Handler, embedStaticFiles bool) http.Handler {
+// Set clientIPHeader only when every request goes through a reverse proxy
+// that sets it, as clients could spoof it otherwise.
+func New(svc *service.Service, sessStore scs.Store, origin *url.URL, logger log.Logger, promHandler http.Handler, embedStaticFiles bool, clientIPHeader string) http.Handler {
 	sess := scs.New()
 	sess.Store = sessStore
 	sess.Lifetime = time.Hour * 24 * 7 * 2 // 2 weeks
@@ -33,6 +39,9 @@ func New(svc *service.Service, sessStore scs.Store, origin *url.URL, logger log.
 		origin:           origin,
 		logger:           logger,
 		embedStaticFiles: embedStaticFiles,
+		proxyTransport:   newProxyTransport(),
+		proxyLimiter:     newRateLimiter(proxyRateLimitEvery, proxyRateLimitBurst),
+		clientIPHeader:   clientIPHeader,
 	}
 
 	api := http.NewServeMux()
@@ -51,37 +60,74 @@ func New(svc *service.Service, sessStore scs.Store, origin *url.URL, logger log.
 	api.HandleFunc("GET /api/usernames", h.usernames)
 	api.HandleFunc("GET /api/users/{username}", h.userProfileByUsername)
 	api.HandleFunc("PATCH /api/user", h.updateUser)
+	api.HandleFunc("DELETE /api/user", h.deleteAccount)
+	api.HandleFunc("POST /api/user/data_export", h.requestDataExport)
 	api.HandleFunc("PUT /api/user/avatar", h.updateAvatar)
 	api.HandleFunc("PUT /api/user/cover", h.updateCover)
 	api.HandleFunc("POST /api/user/email/request", h.requestEmailUpdate)
 	api.HandleFunc("PATCH /api/user/email/verify", h.verifyEmailUpdate)
 	api.HandleFunc("POST /api/users/{username}/toggle_follow", h.toggleFollow)
+	api.HandleFunc("POST /api/users/{username}/block", h.block)
+	api.HandleFunc("DELETE /api/users/{username}/block", h.unblock)
+	api.HandleFunc("GET /api/blocks", h.blocks)
+	api.HandleFunc("POST /api/users/{username}/mute", h.mute)
+	api.HandleFunc("DELETE /api/users/{username}/mute", h.unmute)
+	api.HandleFunc("GET /api/mutes", h.mutes)
+	api.HandleFunc("POST /api/muted_words", h.createMutedWord)
+	api.HandleFunc("GET /api/muted_words", h.mutedWords)
+	api.HandleFunc("DELETE /api/muted_words/{mutedWordID}", h.deleteMutedWord)
 	api.HandleFunc("GET /api/users/{username}/followers", h.followers)
 	api.HandleFunc("GET /api/users/{username}/followees", h.followees)
+	api.HandleFunc("GET /api/follow_requests", h.followRequests)
+	api.HandleFunc("POST /api/follow_requests/{username}/approve", h.approveFollowRequest)
+	api.HandleFunc("POST /api/follow_requests/{username}/reject", h.rejectFollowRequest)
 	api.HandleFunc("GET /api/users/{username}/posts", h.posts)
+	api.HandleFunc("GET /api/search", h.search)
+	api.HandleFunc("GET /api/tags/trending", h.trendingTags)
+	api.HandleFunc("GET /api/tags/{tag}", h.tagPage)
+	api.HandleFunc("POST /api/tags/{tag}/follow", h.followTag)
+	api.HandleFunc("DELETE /api/tags/{tag}/follow", h.unfollowTag)
+	api.HandleFunc("GET /api/tag_follows", h.tagFollows)
 	api.HandleFunc("GET /api/posts", h.posts)
 	api.HandleFunc("GET /api/posts/{postID}", h.post)
 	api.HandleFunc("PATCH /api/posts/{postID}", h.updatePost)
 	api.HandleFunc("DELETE /api/posts/{postID}", h.deletePost)
+	api.HandleFunc("GET /api/posts/{postID}/revisions", h.postRevisions)
 	api.HandleFunc("POST /api/posts/{postID}/toggle_reaction", h.togglePostReaction)
 	api.HandleFunc("POST /api/posts/{postID}/toggle_subscription", h.togglePostSubscription)
+	api.HandleFunc("POST /api/posts/{postID}/toggle_bookmark", h.toggleBookmark)
+	api.HandleFunc("POST /api/posts/{postID}/pin", h.pinPost)
+	api.HandleFunc("DELETE /api/posts/{postID}/pin", h.unpinPost)
+	api.HandleFunc("POST /api/posts/{postID}/poll", h.votePoll)
 	api.HandleFunc("POST /api/timeline", h.createPost)
 	api.HandleFunc("GET /api/timeline", h.timeline)
 	api.HandleFunc("DELETE /api/timeline/{timelineItemID}", h.deleteTimelineItem)
+	api.HandleFunc("GET /api/bookmarks", h.bookmarks)
+	api.HandleFunc("POST /api/drafts", h.createDraft)
+	api.HandleFunc("GET /api/drafts", h.drafts)
+	api.HandleFunc("GET /api/drafts/{draftID}", h.draft)
+	api.HandleFunc("PATCH /api/drafts/{draftID}", h.updateDraft)
+	api.HandleFunc("DELETE /api/drafts/{draftID}", h.deleteDraft)
+	api.HandleFunc("POST /api/drafts/{draftID}/publish", h.publishDraft)
 	api.HandleFunc("POST /api/posts/{postID}/comments", h.createComment)
 	api.HandleFunc("GET /api/posts/{postID}/comments", h.comments)
 	api.HandleFunc("PATCH /api/comments/{commentID}", h.updateComment)
 	api.HandleFunc("DELETE /api/comments/{commentID}", h.deleteComment)
+	api.HandleFunc("GET /api/comments/{commentID}/replies", h.commentReplies)
+	api.HandleFunc("GET /api/comments/{commentID}/revisions", h.commentRevisions)
 	api.HandleFunc("POST /api/comments/{commentID}/toggle_reaction", h.toggleCommentReaction)
 	api.HandleFunc("GET /api/notifications", h.notifications)
 	api.HandleFunc("GET /api/has_unread_notifications", h.hasUnreadNotifications)
 	api.HandleFunc("POST /api/notifications/{notificationID}/mark_as_read", h.markNotificationAsRead)
 	api.HandleFunc("POST /api/mark_notifications_as_read", h.markNotificationsAsRead)
 	api.HandleFunc("POST /api/web_push_subscriptions", h.addWebPushSubscription)
+	api.HandleFunc("GET /api/custom_emojis", h.customEmojis)
+	api.HandleFunc("PUT /api/custom_emojis/{shortcode}", h.createCustomEmoji)
+	api.HandleFunc("DELETE /api/custom_emojis/{shortcode}", h.deleteCustomEmoji)
 
-	proxy := withCacheControl(proxyCacheControl)(h.proxy)
-	api.HandleFunc("HEAD /api/proxy", proxy)
-	api.HandleFunc("GET /api/proxy", proxy)
+	api.HandleFunc("HEAD /api/proxy", h.proxy)
+	api.HandleFunc("GET /api/proxy", h.proxy)
+	api.HandleFunc("GET /api/embed", h.embed)
 
 	api.HandleFunc("POST /api/logs", h.pushLog)
 	api.Handle("GET /api/prom", promHandler)
@@ -0,0 +1,67 @@
+package http
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+
+	"github.com/nakamauwu/nakama/types"
+)
+
+func (h *handler) mute(w http.ResponseWriter, r *http.Request) {
+	defer r.Body.Close()
+
+	var in types.MuteUser
+	// body is optional, mutes are indefinite by default.
+	if err := json.NewDecoder(r.Body).Decode(&in); err != nil && err != io.EOF {
+		h.respondErr(w, errBadRequest)
+		return
+	}
+
+	ctx := r.Context()
+	in.Username = r.PathValue("username")
+	err := h.svc.Mute(ctx, in)
+	if err != nil {
+		h.respondErr(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *handler) unmute(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	username := r.PathValue("username")
+	err := h.svc.Unmute(ctx, username)
+	if err != nil {
+		h.respondErr(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *handler) mutes(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	q := r.URL.Query()
+	pageArgs, err := parsePageArgs(q)
+	if err != nil {
+		h.respondErr(w, err)
+		return
+	}
+
+	in := types.ListMutes{
+		PageArgs: pageArgs,
+	}
+	out, err := h.svc.Mutes(ctx, in)
+	if err != nil {
+		h.respondErr(w, err)
+		return
+	}
+
+	if out.Items == nil {
+		out.Items = []types.UserProfile{} // non null array
+	}
+
+	h.respond(w, out, http.StatusOK)
+}
@@ -0,0 +1,52 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/nakamauwu/nakama/types"
+)
+
+func (h *handler) createMutedWord(w http.ResponseWriter, r *http.Request) {
+	defer r.Body.Close()
+
+	var in types.CreateMutedWord
+	if err := json.NewDecoder(r.Body).Decode(&in); err != nil {
+		h.respondErr(w, errBadRequest)
+		return
+	}
+
+	out, err := h.svc.CreateMutedWord(r.Context(), in)
+	if err != nil {
+		h.respondErr(w, err)
+		return
+	}
+
+	h.respond(w, out, http.StatusCreated)
+}
+
+func (h *handler) mutedWords(w http.ResponseWriter, r *http.Request) {
+	out, err := h.svc.MutedWords(r.Context())
+	if err != nil {
+		h.respondErr(w, err)
+		return
+	}
+
+	if out == nil {
+		out = []types.MutedWord{} // non null array
+	}
+
+	h.respond(w, out, http.StatusOK)
+}
+
+func (h *handler) deleteMutedWord(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	mutedWordID := r.PathValue("mutedWordID")
+	err := h.svc.DeleteMutedWord(ctx, mutedWordID)
+	if err != nil {
+		h.respondErr(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
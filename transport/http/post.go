@@ -35,12 +35,23 @@ func (h *handler) createPost(w http.ResponseWriter, r *http.Request) {
 		defer r.MultipartForm.RemoveAll()
 
 		in.Content = r.FormValue("content")
+		if s := strings.TrimSpace(r.FormValue("repost_of_id")); s != "" {
+			in.RepostOfID = &s
+		}
 		if s := strings.TrimSpace(r.FormValue("spoiler_of")); s != "" {
 			in.SpoilerOf = &s
 		}
 		if v, err := strconv.ParseBool(r.FormValue("nsfw")); err == nil {
 			in.NSFW = v
 		}
+		in.Visibility = types.PostVisibility(r.FormValue("visibility"))
+		if s := strings.TrimSpace(r.FormValue("poll")); s != "" {
+			in.Poll = &types.CreatePoll{}
+			if err := json.Unmarshal([]byte(s), in.Poll); err != nil {
+				h.respondErr(w, errBadRequest)
+				return
+			}
+		}
 		if files, ok := r.MultipartForm.File["media"]; ok {
 			for _, header := range files {
 				if header.Size > service.MaxMediaItemBytes {
@@ -73,13 +84,7 @@ func (h *handler) createPost(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if ti.Post.Reactions == nil {
-		ti.Post.Reactions = []types.Reaction{} // non null array
-	}
-
-	if ti.Post.Media == nil {
-		ti.Post.Media = []types.Media{} // non null array
-	}
+	nonNullPostArrays(&ti.Post)
 
 	h.respond(w, ti, http.StatusCreated)
 }
@@ -127,12 +132,7 @@ func (h *handler) posts(w http.ResponseWriter, r *http.Request) {
 	}
 
 	for i := range page.Items {
-		if page.Items[i].Reactions == nil {
-			page.Items[i].Reactions = []types.Reaction{} // non null array
-		}
-		if page.Items[i].Media == nil {
-			page.Items[i].Media = []types.Media{} // non null array
-		}
+		nonNullPostArrays(&page.Items[i])
 	}
 
 	h.respond(w, page, http.StatusOK)
@@ -159,12 +159,7 @@ func (h *handler) postStream(w http.ResponseWriter, r *http.Request) {
 
 	select {
 	case p := <-pp:
-		if p.Reactions == nil {
-			p.Reactions = []types.Reaction{} // non null array
-		}
-		if p.Media == nil {
-			p.Media = []types.Media{} // non null array
-		}
+		nonNullPostArrays(&p)
 
 		h.writeSSE(w, p)
 		f.Flush()
@@ -182,12 +177,7 @@ func (h *handler) post(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if p.Reactions == nil {
-		p.Reactions = []types.Reaction{} // non null array
-	}
-	if p.Media == nil {
-		p.Media = []types.Media{} // non null array
-	}
+	nonNullPostArrays(&p)
 
 	h.respond(w, p, http.StatusOK)
 }
@@ -213,6 +203,32 @@ func (h *handler) updatePost(w http.ResponseWriter, r *http.Request) {
 	h.respond(w, out, http.StatusOK)
 }
 
+func (h *handler) postRevisions(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	q := r.URL.Query()
+	pageArgs, err := parsePageArgs(q)
+	if err != nil {
+		h.respondErr(w, err)
+		return
+	}
+
+	in := types.ListPostRevisions{
+		PostID:   r.PathValue("postID"),
+		PageArgs: pageArgs,
+	}
+	page, err := h.svc.PostRevisions(ctx, in)
+	if err != nil {
+		h.respondErr(w, err)
+		return
+	}
+
+	if page.Items == nil {
+		page.Items = []types.PostRevision{} // non null array
+	}
+
+	h.respond(w, page, http.StatusOK)
+}
+
 func (h *handler) deletePost(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 	postID := r.PathValue("postID")
@@ -245,6 +261,26 @@ func (h *handler) togglePostReaction(w http.ResponseWriter, r *http.Request) {
 	h.respond(w, out, http.StatusOK)
 }
 
+func (h *handler) votePoll(w http.ResponseWriter, r *http.Request) {
+	defer r.Body.Close()
+
+	var in types.VotePoll
+	if err := json.NewDecoder(r.Body).Decode(&in); err != nil {
+		h.respondErr(w, errBadRequest)
+		return
+	}
+
+	ctx := r.Context()
+	in.PostID = r.PathValue("postID")
+	out, err := h.svc.VotePoll(ctx, in)
+	if err != nil {
+		h.respondErr(w, err)
+		return
+	}
+
+	h.respond(w, out, http.StatusOK)
+}
+
 func (h *handler) togglePostSubscription(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 	postID := r.PathValue("postID")
@@ -256,3 +292,42 @@ func (h *handler) togglePostSubscription(w http.ResponseWriter, r *http.Request)
 
 	h.respond(w, out, http.StatusOK)
 }
+
+func (h *handler) pinPost(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	postID := r.PathValue("postID")
+	err := h.svc.PinPost(ctx, postID)
+	if err != nil {
+		h.respondErr(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *handler) unpinPost(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	postID := r.PathValue("postID")
+	err := h.svc.UnpinPost(ctx, postID)
+	if err != nil {
+		h.respondErr(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func nonNullPostArrays(p *types.Post) {
+	if p.Reactions == nil {
+		p.Reactions = []types.Reaction{} // non null array
+	}
+	if p.Media == nil {
+		p.Media = []types.Media{} // non null array
+	}
+	if p.Poll != nil && p.Poll.Options == nil {
+		p.Poll.Options = []types.PollOption{} // non null array
+	}
+	if p.RepostOf != nil {
+		nonNullPostArrays(p.RepostOf)
+	}
+}
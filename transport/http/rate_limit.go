@@ -0,0 +1,72 @@
+package http
+
+import (
+	"sync"
+	"time"
+)
+
+// rateLimiterSweepSize is the number of tracked keys
+// after which idle ones get forgotten.
+const rateLimiterSweepSize = 10_000
+
+// rateLimiter is an in-memory token bucket per key.
+type rateLimiter struct {
+	mu      sync.Mutex
+	every   time.Duration
+	burst   int
+	buckets map[string]*rateBucket
+}
+
+type rateBucket struct {
+	tokens float64
+	last   time.Time
+}
+
+// newRateLimiter allows one event every given duration per key,
+// with bursts of up to burst events.
+func newRateLimiter(every time.Duration, burst int) *rateLimiter {
+	return &rateLimiter{
+		every:   every,
+		burst:   burst,
+		buckets: map[string]*rateBucket{},
+	}
+}
+
+// allow reports whether an event for the given key may happen now.
+// When not, it also returns how long to wait for the next one.
+func (l *rateLimiter) allow(key string) (bool, time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := time.Now()
+	if len(l.buckets) >= rateLimiterSweepSize {
+		l.sweep(now)
+	}
+
+	b, ok := l.buckets[key]
+	if !ok {
+		b = &rateBucket{tokens: float64(l.burst), last: now}
+		l.buckets[key] = b
+	}
+
+	b.tokens = min(float64(l.burst), b.tokens+float64(now.Sub(b.last))/float64(l.every))
+	b.last = now
+
+	if b.tokens < 1 {
+		return false, time.Duration((1 - b.tokens) * float64(l.every))
+	}
+
+	b.tokens--
+	return true, 0
+}
+
+// sweep forgets the buckets that are full again, since they are
+// no different from new ones.
+func (l *rateLimiter) sweep(now time.Time) {
+	full := l.every * time.Duration(l.burst)
+	for key, b := range l.buckets {
+		if now.Sub(b.last) >= full {
+			delete(l.buckets, key)
+		}
+	}
+}
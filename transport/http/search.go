@@ -0,0 +1,45 @@
+package http
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/nakamauwu/nakama/types"
+	"github.com/nicolasparada/go-errs"
+)
+
+func (h *handler) search(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	q := r.URL.Query()
+	pageArgs, err := parsePageArgs(q)
+	if err != nil {
+		h.respondErr(w, err)
+		return
+	}
+
+	in := types.Search{
+		Query:    q.Get("q"),
+		Kind:     types.SearchKind(q.Get("type")),
+		PageArgs: pageArgs,
+	}
+
+	if q.Has("nsfw") {
+		in.IncludeNSFW, err = strconv.ParseBool(q.Get("nsfw"))
+		if err != nil {
+			h.respondErr(w, errs.InvalidArgumentError("invalid nsfw"))
+			return
+		}
+	}
+
+	page, err := h.svc.Search(ctx, in)
+	if err != nil {
+		h.respondErr(w, err)
+		return
+	}
+
+	if page.Items == nil {
+		page.Items = []types.SearchResult{} // non null array
+	}
+
+	h.respond(w, page, http.StatusOK)
+}
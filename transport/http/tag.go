@@ -0,0 +1,37 @@
+package http
+
+import (
+	"net/http"
+
+	"github.com/nakamauwu/nakama/types"
+)
+
+func (h *handler) tagPage(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	q := r.URL.Query()
+	pageArgs, err := parsePageArgs(q)
+	if err != nil {
+		h.respondErr(w, err)
+		return
+	}
+
+	in := types.RetrieveTagPage{
+		Tag:      r.PathValue("tag"),
+		PageArgs: pageArgs,
+	}
+	out, err := h.svc.TagPage(ctx, in)
+	if err != nil {
+		h.respondErr(w, err)
+		return
+	}
+
+	if out.RelatedTags == nil {
+		out.RelatedTags = []types.Tag{} // non null array
+	}
+
+	if out.Posts.Items == nil {
+		out.Posts.Items = []types.Post{} // non null array
+	}
+
+	h.respond(w, out, http.StatusOK)
+}
@@ -0,0 +1,52 @@
+package http
+
+import (
+	"net/http"
+
+	"github.com/nakamauwu/nakama/types"
+)
+
+func (h *handler) followTag(w http.ResponseWriter, r *http.Request) {
+	err := h.svc.FollowTag(r.Context(), r.PathValue("tag"))
+	if err != nil {
+		h.respondErr(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *handler) unfollowTag(w http.ResponseWriter, r *http.Request) {
+	err := h.svc.UnfollowTag(r.Context(), r.PathValue("tag"))
+	if err != nil {
+		h.respondErr(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *handler) tagFollows(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	q := r.URL.Query()
+	pageArgs, err := parsePageArgs(q)
+	if err != nil {
+		h.respondErr(w, err)
+		return
+	}
+
+	in := types.ListTagFollows{
+		PageArgs: pageArgs,
+	}
+	out, err := h.svc.TagFollows(ctx, in)
+	if err != nil {
+		h.respondErr(w, err)
+		return
+	}
+
+	if out.Items == nil {
+		out.Items = []types.TagFollow{} // non null array
+	}
+
+	h.respond(w, out, http.StatusOK)
+}
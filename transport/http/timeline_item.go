@@ -35,12 +35,7 @@ func (h *handler) timeline(w http.ResponseWriter, r *http.Request) {
 	}
 
 	for i := range page.Items {
-		if page.Items[i].Post.Reactions == nil {
-			page.Items[i].Post.Reactions = []types.Reaction{} // non null array
-		}
-		if page.Items[i].Post.Media == nil {
-			page.Items[i].Post.Media = []types.Media{} // non null array
-		}
+		nonNullPostArrays(&page.Items[i].Post)
 	}
 
 	h.respond(w, page, http.StatusOK)
@@ -67,12 +62,7 @@ func (h *handler) timelineItemStream(w http.ResponseWriter, r *http.Request) {
 
 	select {
 	case ti := <-tt:
-		if ti.Post.Reactions == nil {
-			ti.Post.Reactions = []types.Reaction{} // non null array
-		}
-		if ti.Post.Media == nil {
-			ti.Post.Media = []types.Media{} // non null array
-		}
+		nonNullPostArrays(&ti.Post)
 
 		h.writeSSE(w, ti)
 		f.Flush()
@@ -0,0 +1,25 @@
+package http
+
+import (
+	"net/http"
+
+	"github.com/nakamauwu/nakama/types"
+)
+
+func (h *handler) trendingTags(w http.ResponseWriter, r *http.Request) {
+	in := types.ListTrendingTags{
+		Window: types.TrendingWindow(r.URL.Query().Get("window")),
+	}
+
+	tags, err := h.svc.TrendingTags(r.Context(), in)
+	if err != nil {
+		h.respondErr(w, err)
+		return
+	}
+
+	if tags == nil {
+		tags = []types.TrendingTag{} // non null array
+	}
+
+	h.respond(w, tags, http.StatusOK)
+}
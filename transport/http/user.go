@@ -77,6 +77,10 @@ func (h *handler) userProfileByUsername(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
+	for i := range user.PinnedPosts {
+		nonNullPostArrays(&user.PinnedPosts[i])
+	}
+
 	h.respond(w, user, http.StatusOK)
 }
 
@@ -6,20 +6,35 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"math"
+	"mime"
+	"net"
 	"net/http"
 	"net/http/httputil"
+	"net/netip"
 	"net/url"
 	"strconv"
+	"strings"
 	"syscall"
 	"time"
 
 	"github.com/nakamauwu/nakama/service"
+	"github.com/nakamauwu/nakama/ssrf"
 	"github.com/nakamauwu/nakama/types"
 	"github.com/nicolasparada/go-errs"
 	"github.com/nicolasparada/go-errs/httperrs"
 )
 
-const proxyCacheControl = time.Hour * 24 * 14
+const (
+	proxyCacheControl = time.Hour * 24 * 14
+	proxyMaxBodySize  = 50 << 20
+	proxyMaxRedirects = 5
+	proxyTimeout      = time.Minute
+	// proxyRateLimitEvery and proxyRateLimitBurst leave room
+	// for a page with many images.
+	proxyRateLimitEvery = time.Second / 2
+	proxyRateLimitBurst = 60
+)
 
 const errInvalidTargetURL = errs.InvalidArgumentError("invalid target URL")
 
@@ -31,6 +46,8 @@ var (
 	errEmailNotVerified     = errors.New("email not verified")
 	errEmailNotProvided     = errors.New("email not provided")
 	errServiceUnavailable   = errors.New("service unavailable")
+	errTooManyRedirects     = errors.New("too many redirects")
+	errProxyBodyTooLarge    = errors.New("proxy body too large")
 )
 
 func (h *handler) respond(w http.ResponseWriter, v any, statusCode int) {
@@ -108,7 +125,8 @@ func (h *handler) proxy(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	targetStr := r.URL.Query().Get("target")
+	q := r.URL.Query()
+	targetStr := q.Get("target")
 	if targetStr == "" {
 		h.respondErr(w, errInvalidTargetURL)
 		return
@@ -121,7 +139,17 @@ func (h *handler) proxy(w http.ResponseWriter, r *http.Request) {
 	}
 	target.Fragment = ""
 
+	if err := h.svc.VerifyProxyTarget(targetStr, q.Get("sig")); err != nil {
+		h.respondErr(w, err)
+		return
+	}
+
+	if !h.allowProxyRequest(w, r) {
+		return
+	}
+
 	proxy := &httputil.ReverseProxy{
+		Transport: h.proxyTransport,
 		Rewrite: func(pr *httputil.ProxyRequest) {
 			pr.Out.URL = target
 			pr.Out.Host = target.Host
@@ -146,24 +174,164 @@ func (h *handler) proxy(w http.ResponseWriter, r *http.Request) {
 			w.WriteHeader(http.StatusBadGateway)
 		},
 		ModifyResponse: func(resp *http.Response) error {
+			if err := checkProxyResponse(resp); err != nil {
+				return err
+			}
+
+			resp.Body = &maxBytesBody{ReadCloser: resp.Body, remaining: proxyMaxBodySize}
+
 			resp.Header.Del("Set-Cookie")         // Drop upstream cookies.
 			resp.Header.Del("Set-Cookie2")        // Drop legacy cookies.
 			resp.Header.Del("Clear-Site-Data")    // Drop site-wide clears.
 			resp.Header.Del("Www-Authenticate")   // Drop browser auth prompts.
 			resp.Header.Del("Proxy-Authenticate") // Drop proxy auth prompts.
+			resp.Header.Del("Location")           // Redirects are followed upstream.
+
+			// Served from our origin, so media like SVGs must not run anything.
+			resp.Header.Set("X-Content-Type-Options", "nosniff")
+			resp.Header.Set("Content-Security-Policy", "default-src 'none'; style-src 'unsafe-inline'; sandbox")
+			// only successful responses get cached.
+			resp.Header.Set("Cache-Control", fmt.Sprintf("max-age=%d, public", int64(proxyCacheControl.Seconds())))
 			return nil
 		},
 	}
 	proxy.ServeHTTP(w, r)
 }
 
-func withCacheControl(d time.Duration) func(http.HandlerFunc) http.HandlerFunc {
-	return func(h http.HandlerFunc) http.HandlerFunc {
-		return func(w http.ResponseWriter, r *http.Request) {
-			w.Header().Set("Cache-Control", fmt.Sprintf("max-age=%d, public", int64(d.Seconds())))
-			h(w, r)
+// allowProxyRequest responds with 429 when the caller ran out of requests
+// that fetch from third parties.
+func (h *handler) allowProxyRequest(w http.ResponseWriter, r *http.Request) bool {
+	ok, retryAfter := h.proxyLimiter.allow(h.rateLimitKey(r))
+	if !ok {
+		w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(retryAfter.Seconds()))))
+		http.Error(w, http.StatusText(http.StatusTooManyRequests), http.StatusTooManyRequests)
+	}
+	return ok
+}
+
+// newProxyTransport follows redirects upstream, up to [proxyMaxRedirects],
+// so clients never get sent to a third party directly,
+// and only connects to public addresses.
+func newProxyTransport() http.RoundTripper {
+	return &redirectTransport{client: &http.Client{
+		Transport: ssrf.NewTransport(),
+		Timeout:   proxyTimeout,
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			if len(via) >= proxyMaxRedirects {
+				return errTooManyRedirects
+			}
+
+			if req.URL.Scheme != "http" && req.URL.Scheme != "https" {
+				return errInvalidTargetURL
+			}
+
+			return nil
+		},
+	}}
+}
+
+type redirectTransport struct {
+	client *http.Client
+}
+
+func (t *redirectTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	return t.client.Do(req)
+}
+
+// checkProxyResponse only lets successful responses of media content through.
+func checkProxyResponse(resp *http.Response) error {
+	switch resp.StatusCode {
+	case http.StatusOK, http.StatusPartialContent:
+	case http.StatusNotModified:
+		return nil
+	default:
+		return fmt.Errorf("proxy upstream status code %d", resp.StatusCode)
+	}
+
+	mediaType, _, err := mime.ParseMediaType(resp.Header.Get("Content-Type"))
+	if err != nil {
+		return fmt.Errorf("proxy upstream content type: %w", err)
+	}
+
+	if !strings.HasPrefix(mediaType, "image/") && !strings.HasPrefix(mediaType, "video/") && !strings.HasPrefix(mediaType, "audio/") {
+		return fmt.Errorf("proxy upstream content type %q not allowed", mediaType)
+	}
+
+	if resp.ContentLength > proxyMaxBodySize {
+		return fmt.Errorf("proxy upstream content length %d too large", resp.ContentLength)
+	}
+
+	return nil
+}
+
+// maxBytesBody fails reads past the remaining bytes
+// for responses that don't declare their length upfront.
+type maxBytesBody struct {
+	io.ReadCloser
+	remaining int64
+}
+
+func (b *maxBytesBody) Read(p []byte) (int, error) {
+	if b.remaining <= 0 {
+		// bodies of exactly the limit are fine,
+		// only fail when upstream has more to send.
+		var extra [1]byte
+		n, err := b.ReadCloser.Read(extra[:])
+		if n > 0 {
+			return 0, errProxyBodyTooLarge
 		}
+
+		return 0, err
+	}
+
+	if int64(len(p)) > b.remaining {
+		p = p[:b.remaining]
+	}
+
+	n, err := b.ReadCloser.Read(p)
+	b.remaining -= int64(n)
+	return n, err
+}
+
+// rateLimitKey identifies the caller by user ID when authenticated
+// and by IP address otherwise.
+func (h *handler) rateLimitKey(r *http.Request) string {
+	if uid, ok := r.Context().Value(service.KeyAuthUserID).(string); ok {
+		return "user:" + uid
 	}
+
+	if ip, ok := h.forwardedClientIP(r); ok {
+		return "ip:" + ip
+	}
+
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host = r.RemoteAddr
+	}
+
+	return "ip:" + host
+}
+
+// forwardedClientIP reads the client IP address from [handler.clientIPHeader].
+// Lists like X-Forwarded-For take the last address,
+// the one added by the trusted proxy itself.
+func (h *handler) forwardedClientIP(r *http.Request) (string, bool) {
+	if h.clientIPHeader == "" {
+		return "", false
+	}
+
+	values := r.Header.Values(h.clientIPHeader)
+	if len(values) == 0 {
+		return "", false
+	}
+
+	list := strings.Split(values[len(values)-1], ",")
+	addr, err := netip.ParseAddr(strings.TrimSpace(list[len(list)-1]))
+	if err != nil {
+		return "", false
+	}
+
+	return addr.Unmap().String(), true
 }
 
 func redirectWithHashFragment(w http.ResponseWriter, r *http.Request, uri *url.URL, data url.Values, statusCode int) {
@@ -0,0 +1,20 @@
+package types
+
+import "time"
+
+// AccountDeletion is a pending account deletion.
+// The account stays deactivated until DeleteAfter,
+// and logging back in before that cancels it.
+type AccountDeletion struct {
+	DeleteAfter time.Time `json:"deleteAfter"`
+}
+
+// DeletedUser holds the objects left to remove from storage
+// after permanently deleting a user.
+type DeletedUser struct {
+	Avatar *string
+	Cover  *string
+	Media  []Media
+	// DataExports are the object keys of the user's export archives.
+	DataExports []string
+}
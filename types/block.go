@@ -0,0 +1,18 @@
+package types
+
+type ListBlocks struct {
+	PageArgs
+	userID string
+}
+
+func (in *ListBlocks) SetUserID(userID string) {
+	in.userID = userID
+}
+
+func (in ListBlocks) UserID() string {
+	return in.userID
+}
+
+func (in *ListBlocks) Validate() error {
+	return in.PageArgs.Validate()
+}
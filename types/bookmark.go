@@ -0,0 +1,55 @@
+package types
+
+import (
+	"time"
+
+	"github.com/nicolasparada/go-errs"
+)
+
+// Bookmark is a post privately saved for later.
+type Bookmark struct {
+	BookmarkedAt time.Time `json:"bookmarkedAt" db:"bookmarked_at"`
+	Post
+}
+
+type ToggleBookmark struct {
+	PostID string `json:"-"`
+	userID string
+}
+
+func (in *ToggleBookmark) SetUserID(userID string) {
+	in.userID = userID
+}
+
+func (in ToggleBookmark) UserID() string {
+	return in.userID
+}
+
+func (in *ToggleBookmark) Validate() error {
+	if !ValidUUIDv4(in.PostID) {
+		return errs.InvalidArgumentError("invalid post ID")
+	}
+
+	return nil
+}
+
+type ToggledBookmark struct {
+	Bookmarked bool `json:"bookmarked"`
+}
+
+type ListBookmarks struct {
+	PageArgs
+	userID string
+}
+
+func (in *ListBookmarks) SetUserID(userID string) {
+	in.userID = userID
+}
+
+func (in ListBookmarks) UserID() string {
+	return in.userID
+}
+
+func (in *ListBookmarks) Validate() error {
+	return in.PageArgs.Validate()
+}
@@ -10,15 +10,25 @@ import (
 
 const CommentContentMaxLength = 2048
 
+// CommentMaxDepth bounds how deep replies can nest.
+// Top level comments have depth 0. Replying to a comment at the max depth
+// adds a sibling to it instead.
+const CommentMaxDepth = 3
+
 type Comment struct {
-	ID        string     `json:"id"`
-	UserID    string     `json:"userID" db:"user_id"`
-	PostID    string     `json:"postID" db:"post_id"`
-	Content   string     `json:"content"`
-	Reactions []Reaction `json:"reactions"`
-	CreatedAt time.Time  `json:"createdAt" db:"created_at"`
-	User      *User      `json:"user,omitempty"`
-	Mine      bool       `json:"mine" db:"mine,omitempty"`
+	ID           string     `json:"id"`
+	UserID       string     `json:"userID" db:"user_id"`
+	PostID       string     `json:"postID" db:"post_id"`
+	ParentID     *string    `json:"parentID" db:"parent_id"`
+	Depth        int        `json:"depth"`
+	Content      string     `json:"content"`
+	Entities     []Entity   `json:"entities"`
+	RepliesCount int        `json:"repliesCount" db:"replies_count"`
+	Reactions    []Reaction `json:"reactions"`
+	Edited       bool       `json:"edited"`
+	CreatedAt    time.Time  `json:"createdAt" db:"created_at"`
+	User         *User      `json:"user,omitempty"`
+	Mine         bool       `json:"mine" db:"mine,omitempty"`
 }
 
 type CommentPreview struct {
@@ -28,12 +38,19 @@ type CommentPreview struct {
 	Content string `json:"content"`
 }
 
+// RedactSpoilers is like [PostPreview.RedactSpoilers].
+func (c *CommentPreview) RedactSpoilers() {
+	c.Content = textutil.RedactSpoilers(c.Content)
+}
+
 type CreateComment struct {
-	PostID  string `json:"-"`
-	Content string `json:"content"`
+	PostID   string  `json:"-"`
+	ParentID *string `json:"parentID"`
+	Content  string  `json:"content"`
 
-	userID string
-	tags   []string
+	userID   string
+	tags     []string
+	entities []Entity
 }
 
 func (in *CreateComment) SetUserID(userID string) {
@@ -52,16 +69,32 @@ func (in CreateComment) Tags() []string {
 	return in.tags
 }
 
+func (in *CreateComment) SetEntities(entities []Entity) {
+	in.entities = entities
+}
+
+func (in CreateComment) Entities() []Entity {
+	return in.entities
+}
+
 func (in *CreateComment) Validate() error {
 	if !ValidUUIDv4(in.PostID) {
 		return errs.InvalidArgumentError("invalid post ID")
 	}
 
+	if in.ParentID != nil && !ValidUUIDv4(*in.ParentID) {
+		return errs.InvalidArgumentError("invalid parent ID")
+	}
+
 	in.Content = textutil.SmartTrim(in.Content)
 	if in.Content == "" || utf8.RuneCountInString(in.Content) > CommentContentMaxLength {
 		return errs.InvalidArgumentError("invalid content")
 	}
 
+	if !textutil.ValidSpoilers(in.Content) {
+		return errs.InvalidArgumentError("invalid inline spoiler")
+	}
+
 	return nil
 }
 
@@ -87,10 +120,40 @@ func (in *ListComments) Validate() error {
 	return in.PageArgs.Validate()
 }
 
+type CreatedComment struct {
+	ID        string    `db:"id"`
+	ParentID  *string   `db:"parent_id"`
+	Depth     int       `db:"depth"`
+	CreatedAt time.Time `db:"created_at"`
+}
+
+type ListCommentReplies struct {
+	CommentID string
+	PageArgs
+	viewerID *string
+}
+
+func (in *ListCommentReplies) SetViewerID(userID string) {
+	in.viewerID = &userID
+}
+
+func (in ListCommentReplies) ViewerID() *string {
+	return in.viewerID
+}
+
+func (in *ListCommentReplies) Validate() error {
+	if !ValidUUIDv4(in.CommentID) {
+		return errs.InvalidArgumentError("invalid comment ID")
+	}
+
+	return in.PageArgs.Validate()
+}
+
 type UpdateComment struct {
-	ID      string  `json:"-"`
-	Content *string `json:"content"`
-	tags    []string
+	ID       string  `json:"-"`
+	Content  *string `json:"content"`
+	tags     []string
+	entities []Entity
 }
 
 func (in *UpdateComment) SetTags(tags []string) {
@@ -101,6 +164,14 @@ func (in UpdateComment) Tags() []string {
 	return in.tags
 }
 
+func (in *UpdateComment) SetEntities(entities []Entity) {
+	in.entities = entities
+}
+
+func (in UpdateComment) Entities() []Entity {
+	return in.entities
+}
+
 func (in *UpdateComment) Validate() error {
 	if !ValidUUIDv4(in.ID) {
 		return errs.InvalidArgumentError("invalid comment ID")
@@ -111,11 +182,21 @@ func (in *UpdateComment) Validate() error {
 		if *in.Content == "" || utf8.RuneCountInString(*in.Content) > CommentContentMaxLength {
 			return errs.InvalidArgumentError("invalid content")
 		}
+
+		if !textutil.ValidSpoilers(*in.Content) {
+			return errs.InvalidArgumentError("invalid inline spoiler")
+		}
 	}
 
 	return nil
 }
 
 type UpdatedComment struct {
-	Content string `json:"content"`
+	Content  string   `json:"content"`
+	Entities []Entity `json:"entities"`
+	Edited   bool     `json:"edited"`
+
+	// PostID and PreviousContent are used to diff mentions.
+	PostID          string `json:"-" db:"post_id"`
+	PreviousContent string `json:"-" db:"-"`
 }
@@ -0,0 +1,28 @@
+package types
+
+import (
+	"time"
+
+	"github.com/nicolasparada/go-errs"
+)
+
+// CommentRevision holds the state of a comment before it got edited.
+type CommentRevision struct {
+	ID        string    `json:"id"`
+	CommentID string    `json:"commentID" db:"comment_id"`
+	Content   string    `json:"content"`
+	CreatedAt time.Time `json:"createdAt" db:"created_at"`
+}
+
+type ListCommentRevisions struct {
+	CommentID string
+	PageArgs
+}
+
+func (in *ListCommentRevisions) Validate() error {
+	if !ValidUUIDv4(in.CommentID) {
+		return errs.InvalidArgumentError("invalid comment ID")
+	}
+
+	return in.PageArgs.Validate()
+}
@@ -0,0 +1,55 @@
+package types
+
+import (
+	"io"
+	"regexp"
+	"time"
+
+	"github.com/nicolasparada/go-errs"
+)
+
+var reShortcode = regexp.MustCompile(`^[a-z0-9_]{2,32}$`)
+
+// ValidShortcode reports whether s is a valid custom emoji shortcode,
+// without the surrounding colons.
+func ValidShortcode(s string) bool {
+	return reShortcode.MatchString(s)
+}
+
+// CustomEmoji is an instance-level emoji uploaded by an admin.
+type CustomEmoji struct {
+	Shortcode string    `json:"shortcode"`
+	ImageURL  string    `json:"imageURL" db:"image"`
+	CreatedAt time.Time `json:"createdAt" db:"created_at"`
+}
+
+func (e *CustomEmoji) SetImageURL(baseURL, bucket string) {
+	e.ImageURL = makeURL(baseURL, bucket, e.ImageURL)
+}
+
+type CreateCustomEmoji struct {
+	Shortcode   string
+	ImageReader io.ReadSeeker
+	image       string
+}
+
+// SetImage sets the object key the image got stored at.
+func (in *CreateCustomEmoji) SetImage(image string) {
+	in.image = image
+}
+
+func (in CreateCustomEmoji) Image() string {
+	return in.image
+}
+
+func (in *CreateCustomEmoji) Validate() error {
+	if !ValidShortcode(in.Shortcode) {
+		return errs.InvalidArgumentError("invalid shortcode")
+	}
+
+	if in.ImageReader == nil {
+		return errs.InvalidArgumentError("missing custom emoji image")
+	}
+
+	return nil
+}
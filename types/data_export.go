@@ -0,0 +1,43 @@
+package types
+
+import (
+	"encoding/json"
+	"time"
+)
+
+type DataExportStatus string
+
+const (
+	DataExportStatusPending    DataExportStatus = "pending"
+	DataExportStatusProcessing DataExportStatus = "processing"
+	DataExportStatusReady      DataExportStatus = "ready"
+	DataExportStatusFailed     DataExportStatus = "failed"
+)
+
+// DataExport is a ZIP archive with all the data from a user.
+// It gets built in the background and the user is emailed a link once ready.
+type DataExport struct {
+	ID        string           `json:"id"`
+	UserID    string           `json:"userID" db:"user_id"`
+	Status    DataExportStatus `json:"status"`
+	ExpiresAt *time.Time       `json:"expiresAt" db:"expires_at"`
+	CreatedAt time.Time        `json:"createdAt" db:"created_at"`
+}
+
+// UserData to include in a [DataExport].
+// Each section is already JSON encoded.
+type UserData struct {
+	Email            string
+	Profile          json.RawMessage
+	Posts            json.RawMessage
+	Drafts           json.RawMessage
+	Comments         json.RawMessage
+	PostReactions    json.RawMessage
+	CommentReactions json.RawMessage
+	Following        json.RawMessage
+	Followers        json.RawMessage
+	Notifications    json.RawMessage
+	Avatar           *string
+	Cover            *string
+	Media            []Media
+}
@@ -0,0 +1,186 @@
+package types
+
+import (
+	"io"
+	"time"
+	"unicode/utf8"
+
+	"github.com/nakamauwu/nakama/textutil"
+	"github.com/nicolasparada/go-errs"
+)
+
+// Draft is a post that is not published yet.
+// When PublishAt is set, the draft is scheduled and gets published automatically.
+type Draft struct {
+	ID         string         `json:"id"`
+	UserID     string         `json:"userID" db:"user_id"`
+	Content    string         `json:"content"`
+	SpoilerOf  *string        `json:"spoilerOf" db:"spoiler_of"`
+	NSFW       bool           `json:"nsfw"`
+	Visibility PostVisibility `json:"visibility"`
+	Media      []Media        `json:"media" db:"media"`
+	PublishAt  *time.Time     `json:"publishAt" db:"publish_at"`
+	CreatedAt  time.Time      `json:"createdAt" db:"created_at"`
+	UpdatedAt  time.Time      `json:"updatedAt" db:"updated_at"`
+}
+
+func (d *Draft) SetMediaPaths(base, bucket string) {
+	for i, media := range d.Media {
+		d.Media[i].Path = makeURL(base, bucket, media.Path)
+	}
+}
+
+// CreateDraft input.
+// Poll is only accepted to be rejected, since drafts cannot carry polls.
+type CreateDraft struct {
+	Content      string          `json:"content"`
+	SpoilerOf    *string         `json:"spoilerOf"`
+	NSFW         bool            `json:"nsfw"`
+	Visibility   PostVisibility  `json:"visibility"`
+	PublishAt    *time.Time      `json:"publishAt"`
+	Poll         *CreatePoll     `json:"poll"`
+	MediaReaders []io.ReadSeeker `json:"-"`
+	userID       string
+	media        []Media
+}
+
+func (in *CreateDraft) SetUserID(userID string) {
+	in.userID = userID
+}
+
+func (in CreateDraft) UserID() string {
+	return in.userID
+}
+
+func (in *CreateDraft) SetMedia(media []Media) {
+	in.media = media
+}
+
+func (in CreateDraft) Media() []Media {
+	return in.media
+}
+
+func (in *CreateDraft) Validate() error {
+	in.Content = textutil.SmartTrim(in.Content)
+
+	if utf8.RuneCountInString(in.Content) > PostContentMaxLength {
+		return errs.InvalidArgumentError("invalid content")
+	}
+
+	if !textutil.ValidSpoilers(in.Content) {
+		return errs.InvalidArgumentError("invalid inline spoiler")
+	}
+
+	if in.SpoilerOf != nil {
+		*in.SpoilerOf = textutil.SmartTrim(*in.SpoilerOf)
+
+		if *in.SpoilerOf == "" || utf8.RuneCountInString(*in.SpoilerOf) > PostSpoilerMaxLength {
+			return errs.InvalidArgumentError("invalid spoiler")
+		}
+	}
+
+	if len(in.MediaReaders) > PostMaxMediaItems {
+		return errs.InvalidArgumentError("too many media items")
+	}
+
+	if in.Poll != nil {
+		return errs.InvalidArgumentError("drafts cannot have polls")
+	}
+
+	if in.Visibility == "" {
+		in.Visibility = PostVisibilityPublic
+	}
+
+	if !in.Visibility.IsValid() {
+		return errs.InvalidArgumentError("invalid visibility")
+	}
+
+	if in.PublishAt != nil {
+		if in.Content == "" {
+			return errs.InvalidArgumentError("scheduled draft cannot be empty")
+		}
+
+		if !in.PublishAt.After(time.Now()) {
+			return errs.InvalidArgumentError("invalid publish at")
+		}
+	}
+
+	return nil
+}
+
+type ListDrafts struct {
+	PageArgs
+	userID string
+}
+
+func (in *ListDrafts) SetUserID(userID string) {
+	in.userID = userID
+}
+
+func (in ListDrafts) UserID() string {
+	return in.userID
+}
+
+func (in *ListDrafts) Validate() error {
+	return in.PageArgs.Validate()
+}
+
+// UpdateDraft updates only the given fields.
+// Set Unschedule to turn a scheduled draft back into a regular draft.
+// Poll is only accepted to be rejected, since drafts cannot carry polls.
+type UpdateDraft struct {
+	ID         string          `json:"-"`
+	Content    *string         `json:"content"`
+	SpoilerOf  *string         `json:"spoilerOf"`
+	NSFW       *bool           `json:"nsfw"`
+	Visibility *PostVisibility `json:"visibility"`
+	PublishAt  *time.Time      `json:"publishAt"`
+	Unschedule bool            `json:"unschedule"`
+	Poll       *CreatePoll     `json:"poll"`
+}
+
+func (in *UpdateDraft) Validate() error {
+	if !ValidUUIDv4(in.ID) {
+		return errs.InvalidArgumentError("invalid draft ID")
+	}
+
+	if in.Content != nil {
+		*in.Content = textutil.SmartTrim(*in.Content)
+
+		if utf8.RuneCountInString(*in.Content) > PostContentMaxLength {
+			return errs.InvalidArgumentError("invalid content")
+		}
+
+		if !textutil.ValidSpoilers(*in.Content) {
+			return errs.InvalidArgumentError("invalid inline spoiler")
+		}
+	}
+
+	if in.SpoilerOf != nil {
+		*in.SpoilerOf = textutil.SmartTrim(*in.SpoilerOf)
+
+		if *in.SpoilerOf != "" && utf8.RuneCountInString(*in.SpoilerOf) > PostSpoilerMaxLength {
+			return errs.InvalidArgumentError("invalid spoiler of")
+		}
+	}
+
+	if in.Visibility != nil && !in.Visibility.IsValid() {
+		return errs.InvalidArgumentError("invalid visibility")
+	}
+
+	if in.Poll != nil {
+		return errs.InvalidArgumentError("drafts cannot have polls")
+	}
+
+	if in.PublishAt != nil {
+		if in.Unschedule {
+			return errs.InvalidArgumentError("cannot specify both publish at and unschedule")
+		}
+
+		if !in.PublishAt.After(time.Now()) {
+			return errs.InvalidArgumentError("invalid publish at")
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,21 @@
+package types
+
+// EmbedKind tells how clients render an [Embed].
+type EmbedKind string
+
+const (
+	EmbedKindImage EmbedKind = "image"
+	EmbedKindVideo EmbedKind = "video"
+	// EmbedKindHTML embeds are third party markup,
+	// to be enhanced by the provider's own script.
+	EmbedKindHTML EmbedKind = "html"
+)
+
+// Embed of a link to a supported third party.
+type Embed struct {
+	Kind EmbedKind `json:"kind"`
+	// URL of the image or video.
+	URL string `json:"url,omitempty"`
+	// HTML markup of html embeds.
+	HTML string `json:"html,omitempty"`
+}
@@ -0,0 +1,26 @@
+package types
+
+import "github.com/nakamauwu/nakama/textutil"
+
+// Entity is a span of post or comment content with a special meaning,
+// so clients don't have to parse the content themselves.
+type Entity struct {
+	textutil.Entity
+	// UserID of the mentioned user, if it exists.
+	UserID *string `json:"userID,omitempty"`
+}
+
+// MakeEntities finds the entities in the given content.
+// Mentions still need to be resolved to their user IDs.
+func MakeEntities(content string) []Entity {
+	found := textutil.Entities(content)
+	if len(found) == 0 {
+		return nil
+	}
+
+	out := make([]Entity, len(found))
+	for i, e := range found {
+		out[i] = Entity{Entity: e}
+	}
+	return out
+}
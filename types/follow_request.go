@@ -0,0 +1,18 @@
+package types
+
+type ListFollowRequests struct {
+	PageArgs
+	userID string
+}
+
+func (in *ListFollowRequests) SetUserID(userID string) {
+	in.userID = userID
+}
+
+func (in ListFollowRequests) UserID() string {
+	return in.userID
+}
+
+func (in *ListFollowRequests) Validate() error {
+	return in.PageArgs.Validate()
+}
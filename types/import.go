@@ -0,0 +1,31 @@
+package types
+
+// ImportSource is the network an archive was exported from.
+type ImportSource string
+
+const (
+	ImportSourceMastodon ImportSource = "mastodon"
+	ImportSourceTwitter  ImportSource = "twitter"
+)
+
+// ImportPost creates a post out of one from another network,
+// remembering where it came from so it does not get imported twice.
+type ImportPost struct {
+	CreatePost
+	Source   ImportSource
+	SourceID string
+}
+
+// ImportReport sums up an archive import.
+type ImportReport struct {
+	Source   ImportSource    `json:"source"`
+	Imported int             `json:"imported"`
+	Skipped  []ImportSkipped `json:"skipped"`
+}
+
+// ImportSkipped is a post, or an attachment of a post,
+// from the archive that could not be imported.
+type ImportSkipped struct {
+	SourceID string `json:"sourceID"`
+	Reason   string `json:"reason"`
+}
@@ -0,0 +1,29 @@
+package types
+
+import "github.com/nakamauwu/nakama/textutil"
+
+// LinkPreview is the card shown for the first URL in a post.
+type LinkPreview struct {
+	URL         string  `json:"url"`
+	Title       string  `json:"title"`
+	Description string  `json:"description"`
+	ImageURL    *string `json:"imageURL"`
+	SiteName    string  `json:"siteName"`
+}
+
+// LinkURL returns the first URL in the entities that is not part of a spoiler.
+// That's the one that gets a link preview.
+func LinkURL(entities []Entity) *string {
+	var spoilerEnd int
+	for _, e := range entities {
+		switch e.Kind {
+		case textutil.EntityKindSpoiler:
+			spoilerEnd = max(spoilerEnd, e.End)
+		case textutil.EntityKindURL:
+			if e.Start >= spoilerEnd {
+				return &e.Value
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,45 @@
+package types
+
+import (
+	"strings"
+	"time"
+
+	"github.com/nicolasparada/go-errs"
+)
+
+// MuteUser hides the posts and notifications from a user
+// without them knowing. Leave ExpiresAt empty to mute indefinitely.
+type MuteUser struct {
+	Username  string     `json:"-"`
+	ExpiresAt *time.Time `json:"expiresAt"`
+}
+
+func (in *MuteUser) Validate() error {
+	in.Username = strings.TrimSpace(in.Username)
+	if !ValidUsername(in.Username) {
+		return errs.InvalidArgumentError("invalid username")
+	}
+
+	if in.ExpiresAt != nil && !in.ExpiresAt.After(time.Now()) {
+		return errs.InvalidArgumentError("invalid expires at")
+	}
+
+	return nil
+}
+
+type ListMutes struct {
+	PageArgs
+	userID string
+}
+
+func (in *ListMutes) SetUserID(userID string) {
+	in.userID = userID
+}
+
+func (in ListMutes) UserID() string {
+	return in.userID
+}
+
+func (in *ListMutes) Validate() error {
+	return in.PageArgs.Validate()
+}
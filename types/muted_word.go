@@ -0,0 +1,125 @@
+package types
+
+import (
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/nakamauwu/nakama/textutil"
+	"github.com/nicolasparada/go-errs"
+)
+
+const (
+	MutedWordKeywordMaxLength = 100
+	MaxMutedWords             = 200
+)
+
+// MutedWordScope is where a muted word applies.
+type MutedWordScope string
+
+const (
+	MutedWordScopeTimeline      MutedWordScope = "timeline"
+	MutedWordScopeNotifications MutedWordScope = "notifications"
+	MutedWordScopeBoth          MutedWordScope = "both"
+)
+
+func (s MutedWordScope) IsValid() bool {
+	switch s {
+	case MutedWordScopeTimeline, MutedWordScopeNotifications, MutedWordScopeBoth:
+		return true
+	default:
+		return false
+	}
+}
+
+func (s MutedWordScope) String() string {
+	return string(s)
+}
+
+// MutedWordAction is what happens to the posts matching a muted word.
+type MutedWordAction string
+
+const (
+	MutedWordActionHide MutedWordAction = "hide"
+	// MutedWordActionFilter keeps the post but marks it as filtered
+	// so clients can collapse it.
+	MutedWordActionFilter MutedWordAction = "filter"
+)
+
+func (a MutedWordAction) IsValid() bool {
+	switch a {
+	case MutedWordActionHide, MutedWordActionFilter:
+		return true
+	default:
+		return false
+	}
+}
+
+func (a MutedWordAction) String() string {
+	return string(a)
+}
+
+// MutedWord is a word, phrase or "#hashtag"
+// a user doesn't want to see.
+type MutedWord struct {
+	ID        string          `json:"id"`
+	UserID    string          `json:"userID" db:"user_id"`
+	Keyword   string          `json:"keyword"`
+	Scope     MutedWordScope  `json:"scope"`
+	Action    MutedWordAction `json:"action"`
+	CreatedAt time.Time       `json:"createdAt" db:"created_at"`
+	Username  string          `json:"-" db:"username"`
+}
+
+// Match reports whether the given text contains the muted word.
+func (w MutedWord) Match(text string) bool {
+	return textutil.MatchKeyword(text, w.Keyword)
+}
+
+type CreateMutedWord struct {
+	Keyword string          `json:"keyword"`
+	Scope   MutedWordScope  `json:"scope"`
+	Action  MutedWordAction `json:"action"`
+	userID  string
+}
+
+func (in *CreateMutedWord) SetUserID(userID string) {
+	in.userID = userID
+}
+
+func (in CreateMutedWord) UserID() string {
+	return in.userID
+}
+
+func (in *CreateMutedWord) Validate() error {
+	in.Keyword = textutil.NormalizeKeyword(in.Keyword)
+	if in.Keyword == "" || in.Keyword == "#" {
+		return errs.InvalidArgumentError("empty keyword")
+	}
+
+	if utf8.RuneCountInString(in.Keyword) > MutedWordKeywordMaxLength {
+		return errs.InvalidArgumentError("keyword too long")
+	}
+
+	if strings.HasPrefix(in.Keyword, "#") && strings.ContainsRune(in.Keyword, ' ') {
+		return errs.InvalidArgumentError("invalid hashtag")
+	}
+
+	if in.Scope == "" {
+		in.Scope = MutedWordScopeBoth
+	}
+
+	if !in.Scope.IsValid() {
+		return errs.InvalidArgumentError("invalid scope")
+	}
+
+	if in.Action == "" {
+		in.Action = MutedWordActionHide
+	}
+
+	if !in.Action.IsValid() {
+		return errs.InvalidArgumentError("invalid action")
+	}
+
+	return nil
+}
@@ -11,11 +11,23 @@ const (
 	NotificationKindComment        NotificationKind = "comment"
 	NotificationKindPostMention    NotificationKind = "post_mention"
 	NotificationKindCommentMention NotificationKind = "comment_mention"
+	NotificationKindRepost         NotificationKind = "repost"
+	NotificationKindQuote          NotificationKind = "quote"
+	NotificationKindPollClosed     NotificationKind = "poll_closed"
+	NotificationKindCommentReply   NotificationKind = "comment_reply"
+	NotificationKindFollowRequest  NotificationKind = "follow_request"
+	NotificationKindFollowAccepted NotificationKind = "follow_accepted"
+	// NotificationKindDraftFailed tells the author their scheduled draft
+	// could not be published and got unscheduled.
+	NotificationKindDraftFailed NotificationKind = "draft_failed"
 )
 
 func (k NotificationKind) IsValid() bool {
 	switch k {
-	case NotificationKindFollow, NotificationKindComment, NotificationKindPostMention, NotificationKindCommentMention:
+	case NotificationKindFollow, NotificationKindComment, NotificationKindPostMention, NotificationKindCommentMention,
+		NotificationKindRepost, NotificationKindQuote, NotificationKindPollClosed,
+		NotificationKindCommentReply, NotificationKindFollowRequest, NotificationKindFollowAccepted,
+		NotificationKindDraftFailed:
 		return true
 	default:
 		return false
@@ -34,6 +46,7 @@ type Notification struct {
 	Kind         NotificationKind `json:"kind" db:"kind"`
 	PostID       *string          `json:"postID,omitempty" db:"post_id,omitempty"`
 	CommentID    *string          `json:"commentID,omitempty" db:"comment_id,omitempty"`
+	DraftID      *string          `json:"draftID,omitempty" db:"draft_id,omitempty"`
 	ReadAt       *time.Time       `json:"readAt" db:"read_at"`
 	IssuedAt     time.Time        `json:"issuedAt" db:"issued_at"`
 	Read         bool             `json:"read"`
@@ -75,6 +88,17 @@ type CreatedNotification struct {
 type FanoutCommentNotification struct {
 	ActorUserID string
 	PostID      string
+	// ParentID of the comment being replied to, if it is a reply.
+	// Not the stored parent of flattened replies, as the author replied to this one.
+	// The parent comment author gets a [NotificationKindCommentReply] instead.
+	ParentID *string
+}
+
+type CreateCommentReplyNotification struct {
+	ActorUserID string
+	PostID      string
+	CommentID   string
+	ParentID    string
 }
 
 type CreateMentionNotifications struct {
@@ -84,3 +108,17 @@ type CreateMentionNotifications struct {
 	Kind        NotificationKind
 	Mentions    []string
 }
+
+type CreateRepostNotification struct {
+	ActorUserID string
+	PostID      string
+	RepostOfID  string
+	Quote       bool
+}
+
+type DeleteMentionNotifications struct {
+	PostID    string
+	CommentID *string
+	Kind      NotificationKind
+	Mentions  []string
+}
@@ -4,6 +4,9 @@ import "github.com/nicolasparada/go-errs"
 
 const maxPageSize = 200
 
+// DefaultPageSize when neither first nor last are given.
+const DefaultPageSize = 15
+
 type Page[T any] struct {
 	Items    []T      `json:"items"`
 	PageInfo PageInfo `json:"pageInfo"`
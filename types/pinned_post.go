@@ -0,0 +1,27 @@
+package types
+
+import "github.com/nicolasparada/go-errs"
+
+// MaxPinnedPosts a user can have at the top of their profile.
+const MaxPinnedPosts = 3
+
+type ListPinnedPosts struct {
+	UserID   string
+	viewerID *string
+}
+
+func (in *ListPinnedPosts) SetViewerID(viewerID string) {
+	in.viewerID = &viewerID
+}
+
+func (in ListPinnedPosts) ViewerID() *string {
+	return in.viewerID
+}
+
+func (in *ListPinnedPosts) Validate() error {
+	if !ValidUUIDv4(in.UserID) {
+		return errs.InvalidArgumentError("invalid user ID")
+	}
+
+	return nil
+}
@@ -0,0 +1,106 @@
+package types
+
+import (
+	"time"
+	"unicode/utf8"
+
+	"github.com/nakamauwu/nakama/textutil"
+	"github.com/nicolasparada/go-errs"
+)
+
+const (
+	PollMinOptions      = 2
+	PollMaxOptions      = 10
+	PollOptionMaxLength = 64
+	PollMinDuration     = time.Minute * 5
+	PollMaxDuration     = time.Hour * 24 * 30
+)
+
+type Poll struct {
+	MultipleChoice bool         `json:"multipleChoice"`
+	Options        []PollOption `json:"options"`
+	VotersCount    int          `json:"votersCount"`
+	ClosesAt       time.Time    `json:"closesAt"`
+	Closed         bool         `json:"closed"`
+	Voted          bool         `json:"voted"`
+}
+
+type PollOption struct {
+	ID         string `json:"id"`
+	Text       string `json:"text"`
+	VotesCount int    `json:"votesCount"`
+	Voted      bool   `json:"voted"`
+}
+
+type CreatePoll struct {
+	Options        []string  `json:"options"`
+	MultipleChoice bool      `json:"multipleChoice"`
+	ClosesAt       time.Time `json:"closesAt"`
+}
+
+func (in *CreatePoll) Validate() error {
+	if len(in.Options) < PollMinOptions || len(in.Options) > PollMaxOptions {
+		return errs.InvalidArgumentError("invalid poll options count")
+	}
+
+	seen := make(map[string]struct{}, len(in.Options))
+	for i, opt := range in.Options {
+		opt = textutil.SmartTrim(opt)
+
+		if opt == "" || utf8.RuneCountInString(opt) > PollOptionMaxLength {
+			return errs.InvalidArgumentError("invalid poll option")
+		}
+
+		if _, ok := seen[opt]; ok {
+			return errs.InvalidArgumentError("duplicated poll option")
+		}
+
+		seen[opt] = struct{}{}
+		in.Options[i] = opt
+	}
+
+	if d := time.Until(in.ClosesAt); d < PollMinDuration || d > PollMaxDuration {
+		return errs.InvalidArgumentError("invalid poll closes at")
+	}
+
+	return nil
+}
+
+type VotePoll struct {
+	PostID    string   `json:"-"`
+	OptionIDs []string `json:"optionIDs"`
+	userID    string
+}
+
+func (in *VotePoll) SetUserID(userID string) {
+	in.userID = userID
+}
+
+func (in VotePoll) UserID() string {
+	return in.userID
+}
+
+func (in *VotePoll) Validate() error {
+	if !ValidUUIDv4(in.PostID) {
+		return errs.InvalidArgumentError("invalid post ID")
+	}
+
+	if len(in.OptionIDs) == 0 || len(in.OptionIDs) > PollMaxOptions {
+		return errs.InvalidArgumentError("invalid poll option IDs")
+	}
+
+	seen := make(map[string]struct{}, len(in.OptionIDs))
+	for _, id := range in.OptionIDs {
+		if !ValidUUIDv4(id) {
+			return errs.InvalidArgumentError("invalid poll option ID")
+		}
+
+		if _, ok := seen[id]; ok {
+			return errs.InvalidArgumentError("duplicated poll option ID")
+		}
+
+		seen[id] = struct{}{}
+	}
+
+	return nil
+}
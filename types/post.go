@@ -15,20 +15,59 @@ const (
 	PostMaxMediaItems    = 15
 )
 
+// PostVisibility controls who can see a post.
+type PostVisibility string
+
+const (
+	PostVisibilityPublic    PostVisibility = "public"
+	PostVisibilityFollowers PostVisibility = "followers"
+	// PostVisibilityMentioned posts can be seen only by the mentioned users.
+	PostVisibilityMentioned PostVisibility = "mentioned"
+)
+
+func (v PostVisibility) IsValid() bool {
+	switch v {
+	case PostVisibilityPublic, PostVisibilityFollowers, PostVisibilityMentioned:
+		return true
+	default:
+		return false
+	}
+}
+
+func (v PostVisibility) String() string {
+	return string(v)
+}
+
 type Post struct {
-	ID            string     `json:"id"`
-	UserID        string     `json:"userID" db:"user_id"`
-	Content       string     `json:"content"`
-	SpoilerOf     *string    `json:"spoilerOf" db:"spoiler_of"`
-	NSFW          bool       `json:"nsfw"`
-	Media         []Media    `json:"media" db:"media"`
-	Reactions     []Reaction `json:"reactions"`
-	CommentsCount int        `json:"commentsCount" db:"comments_count"`
-	CreatedAt     time.Time  `json:"createdAt" db:"created_at"`
-	UpdatedAt     time.Time  `json:"updatedAt" db:"updated_at"`
-	User          *User      `json:"user,omitempty"`
-	Mine          bool       `json:"mine" db:"mine,omitempty"`
-	Subscribed    bool       `json:"subscribed" db:"subscribed,omitempty"`
+	ID            string         `json:"id"`
+	UserID        string         `json:"userID" db:"user_id"`
+	Content       string         `json:"content"`
+	Entities      []Entity       `json:"entities"`
+	SpoilerOf     *string        `json:"spoilerOf" db:"spoiler_of"`
+	NSFW          bool           `json:"nsfw"`
+	Media         []Media        `json:"media" db:"media"`
+	Reactions     []Reaction     `json:"reactions"`
+	CommentsCount int            `json:"commentsCount" db:"comments_count"`
+	RepostOfID    *string        `json:"repostOfID" db:"repost_of_id"`
+	RepostsCount  int            `json:"repostsCount" db:"reposts_count"`
+	Visibility    PostVisibility `json:"visibility"`
+	Edited        bool           `json:"edited"`
+	CreatedAt     time.Time      `json:"createdAt" db:"created_at"`
+	UpdatedAt     time.Time      `json:"updatedAt" db:"updated_at"`
+	User          *User          `json:"user,omitempty"`
+	RepostOf      *Post          `json:"repostOf,omitempty" db:"repost_of"`
+	Poll          *Poll          `json:"poll,omitempty" db:"poll"`
+	LinkPreview   *LinkPreview   `json:"linkPreview,omitempty" db:"link_preview"`
+	Mine          bool           `json:"mine" db:"mine,omitempty"`
+	Subscribed    bool           `json:"subscribed" db:"subscribed,omitempty"`
+	Bookmarked    bool           `json:"bookmarked" db:"bookmarked,omitempty"`
+	Filtered      bool           `json:"filtered" db:"-"`
+}
+
+// IsPlainRepost reports whether the post only shares another post
+// without adding any content of its own.
+func (p Post) IsPlainRepost() bool {
+	return p.RepostOfID != nil && p.Content == ""
 }
 
 func (p *Post) SetMediaPaths(base, bucket string) {
@@ -54,14 +93,26 @@ func (p *PostPreview) SetMediaPaths(base, bucket string) {
 	}
 }
 
+// RedactSpoilers hides inline spoilers from the preview content,
+// since previews show up outside the post, like in push notifications.
+func (p *PostPreview) RedactSpoilers() {
+	p.Content = textutil.RedactSpoilers(p.Content)
+}
+
 type CreatePost struct {
 	Content      string          `json:"content"`
 	SpoilerOf    *string         `json:"spoilerOf"`
 	NSFW         bool            `json:"nsfw"`
+	RepostOfID   *string         `json:"repostOfID"`
+	Poll         *CreatePoll     `json:"poll"`
+	Visibility   PostVisibility  `json:"visibility"`
 	MediaReaders []io.ReadSeeker `json:"-"`
 	userID       string
 	tags         []string
+	mentions     []string
+	entities     []Entity
 	media        []Media
+	createdAt    *time.Time
 }
 
 func (in *CreatePost) SetUserID(userID string) {
@@ -80,6 +131,24 @@ func (in CreatePost) Tags() []string {
 	return in.tags
 }
 
+// SetMentions sets the usernames mentioned in the content.
+// They make up the audience of [PostVisibilityMentioned] posts.
+func (in *CreatePost) SetMentions(mentions []string) {
+	in.mentions = mentions
+}
+
+func (in CreatePost) Mentions() []string {
+	return in.mentions
+}
+
+func (in *CreatePost) SetEntities(entities []Entity) {
+	in.entities = entities
+}
+
+func (in CreatePost) Entities() []Entity {
+	return in.entities
+}
+
 func (in *CreatePost) SetMedia(media []Media) {
 	in.media = media
 }
@@ -88,13 +157,52 @@ func (in CreatePost) Media() []Media {
 	return in.media
 }
 
+// SetCreatedAt backdates the post, like when importing it from another network.
+func (in *CreatePost) SetCreatedAt(t time.Time) {
+	in.createdAt = &t
+}
+
+func (in CreatePost) CreatedAt() *time.Time {
+	return in.createdAt
+}
+
+// IsPlainRepost reports whether the post to create only shares another post.
+// Otherwise, when RepostOfID is set, the post quotes the other one.
+func (in CreatePost) IsPlainRepost() bool {
+	return in.RepostOfID != nil && in.Content == ""
+}
+
 func (in *CreatePost) Validate() error {
 	in.Content = textutil.SmartTrim(in.Content)
 
+	if in.Visibility == "" {
+		in.Visibility = PostVisibilityPublic
+	}
+
+	if !in.Visibility.IsValid() {
+		return errs.InvalidArgumentError("invalid visibility")
+	}
+
+	if in.RepostOfID != nil && !ValidUUIDv4(*in.RepostOfID) {
+		return errs.InvalidArgumentError("invalid repost of ID")
+	}
+
+	if in.IsPlainRepost() {
+		if in.SpoilerOf != nil || in.NSFW || len(in.MediaReaders) != 0 || in.Poll != nil {
+			return errs.InvalidArgumentError("plain repost cannot have spoiler, nsfw, media or poll")
+		}
+
+		return nil
+	}
+
 	if in.Content == "" || utf8.RuneCountInString(in.Content) > PostContentMaxLength {
 		return errs.InvalidArgumentError("invalid content")
 	}
 
+	if !textutil.ValidSpoilers(in.Content) {
+		return errs.InvalidArgumentError("invalid inline spoiler")
+	}
+
 	if in.SpoilerOf != nil {
 		*in.SpoilerOf = textutil.SmartTrim(*in.SpoilerOf)
 
@@ -107,6 +215,12 @@ func (in *CreatePost) Validate() error {
 		return errs.InvalidArgumentError("too many media items")
 	}
 
+	if in.Poll != nil {
+		if err := in.Poll.Validate(); err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
 
@@ -136,6 +250,8 @@ func (in *ListPosts) Validate() error {
 		if *in.Tag == "" {
 			return errs.InvalidArgumentError("invalid tag")
 		}
+
+		in.Tag = new(textutil.NormalizeTag(*in.Tag))
 	}
 
 	return in.PageArgs.Validate()
@@ -160,6 +276,8 @@ type UpdatePost struct {
 	SpoilerOf *string `json:"spoilerOf"`
 	NSFW      *bool   `json:"nsfw"`
 	tags      []string
+	mentions  []string
+	entities  []Entity
 }
 
 func (in *UpdatePost) SetTags(tags []string) {
@@ -170,6 +288,22 @@ func (in UpdatePost) Tags() []string {
 	return in.tags
 }
 
+func (in *UpdatePost) SetMentions(mentions []string) {
+	in.mentions = mentions
+}
+
+func (in UpdatePost) Mentions() []string {
+	return in.mentions
+}
+
+func (in *UpdatePost) SetEntities(entities []Entity) {
+	in.entities = entities
+}
+
+func (in UpdatePost) Entities() []Entity {
+	return in.entities
+}
+
 func (in *UpdatePost) Validate() error {
 	if !ValidUUIDv4(in.ID) {
 		return errs.InvalidArgumentError("invalid post ID")
@@ -181,6 +315,10 @@ func (in *UpdatePost) Validate() error {
 		if *in.Content == "" || utf8.RuneCountInString(*in.Content) > PostContentMaxLength {
 			return errs.InvalidArgumentError("invalid content")
 		}
+
+		if !textutil.ValidSpoilers(*in.Content) {
+			return errs.InvalidArgumentError("invalid inline spoiler")
+		}
 	}
 
 	if in.SpoilerOf != nil {
@@ -196,7 +334,12 @@ func (in *UpdatePost) Validate() error {
 
 type UpdatedPost struct {
 	Content   string    `json:"content"`
+	Entities  []Entity  `json:"entities"`
 	SpoilerOf *string   `json:"spoilerOf" db:"spoiler_of"`
 	NSFW      bool      `json:"nsfw"`
+	Edited    bool      `json:"edited"`
 	UpdatedAt time.Time `json:"updatedAt" db:"updated_at"`
+
+	// PreviousContent before the update, used to diff mentions.
+	PreviousContent string `json:"-" db:"-"`
 }
@@ -0,0 +1,30 @@
+package types
+
+import (
+	"time"
+
+	"github.com/nicolasparada/go-errs"
+)
+
+// PostRevision holds the state of a post before it got edited.
+type PostRevision struct {
+	ID        string    `json:"id"`
+	PostID    string    `json:"postID" db:"post_id"`
+	Content   string    `json:"content"`
+	SpoilerOf *string   `json:"spoilerOf" db:"spoiler_of"`
+	NSFW      bool      `json:"nsfw"`
+	CreatedAt time.Time `json:"createdAt" db:"created_at"`
+}
+
+type ListPostRevisions struct {
+	PostID string
+	PageArgs
+}
+
+func (in *ListPostRevisions) Validate() error {
+	if !ValidUUIDv4(in.PostID) {
+		return errs.InvalidArgumentError("invalid post ID")
+	}
+
+	return in.PageArgs.Validate()
+}
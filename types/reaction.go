@@ -9,11 +9,13 @@ type ReactionKind string
 
 const (
 	ReactionKindEmoji ReactionKind = "emoji"
+	// ReactionKindCustom reactions hold the shortcode of a [CustomEmoji].
+	ReactionKindCustom ReactionKind = "custom"
 )
 
 func (k ReactionKind) IsValid() bool {
 	switch k {
-	case ReactionKindEmoji:
+	case ReactionKindEmoji, ReactionKindCustom:
 		return true
 	default:
 		return false
@@ -37,6 +39,14 @@ type Reaction struct {
 	Reaction string       `json:"reaction"`
 	Count    uint64       `json:"count"`
 	Reacted  *bool        `json:"reacted,omitempty"`
+	// ImageURL of custom reactions.
+	ImageURL *string `json:"imageURL,omitempty"`
+}
+
+func (r *Reaction) SetImageURL(baseURL, bucket string) {
+	if r.ImageURL != nil {
+		r.ImageURL = new(makeURL(baseURL, bucket, *r.ImageURL))
+	}
 }
 
 type ReactionInput struct {
@@ -73,6 +83,10 @@ func (in *TogglePostReaction) Validate() error {
 		if !emoji.IsValid(in.Reaction) {
 			return errs.InvalidArgumentError("invalid reaction")
 		}
+	case ReactionKindCustom:
+		if !ValidShortcode(in.Reaction) {
+			return errs.InvalidArgumentError("invalid reaction")
+		}
 	}
 
 	return nil
@@ -107,6 +121,10 @@ func (in *ToggleCommentReaction) Validate() error {
 		if !emoji.IsValid(in.Reaction) {
 			return errs.InvalidArgumentError("invalid reaction")
 		}
+	case ReactionKindCustom:
+		if !ValidShortcode(in.Reaction) {
+			return errs.InvalidArgumentError("invalid reaction")
+		}
 	}
 
 	return nil
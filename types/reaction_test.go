@@ -0,0 +1,81 @@
+package types_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/nakamauwu/nakama/types"
+	"github.com/nicolasparada/go-errs"
+)
+
+const testUUID = "7c8c5b9e-5a4d-4b9b-8e3f-2f1d6c9a0b1e"
+
+func TestValidShortcode(t *testing.T) {
+	tt := []struct {
+		given string
+		want  bool
+	}{
+		{given: "blobcat", want: true},
+		{given: "party_parrot", want: true},
+		{given: "eva01", want: true},
+		{given: "ok", want: true},
+		{given: "abcdefghijklmnopqrstuvwxyz012345", want: true},
+		{given: "", want: false},
+		{given: "a", want: false},
+		{given: "abcdefghijklmnopqrstuvwxyz0123456", want: false},
+		{given: ":blobcat:", want: false},
+		{given: "BlobCat", want: false},
+		{given: "blob-cat", want: false},
+		{given: "blob cat", want: false},
+		{given: "blobcat\n", want: false},
+		{given: "ñandú", want: false},
+	}
+	for _, tc := range tt {
+		t.Run(tc.given, func(t *testing.T) {
+			if got := types.ValidShortcode(tc.given); got != tc.want {
+				t.Errorf("ValidShortcode(%q) = %v, want %v", tc.given, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestToggleReaction_Validate(t *testing.T) {
+	tt := []struct {
+		name     string
+		kind     types.ReactionKind
+		reaction string
+		wantErr  bool
+	}{
+		{name: "emoji", kind: types.ReactionKindEmoji, reaction: "❤️"},
+		{name: "emoji_not_an_emoji", kind: types.ReactionKindEmoji, reaction: "blobcat", wantErr: true},
+		{name: "custom", kind: types.ReactionKindCustom, reaction: "blobcat"},
+		{name: "custom_with_colons", kind: types.ReactionKindCustom, reaction: ":blobcat:", wantErr: true},
+		{name: "custom_emoji", kind: types.ReactionKindCustom, reaction: "❤️", wantErr: true},
+		{name: "custom_empty", kind: types.ReactionKindCustom, reaction: "", wantErr: true},
+		{name: "unknown_kind", kind: "sticker", reaction: "blobcat", wantErr: true},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			post := types.TogglePostReaction{PostID: testUUID, Kind: tc.kind, Reaction: tc.reaction}
+			checkValidateErr(t, "post", post.Validate(), tc.wantErr)
+
+			comment := types.ToggleCommentReaction{CommentID: testUUID, Kind: tc.kind, Reaction: tc.reaction}
+			checkValidateErr(t, "comment", comment.Validate(), tc.wantErr)
+		})
+	}
+}
+
+func checkValidateErr(t *testing.T, name string, err error, wantErr bool) {
+	t.Helper()
+
+	if !wantErr {
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", name, err)
+		}
+		return
+	}
+
+	if !errors.Is(err, errs.InvalidArgument) {
+		t.Errorf("%s: error = %v, want invalid argument", name, err)
+	}
+}
@@ -0,0 +1,72 @@
+package types
+
+import (
+	"strings"
+	"unicode/utf8"
+
+	"github.com/nicolasparada/go-errs"
+)
+
+const SearchQueryMaxLength = 100
+
+// SearchKind is the kind of results to search for.
+type SearchKind string
+
+const (
+	SearchKindPost    SearchKind = "post"
+	SearchKindComment SearchKind = "comment"
+	SearchKindUser    SearchKind = "user"
+	SearchKindTag     SearchKind = "tag"
+)
+
+func (k SearchKind) IsValid() bool {
+	switch k {
+	case SearchKindPost, SearchKindComment, SearchKindUser, SearchKindTag:
+		return true
+	}
+	return false
+}
+
+type Search struct {
+	Query string
+	// Kind defaults to [SearchKindPost].
+	Kind SearchKind
+	// IncludeNSFW posts, and comments on them, in the results.
+	IncludeNSFW bool
+	PageArgs
+	viewerID *string
+}
+
+func (in *Search) SetViewerID(viewerID string) {
+	in.viewerID = &viewerID
+}
+
+func (in Search) ViewerID() *string {
+	return in.viewerID
+}
+
+func (in *Search) Validate() error {
+	in.Query = strings.TrimSpace(in.Query)
+	if in.Query == "" || utf8.RuneCountInString(in.Query) > SearchQueryMaxLength {
+		return errs.InvalidArgumentError("invalid search query")
+	}
+
+	if in.Kind == "" {
+		in.Kind = SearchKindPost
+	}
+
+	if !in.Kind.IsValid() {
+		return errs.InvalidArgumentError("invalid search kind")
+	}
+
+	return in.PageArgs.Validate()
+}
+
+// SearchResult holds one of its fields depending on the kind.
+type SearchResult struct {
+	Kind    SearchKind   `json:"kind"`
+	Post    *Post        `json:"post,omitempty"`
+	Comment *Comment     `json:"comment,omitempty"`
+	User    *UserProfile `json:"user,omitempty"`
+	Tag     *Tag         `json:"tag,omitempty"`
+}
@@ -0,0 +1,127 @@
+package types
+
+import (
+	"slices"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/nakamauwu/nakama/textutil"
+	"github.com/nicolasparada/go-errs"
+)
+
+const TagMaxLength = 100
+
+// ValidTag reports whether s, without the "#", could be collected from text as a tag.
+func ValidTag(s string) bool {
+	if s == "" || utf8.RuneCountInString(s) > TagMaxLength {
+		return false
+	}
+
+	for _, r := range s {
+		if !unicode.IsLetter(r) && !unicode.IsMark(r) && !unicode.IsNumber(r) && r != '_' {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Tag names are normalized with [textutil.NormalizeTag].
+// Display is the form in which the tag was first written.
+type Tag struct {
+	Name       string `json:"name"`
+	Display    string `json:"display"`
+	PostsCount int    `json:"postsCount" db:"posts_count"`
+}
+
+// TagPage gathers what is known about a tag.
+// PostsCount only counts posts visible to the viewer.
+type TagPage struct {
+	Tag
+	FirstSeenAt      time.Time  `json:"firstSeenAt" db:"first_seen_at"`
+	FollowedByViewer bool       `json:"followedByViewer" db:"followed_by_viewer"`
+	RelatedTags      []Tag      `json:"relatedTags" db:"-"`
+	Posts            Page[Post] `json:"posts" db:"-"`
+}
+
+type RetrieveTagPage struct {
+	Tag string
+	PageArgs
+	viewerID *string
+}
+
+func (in *RetrieveTagPage) SetViewerID(userID string) {
+	in.viewerID = &userID
+}
+
+func (in RetrieveTagPage) ViewerID() *string {
+	return in.viewerID
+}
+
+func (in *RetrieveTagPage) Validate() error {
+	in.Tag = strings.TrimPrefix(strings.TrimSpace(in.Tag), "#")
+	if !ValidTag(in.Tag) {
+		return errs.InvalidArgumentError("invalid tag")
+	}
+
+	in.Tag = textutil.NormalizeTag(in.Tag)
+
+	return in.PageArgs.Validate()
+}
+
+// TrendingWindow is the time span over which tag usage is measured.
+type TrendingWindow string
+
+const (
+	TrendingWindowHour TrendingWindow = "1h"
+	TrendingWindowDay  TrendingWindow = "24h"
+	TrendingWindowWeek TrendingWindow = "7d"
+)
+
+// TrendingWindows lists all the windows, shortest first.
+var TrendingWindows = []TrendingWindow{TrendingWindowHour, TrendingWindowDay, TrendingWindowWeek}
+
+func (w TrendingWindow) IsValid() bool {
+	return slices.Contains(TrendingWindows, w)
+}
+
+func (w TrendingWindow) Duration() time.Duration {
+	switch w {
+	case TrendingWindowHour:
+		return time.Hour
+	case TrendingWindowWeek:
+		return time.Hour * 24 * 7
+	default:
+		return time.Hour * 24
+	}
+}
+
+// TrendingTag is a tag whose usage grew within the window.
+// Score favors tags used by many distinct authors
+// over the ones repeated by a few.
+type TrendingTag struct {
+	Name         string  `json:"name"`
+	Display      string  `json:"display"`
+	Score        float64 `json:"score"`
+	AuthorsCount int     `json:"authorsCount" db:"authors_count"`
+	PostsCount   int     `json:"postsCount" db:"posts_count"`
+}
+
+type ListTrendingTags struct {
+	// Window defaults to [TrendingWindowDay].
+	Window TrendingWindow
+}
+
+func (in *ListTrendingTags) Validate() error {
+	if in.Window == "" {
+		in.Window = TrendingWindowDay
+	}
+
+	if !in.Window.IsValid() {
+		return errs.InvalidArgumentError("invalid trending window")
+	}
+
+	return nil
+}
@@ -0,0 +1,27 @@
+package types
+
+import (
+	"time"
+)
+
+type TagFollow struct {
+	Tag       string    `json:"tag"`
+	CreatedAt time.Time `json:"createdAt" db:"created_at"`
+}
+
+type ListTagFollows struct {
+	PageArgs
+	userID string
+}
+
+func (in *ListTagFollows) SetUserID(userID string) {
+	in.userID = userID
+}
+
+func (in ListTagFollows) UserID() string {
+	return in.userID
+}
+
+func (in *ListTagFollows) Validate() error {
+	return in.PageArgs.Validate()
+}
@@ -2,16 +2,31 @@ package types
 
 import "time"
 
+// TimelineItemReason tells why a post made it into a timeline.
+type TimelineItemReason string
+
+const (
+	TimelineItemReasonOwn     TimelineItemReason = "own"
+	TimelineItemReasonFollow  TimelineItemReason = "follow"
+	TimelineItemReasonMention TimelineItemReason = "mention"
+	// TimelineItemReasonTag posts come from a followed tag, set in ReasonTag.
+	TimelineItemReasonTag TimelineItemReason = "tag"
+)
+
 type TimelineItem struct {
-	ID     string `json:"timelineItemID" db:"timeline_item_id"`
-	UserID string `json:"-" db:"user_id"`
-	PostID string `json:"-" db:"post_id"`
+	ID        string             `json:"timelineItemID" db:"timeline_item_id"`
+	UserID    string             `json:"-" db:"user_id"`
+	PostID    string             `json:"-" db:"post_id"`
+	Reason    TimelineItemReason `json:"reason"`
+	ReasonTag *string            `json:"reasonTag,omitempty" db:"reason_tag"`
 	Post
 }
 
 type CreatedTimelineItem struct {
 	TimelineItemID string    `json:"timelineItemID" db:"timeline_item_id"`
 	PostID         string    `json:"postID" db:"post_id"`
+	RepostOfID     *string   `json:"repostOfID" db:"repost_of_id"`
+	Poll           *Poll     `json:"poll,omitempty" db:"-"`
 	CreatedAt      time.Time `json:"createdAt" db:"created_at"`
 }
 
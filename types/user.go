@@ -21,16 +21,21 @@ func (u *User) SetAvatarURL(baseURL, bucket string) {
 
 type UserProfile struct {
 	User
-	Email            string  `json:"email,omitempty"`
-	CoverURL         *string `json:"coverURL" db:"cover"`
-	Bio              *string `json:"bio"`
-	Waifu            *string `json:"waifu"`
-	Husbando         *string `json:"husbando"`
-	FollowersCount   int     `json:"followersCount" db:"followers_count"`
-	FolloweesCount   int     `json:"followeesCount" db:"followees_count"`
-	IsMe             bool    `json:"isMe" db:"is_me"`
-	FollowedByViewer bool    `json:"followedByViewer" db:"followed_by_viewer"`
-	FollowsViewer    bool    `json:"followsViewer" db:"follows_viewer"`
+	Email                   string  `json:"email,omitempty"`
+	CoverURL                *string `json:"coverURL" db:"cover"`
+	Bio                     *string `json:"bio"`
+	Waifu                   *string `json:"waifu"`
+	Husbando                *string `json:"husbando"`
+	FollowersCount          int     `json:"followersCount" db:"followers_count"`
+	FolloweesCount          int     `json:"followeesCount" db:"followees_count"`
+	Private                 bool    `json:"private"`
+	IsMe                    bool    `json:"isMe" db:"is_me"`
+	FollowedByViewer        bool    `json:"followedByViewer" db:"followed_by_viewer"`
+	FollowRequestedByViewer bool    `json:"followRequestedByViewer" db:"follow_requested_by_viewer"`
+	FollowsViewer           bool    `json:"followsViewer" db:"follows_viewer"`
+	BlockedByViewer         bool    `json:"blockedByViewer" db:"blocked_by_viewer"`
+	MutedByViewer           bool    `json:"mutedByViewer" db:"muted_by_viewer"`
+	PinnedPosts             []Post  `json:"pinnedPosts,omitempty" db:"-"`
 }
 
 func (u *UserProfile) SetCoverURL(base, bucket string) {
@@ -45,6 +50,7 @@ type CreateUser struct {
 
 type ToggledFollow struct {
 	FollowedByViewer bool `json:"followedByViewer"`
+	FollowRequested  bool `json:"followRequested"`
 	FollowersCount   uint `json:"followersCount"`
 }
 
@@ -169,7 +175,10 @@ type UpdateUser struct {
 	Bio      *string `json:"bio"`
 	Waifu    *string `json:"waifu"`
 	Husbando *string `json:"husbando"`
-	userID   string
+	// Private accounts require approving followers.
+	// Going public approves all the pending follow requests.
+	Private *bool `json:"private"`
+	userID  string
 }
 
 func (u *UpdateUser) SetUserID(userID string) {
@@ -0,0 +1,312 @@
+// Package unfurl extracts link preview metadata out of web pages
+// from their OpenGraph and Twitter card tags.
+package unfurl
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+
+	"github.com/nakamauwu/nakama/ssrf"
+)
+
+const (
+	// maxBodySize read from a page. Metadata lives in the head,
+	// so there is no need to go through the whole document.
+	maxBodySize  = 512 << 10
+	maxRedirects = 5
+	userAgent    = "Mozilla/5.0 (compatible; nakama/1.0; +https://nakama.social)"
+)
+
+var (
+	ErrNotHTML           = errors.New("unfurl: not an html page")
+	ErrNotJSON           = errors.New("unfurl: not a json document")
+	ErrTooManyRedirects  = errors.New("unfurl: too many redirects")
+	ErrUnexpectedStatus  = errors.New("unfurl: unexpected status code")
+	ErrUnsupportedScheme = errors.New("unfurl: unsupported url scheme")
+)
+
+// StatusError is returned for responses other than 200 OK.
+// It matches [ErrUnexpectedStatus].
+type StatusError struct {
+	StatusCode int
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("%s: %d", ErrUnexpectedStatus, e.StatusCode)
+}
+
+func (e *StatusError) Is(target error) bool {
+	return target == ErrUnexpectedStatus
+}
+
+// IsPermanent reports whether the error comes from the page itself,
+// so fetching it again later won't help.
+// Network errors, timeouts and server errors are transient.
+func IsPermanent(err error) bool {
+	if errors.Is(err, ErrNotHTML) ||
+		errors.Is(err, ErrNotJSON) ||
+		errors.Is(err, ErrTooManyRedirects) ||
+		errors.Is(err, ErrUnsupportedScheme) ||
+		errors.Is(err, ssrf.ErrNonPublicAddress) {
+		return true
+	}
+
+	var statusErr *StatusError
+	if errors.As(err, &statusErr) {
+		switch statusErr.StatusCode {
+		case http.StatusRequestTimeout, http.StatusTooManyRequests:
+			return false
+		}
+
+		return statusErr.StatusCode < 500
+	}
+
+	return false
+}
+
+// Metadata of a web page. Empty fields were not found.
+type Metadata struct {
+	// URL of the page after following redirects.
+	// The og:url tag is not trusted since it could point anywhere.
+	URL         string
+	Title       string
+	Description string
+	ImageURL    string
+	VideoURL    string
+	SiteName    string
+}
+
+// IsZero reports whether nothing worth a preview was found.
+func (m Metadata) IsZero() bool {
+	return m.Title == "" && m.Description == "" && m.ImageURL == ""
+}
+
+type Unfurler struct {
+	client *http.Client
+}
+
+// New unfurler using the given client. When nil, [NewClient] is used.
+func New(client *http.Client) *Unfurler {
+	if client == nil {
+		client = NewClient()
+	}
+	return &Unfurler{client: client}
+}
+
+// NewClient returns an HTTP client that refuses to connect to non public addresses
+// so posted links can't be used to reach internal services. See [ssrf.Dialer].
+func NewClient() *http.Client {
+	return &http.Client{
+		Transport:     ssrf.NewTransport(),
+		Timeout:       time.Second * 10,
+		CheckRedirect: checkRedirect,
+	}
+}
+
+func checkRedirect(req *http.Request, via []*http.Request) error {
+	if len(via) >= maxRedirects {
+		return ErrTooManyRedirects
+	}
+
+	if req.URL.Scheme != "http" && req.URL.Scheme != "https" {
+		return ErrUnsupportedScheme
+	}
+
+	return nil
+}
+
+// Unfurl fetches the page at the given URL and extracts its metadata.
+func (u *Unfurler) Unfurl(ctx context.Context, rawURL string) (Metadata, error) {
+	var out Metadata
+
+	target, err := url.Parse(rawURL)
+	if err != nil {
+		return out, fmt.Errorf("unfurl: parse url: %w", err)
+	}
+
+	if target.Scheme != "http" && target.Scheme != "https" {
+		return out, ErrUnsupportedScheme
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, target.String(), nil)
+	if err != nil {
+		return out, fmt.Errorf("unfurl: create request: %w", err)
+	}
+
+	req.Header.Set("User-Agent", userAgent)
+	req.Header.Set("Accept", "text/html,application/xhtml+xml")
+
+	resp, err := u.client.Do(req)
+	if err != nil {
+		return out, fmt.Errorf("unfurl: fetch: %w", err)
+	}
+
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return out, &StatusError{StatusCode: resp.StatusCode}
+	}
+
+	mediaType, _, err := mime.ParseMediaType(resp.Header.Get("Content-Type"))
+	if err != nil || (mediaType != "text/html" && mediaType != "application/xhtml+xml") {
+		return out, ErrNotHTML
+	}
+
+	// after redirects, relative URLs resolve against the final location.
+	out, err = Parse(io.LimitReader(resp.Body, maxBodySize), resp.Request.URL)
+	if err != nil {
+		return out, err
+	}
+
+	return out, nil
+}
+
+// OEmbed response. Only the fields used to embed rich content.
+type OEmbed struct {
+	Type string `json:"type"`
+	HTML string `json:"html"`
+}
+
+// OEmbed fetches the oEmbed JSON document at the given provider endpoint.
+func (u *Unfurler) OEmbed(ctx context.Context, endpoint string) (OEmbed, error) {
+	var out OEmbed
+
+	target, err := url.Parse(endpoint)
+	if err != nil {
+		return out, fmt.Errorf("unfurl: parse oembed endpoint: %w", err)
+	}
+
+	if target.Scheme != "http" && target.Scheme != "https" {
+		return out, ErrUnsupportedScheme
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, target.String(), nil)
+	if err != nil {
+		return out, fmt.Errorf("unfurl: create oembed request: %w", err)
+	}
+
+	req.Header.Set("User-Agent", userAgent)
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := u.client.Do(req)
+	if err != nil {
+		return out, fmt.Errorf("unfurl: fetch oembed: %w", err)
+	}
+
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return out, &StatusError{StatusCode: resp.StatusCode}
+	}
+
+	mediaType, _, err := mime.ParseMediaType(resp.Header.Get("Content-Type"))
+	if err != nil || (mediaType != "application/json" && mediaType != "text/json") {
+		return out, ErrNotJSON
+	}
+
+	if err := json.NewDecoder(io.LimitReader(resp.Body, maxBodySize)).Decode(&out); err != nil {
+		return out, fmt.Errorf("unfurl: decode oembed: %w", err)
+	}
+
+	return out, nil
+}
+
+// Parse extracts the metadata out of an HTML document.
+// OpenGraph tags take precedence over Twitter card tags,
+// which take precedence over the document title and description.
+// Relative URLs are resolved against base.
+func Parse(r io.Reader, base *url.URL) (Metadata, error) {
+	meta := map[string]string{}
+	var title string
+
+	z := html.NewTokenizer(r)
+	for {
+		tt := z.Next()
+		if tt == html.ErrorToken {
+			if err := z.Err(); err != io.EOF {
+				return Metadata{}, fmt.Errorf("unfurl: parse html: %w", err)
+			}
+			break
+		}
+
+		tok := z.Token()
+		if tok.DataAtom == atom.Body || (tt == html.EndTagToken && tok.DataAtom == atom.Head) {
+			break
+		}
+
+		if tt == html.StartTagToken && tok.DataAtom == atom.Title && title == "" {
+			if z.Next() == html.TextToken {
+				title = string(z.Text())
+			}
+			continue
+		}
+
+		if (tt != html.StartTagToken && tt != html.SelfClosingTagToken) || tok.DataAtom != atom.Meta {
+			continue
+		}
+
+		var key, content string
+		for _, attr := range tok.Attr {
+			switch strings.ToLower(attr.Key) {
+			case "property", "name":
+				if key == "" {
+					key = strings.ToLower(strings.TrimSpace(attr.Val))
+				}
+			case "content":
+				content = strings.TrimSpace(attr.Val)
+			}
+		}
+
+		if _, ok := meta[key]; key != "" && content != "" && !ok {
+			meta[key] = content
+		}
+	}
+
+	out := Metadata{
+		URL:         base.String(),
+		Title:       first(meta["og:title"], meta["twitter:title"], title),
+		Description: first(meta["og:description"], meta["twitter:description"], meta["description"]),
+		SiteName:    meta["og:site_name"],
+	}
+	out.Title = strings.Join(strings.Fields(out.Title), " ")
+	out.Description = strings.Join(strings.Fields(out.Description), " ")
+
+	out.ImageURL = resolveURL(base, first(meta["og:image:secure_url"], meta["og:image"], meta["og:image:url"], meta["twitter:image"], meta["twitter:image:src"]))
+	out.VideoURL = resolveURL(base, first(meta["og:video:secure_url"], meta["og:video"], meta["og:video:url"], meta["twitter:player:stream"]))
+
+	return out, nil
+}
+
+// resolveURL against base, keeping only http and https URLs.
+func resolveURL(base *url.URL, s string) string {
+	if s == "" {
+		return ""
+	}
+
+	u, err := base.Parse(s)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") {
+		return ""
+	}
+
+	return u.String()
+}
+
+func first(ss ...string) string {
+	for _, s := range ss {
+		if s != "" {
+			return s
+		}
+	}
+	return ""
+}
@@ -0,0 +1,254 @@
+package unfurl_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/nakamauwu/nakama/ssrf"
+	"github.com/nakamauwu/nakama/unfurl"
+)
+
+func TestParse(t *testing.T) {
+	base, _ := url.Parse("https://example.org/posts/1")
+
+	tt := []struct {
+		name  string
+		given string
+		want  unfurl.Metadata
+	}{
+		{
+			name: "opengraph",
+			given: `<html><head>
+				<meta property="og:title" content="Evangelion">
+				<meta property="og:description" content="Get in the robot.">
+				<meta property="og:image" content="https://cdn.example.org/eva.png">
+				<meta property="og:site_name" content="Example">
+				<meta name="twitter:title" content="Ignored">
+				<title>Ignored too</title>
+			</head><body></body></html>`,
+			want: unfurl.Metadata{
+				URL:         "https://example.org/posts/1",
+				Title:       "Evangelion",
+				Description: "Get in the robot.",
+				ImageURL:    "https://cdn.example.org/eva.png",
+				SiteName:    "Example",
+			},
+		},
+		{
+			name: "twitter_card",
+			given: `<head>
+				<meta name="twitter:title" content="Evangelion">
+				<meta name="twitter:description" content="Get in the robot.">
+				<meta name="twitter:image" content="/eva.png">
+			</head>`,
+			want: unfurl.Metadata{
+				URL:         "https://example.org/posts/1",
+				Title:       "Evangelion",
+				Description: "Get in the robot.",
+				ImageURL:    "https://example.org/eva.png",
+			},
+		},
+		{
+			name: "video",
+			given: `<head>
+				<meta property="og:title" content="Evangelion">
+				<meta property="og:video" content="/eva.mp4">
+				<meta property="og:video:secure_url" content="https://cdn.example.org/eva.mp4">
+			</head>`,
+			want: unfurl.Metadata{
+				URL:      "https://example.org/posts/1",
+				Title:    "Evangelion",
+				VideoURL: "https://cdn.example.org/eva.mp4",
+			},
+		},
+		{
+			name: "document",
+			given: `<head>
+				<title>  Evangelion
+					Wiki  </title>
+				<meta name="description" content="Get in the robot.">
+			</head>`,
+			want: unfurl.Metadata{
+				URL:         "https://example.org/posts/1",
+				Title:       "Evangelion Wiki",
+				Description: "Get in the robot.",
+			},
+		},
+		{
+			name: "untrusted_url",
+			given: `<head>
+				<meta property="og:title" content="Evangelion">
+				<meta property="og:url" content="https://phishing.example.com">
+			</head>`,
+			want: unfurl.Metadata{
+				URL:   "https://example.org/posts/1",
+				Title: "Evangelion",
+			},
+		},
+		{
+			name: "unsupported_image_scheme",
+			given: `<head>
+				<meta property="og:title" content="Evangelion">
+				<meta property="og:image" content="javascript:alert(1)">
+			</head>`,
+			want: unfurl.Metadata{
+				URL:   "https://example.org/posts/1",
+				Title: "Evangelion",
+			},
+		},
+		{
+			name: "body_is_ignored",
+			given: `<head></head><body>
+				<meta property="og:title" content="Ignored">
+			</body>`,
+			want: unfurl.Metadata{
+				URL: "https://example.org/posts/1",
+			},
+		},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := unfurl.Parse(strings.NewReader(tc.given), base)
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+
+			if got != tc.want {
+				t.Errorf("Parse() = %+v, want %+v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestUnfurler_Unfurl(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/page", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		_, _ = w.Write([]byte(`<head><meta property="og:title" content="Evangelion"><meta property="og:image" content="/eva.png"></head>`))
+	})
+	mux.HandleFunc("/redirect", func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "/page", http.StatusFound)
+	})
+	mux.HandleFunc("/loop", func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "/loop", http.StatusFound)
+	})
+	mux.HandleFunc("/unavailable", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	})
+	mux.HandleFunc("/image", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "image/png")
+		_, _ = w.Write([]byte("\x89PNG"))
+	})
+
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	client := srv.Client()
+	client.CheckRedirect = unfurl.NewClient().CheckRedirect
+	u := unfurl.New(client)
+	ctx := context.Background()
+
+	t.Run("page", func(t *testing.T) {
+		got, err := u.Unfurl(ctx, srv.URL+"/page")
+		if err != nil {
+			t.Fatalf("Unfurl() error = %v", err)
+		}
+
+		want := unfurl.Metadata{URL: srv.URL + "/page", Title: "Evangelion", ImageURL: srv.URL + "/eva.png"}
+		if got != want {
+			t.Errorf("Unfurl() = %+v, want %+v", got, want)
+		}
+	})
+
+	t.Run("redirect", func(t *testing.T) {
+		got, err := u.Unfurl(ctx, srv.URL+"/redirect")
+		if err != nil {
+			t.Fatalf("Unfurl() error = %v", err)
+		}
+
+		if want := srv.URL + "/page"; got.URL != want {
+			t.Errorf("Unfurl().URL = %q, want %q", got.URL, want)
+		}
+	})
+
+	tt := []struct {
+		name          string
+		path          string
+		want          error
+		wantPermanent bool
+	}{
+		{name: "not_html", path: "/image", want: unfurl.ErrNotHTML, wantPermanent: true},
+		{name: "not_found", path: "/nope", want: unfurl.ErrUnexpectedStatus, wantPermanent: true},
+		{name: "unavailable", path: "/unavailable", want: unfurl.ErrUnexpectedStatus, wantPermanent: false},
+		{name: "redirect_loop", path: "/loop", want: unfurl.ErrTooManyRedirects, wantPermanent: true},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := u.Unfurl(ctx, srv.URL+tc.path)
+			if !errors.Is(err, tc.want) {
+				t.Errorf("Unfurl() error = %v, want %v", err, tc.want)
+			}
+
+			if got := unfurl.IsPermanent(err); got != tc.wantPermanent {
+				t.Errorf("IsPermanent(%v) = %v, want %v", err, got, tc.wantPermanent)
+			}
+		})
+	}
+
+	t.Run("unsupported_scheme", func(t *testing.T) {
+		_, err := u.Unfurl(ctx, "file:///etc/passwd")
+		if !errors.Is(err, unfurl.ErrUnsupportedScheme) {
+			t.Errorf("Unfurl() error = %v, want %v", err, unfurl.ErrUnsupportedScheme)
+		}
+	})
+}
+
+func TestUnfurler_OEmbed(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/oembed", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"type":"rich","html":"<blockquote>Get in the robot.</blockquote>","width":600}`))
+	})
+	mux.HandleFunc("/page", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		_, _ = w.Write([]byte(`<head></head>`))
+	})
+
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	u := unfurl.New(srv.Client())
+	ctx := context.Background()
+
+	got, err := u.OEmbed(ctx, srv.URL+"/oembed")
+	if err != nil {
+		t.Fatalf("OEmbed() error = %v", err)
+	}
+
+	want := unfurl.OEmbed{Type: "rich", HTML: "<blockquote>Get in the robot.</blockquote>"}
+	if got != want {
+		t.Errorf("OEmbed() = %+v, want %+v", got, want)
+	}
+
+	_, err = u.OEmbed(ctx, srv.URL+"/page")
+	if !errors.Is(err, unfurl.ErrNotJSON) {
+		t.Errorf("OEmbed() error = %v, want %v", err, unfurl.ErrNotJSON)
+	}
+}
+
+func TestNewClient(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Error("non public address reached")
+	}))
+	defer srv.Close()
+
+	_, err := unfurl.New(nil).Unfurl(context.Background(), srv.URL)
+	if !errors.Is(err, ssrf.ErrNonPublicAddress) {
+		t.Errorf("Unfurl() error = %v, want %v", err, ssrf.ErrNonPublicAddress)
+	}
+}
@@ -25,6 +25,10 @@ import "./toast-item.js"
  * @typedef {import("../types.js").Media} Media
  */
 
+/**
+ * @typedef {import("../types.js").Embed} Embed
+ */
+
 /**
  * @typedef {import("../types.js").Comment} Comment
  */
@@ -846,14 +850,13 @@ function MediaScroller({ items: mediaItems }) {
 
                 if (url.hostname === "bsky.app" && url.pathname.includes("/post/")) {
                     try {
-                        const endpoint = "https://embed.bsky.app/oembed?url=" + encodeURIComponent(url.toString())
-                        const resp = await fetch("/api/proxy?target=" + encodeURIComponent(endpoint))
+                        const resp = await fetch("/api/embed?url=" + encodeURIComponent(url.toString()))
                         if (!resp.ok) {
                             continue
                         }
 
-                        const json = await resp.json()
-                        if (typeof json === "object" && json !== null && typeof json.html === "string") {
+                        const json = /** @type {Embed} */ (await resp.json())
+                        if (json.kind === "html" && typeof json.html === "string") {
                             const div = document.createElement("div")
                             div.innerHTML = json.html
                             const script = div.querySelector("script")
@@ -1290,6 +1293,7 @@ function findCoubVideoID(url) {
 }
 
 /**
+ * resolveTenorMedia asks the server for the GIF behind a Tenor page.
  * @param {URL} url
  * @returns {Promise<{kind:"image"|"video", src:string}|null>}
  */
@@ -1299,53 +1303,14 @@ async function resolveTenorMedia(url) {
     }
 
     try {
-        const resp = await fetch("/api/proxy?target=" + encodeURIComponent(url.toString()), {
-            headers: {
-                accept: "text/html,application/xhtml+xml",
-            },
-        })
+        const resp = await fetch("/api/embed?url=" + encodeURIComponent(url.toString()))
         if (!resp.ok) {
             return null
         }
 
-        const ct = resp.headers.get("content-type")
-        if (ct !== null && !ct.includes("text/html")) {
-            return null
-        }
-
-        const text = await resp.text()
-        const doc = new DOMParser().parseFromString(text, "text/html")
-
-        const imageURL = findMetaContent(doc, [
-            'meta[property="og:image"]',
-            'meta[name="twitter:image"]',
-            'meta[property="og:image:secure_url"]',
-        ])
-        if (imageURL !== null) {
-            return { kind: "image", src: imageURL }
-        }
-
-        const videoURL = findMetaContent(doc, [
-            'meta[property="og:video:secure_url"]',
-            'meta[property="og:video"]',
-            'meta[name="twitter:player:stream"]',
-        ])
-        if (videoURL !== null) {
-            return { kind: "video", src: videoURL }
-        }
-
-        const imageElementURL = findAttrContent(
-            doc,
-            ['img[src*="tenor.com"]', 'img[src*="media.tenor.com"]', 'img[src*="media1.tenor.com"]'],
-            "src",
-        )
-        if (imageElementURL !== null) {
-            return { kind: "image", src: imageElementURL }
-        }
-
-        const videoElementURL = findAttrContent(doc, ['source[src*="tenor.com"]', 'video[src*="tenor.com"]'], "src")
-        if (videoElementURL !== null) {
-            return { kind: "video", src: videoElementURL }
+        const json = /** @type {Embed} */ (await resp.json())
+        if ((json.kind === "image" || json.kind === "video") && typeof json.url === "string") {
+            return { kind: json.kind, src: json.url }
         }
     } catch (err) {
         console.error("failed to resolve tenor media", err)
@@ -1354,41 +1319,6 @@ async function resolveTenorMedia(url) {
     return null
 }
 
-/**
- * @param {Document} doc
- * @param {string[]} selectors
- * @returns {string|null}
- */
-function findMetaContent(doc, selectors) {
-    for (const selector of selectors) {
-        const el = doc.querySelector(selector)
-        const content = el?.getAttribute("content")
-        if (content !== null && content !== undefined && content !== "") {
-            return content
-        }
-    }
-
-    return null
-}
-
-/**
- * @param {Document} doc
- * @param {string[]} selectors
- * @param {string} attr
- * @returns {string|null}
- */
-function findAttrContent(doc, selectors, attr) {
-    for (const selector of selectors) {
-        const el = doc.querySelector(selector)
-        const value = el?.getAttribute(attr)
-        if (value !== null && value !== undefined && value !== "") {
-            return value
-        }
-    }
-
-    return null
-}
-
 /**
  * @param {URL} url
  */
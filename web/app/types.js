@@ -88,6 +88,13 @@
  * @prop {boolean} subscribed
  */
 
+/**
+ * @typedef {object} Embed
+ * @prop {"image"|"video"|"html"} kind
+ * @prop {string=} url
+ * @prop {string=} html
+ */
+
 /**
  * @typedef {object} Media
  * @prop {string} path